@@ -0,0 +1,497 @@
+/*
+Package checkdigit computes and validates check digits of integral Quad numbers and of identifiers like IBAN.
+
+The following algorithms are available:
+
+	Luhn                   credit card numbers, IMEI, ...
+	ISO 7064               MOD 11-2, MOD 37-2, MOD 97-10, MOD 661-26, MOD 1271-36 (pure systems), MOD 11,10, MOD 27,26, MOD 37,36 (hybrid systems)
+	IBAN                   ISO 13616, which uses ISO 7064 MOD 97-10
+	RF creditor reference  ISO 11649, which uses ISO 7064 MOD 97-10
+
+A Quad holds 34 exact digits, which is enough for any IBAN or RF creditor reference after their letters have been replaced by numbers, when the MOD 97 is computed by parts.
+
+Errors are returned as decnum.QuadError:
+
+	InvalidOperation       the Quad is not a non-negative integer, or is Infinite or NaN
+	ConversionSyntax       the string contains an invalid character, or has an invalid length
+*/
+package checkdigit
+
+import (
+	"strings"
+
+	"github.com/rin01/decnum"
+)
+
+var (
+	errInvalid = decnum.QuadError(decnum.InvalidOperation) // Quad is not a non-negative integer
+	errSyntax  = decnum.QuadError(decnum.ConversionSyntax) // invalid character or length in string
+)
+
+const (
+	digits       = "0123456789"
+	letters      = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	alphanumeric = digits + letters
+)
+
+// integerDigits returns the digits of n, without leading zeros.
+// n must be a non-negative integer. 0 gives "0".
+//
+//      123.00     gives   "123"
+//      12E+3      gives   "12000"
+//
+func integerDigits(n decnum.Quad) (string, error) {
+	var (
+		bcd      [decnum.DecquadPmax]byte
+		exp      int32
+		negative bool
+		err      error
+		buff     []byte
+	)
+
+	if bcd, exp, negative, err = n.ToBCD(); err != nil {
+		return "", errInvalid
+	}
+
+	if negative {
+		return "", errInvalid
+	}
+
+	i := 0
+	for i < len(bcd)-1 && bcd[i] == 0 { // skip leading zeros, but keep at least one digit
+		i++
+	}
+
+	coefficient := bcd[i:]
+
+	for exp < 0 { // fractional digits must be 0, and are discarded
+		last := len(coefficient) - 1
+		if coefficient[last] != 0 {
+			return "", errInvalid
+		}
+		if last == 0 { // all digits are 0
+			break
+		}
+		coefficient = coefficient[:last]
+		exp++
+	}
+
+	size := len(coefficient)
+	if exp > 0 {
+		size += int(exp)
+	}
+
+	buff = make([]byte, 0, size)
+
+	for _, d := range coefficient {
+		buff = append(buff, '0'+d)
+	}
+
+	if coefficient[0] != 0 {
+		for ; exp > 0; exp-- {
+			buff = append(buff, '0')
+		}
+	}
+
+	return string(buff), nil
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                                 Luhn                                 */
+/*                                                                      */
+/************************************************************************/
+
+// luhnSum returns the Luhn sum of s, a string of digits.
+// If doubleLast is true, the last digit is doubled, then every second digit going left.
+//
+func luhnSum(s string, doubleLast bool) int {
+	var sum int
+
+	double := doubleLast
+
+	for i := len(s) - 1; i >= 0; i-- {
+		d := int(s[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum
+}
+
+// Luhn returns the Luhn check digit of n, which must be a non-negative integer.
+//
+//      Luhn(7992739871) is 3, and the number with check digit is 79927398713.
+//
+func Luhn(n decnum.Quad) (int, error) {
+	var (
+		s   string
+		err error
+	)
+
+	if s, err = integerDigits(n); err != nil {
+		return 0, err
+	}
+
+	return (10 - luhnSum(s, true)%10) % 10, nil
+}
+
+// ValidLuhn returns true if the last digit of n is the Luhn check digit of the other digits.
+// n must be a non-negative integer.
+//
+func ValidLuhn(n decnum.Quad) (bool, error) {
+	var (
+		s   string
+		err error
+	)
+
+	if s, err = integerDigits(n); err != nil {
+		return false, err
+	}
+
+	return luhnSum(s, false)%10 == 0, nil
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                                ISO 7064                              */
+/*                                                                      */
+/************************************************************************/
+
+// ISO7064 describes a check character system of ISO 7064.
+//
+// Use the predefined systems Mod11_2, Mod37_2, Mod97_10, Mod661_26, Mod1271_36, Mod11_10, Mod27_26 and Mod37_36.
+//
+type ISO7064 struct {
+	name       string
+	modulus    int    // M. For hybrid systems, the computation also uses M+1.
+	radix      int    // for pure systems only
+	hybrid     bool   // hybrid system MOD M+1,M, else pure system MOD M-r
+	alphabet   string // characters of the string to protect, in order of value
+	checkChars string // characters used for the check, in order of value
+	checkLen   int    // number of check characters, 1 or 2
+}
+
+var (
+	Mod11_2    = ISO7064{"ISO 7064 MOD 11-2", 11, 2, false, digits, digits + "X", 1}
+	Mod37_2    = ISO7064{"ISO 7064 MOD 37-2", 37, 2, false, alphanumeric, alphanumeric + "*", 1}
+	Mod97_10   = ISO7064{"ISO 7064 MOD 97-10", 97, 10, false, digits, digits, 2}
+	Mod661_26  = ISO7064{"ISO 7064 MOD 661-26", 661, 26, false, letters, letters, 2}
+	Mod1271_36 = ISO7064{"ISO 7064 MOD 1271-36", 1271, 36, false, alphanumeric, alphanumeric, 2}
+	Mod11_10   = ISO7064{"ISO 7064 MOD 11,10", 10, 0, true, digits, digits, 1}
+	Mod27_26   = ISO7064{"ISO 7064 MOD 27,26", 26, 0, true, letters, letters, 1}
+	Mod37_36   = ISO7064{"ISO 7064 MOD 37,36", 36, 0, true, alphanumeric, alphanumeric, 1}
+)
+
+// String returns the name of the system, e.g. "ISO 7064 MOD 97-10".
+//
+func (sys ISO7064) String() string {
+
+	return sys.name
+}
+
+// check returns the value of the check characters for s.
+//
+func (sys ISO7064) check(s string) (int, error) {
+	var p int
+
+	if s == "" {
+		return 0, errSyntax
+	}
+
+	if sys.hybrid {
+		m := sys.modulus
+		p = m
+		for i := 0; i < len(s); i++ {
+			a := strings.IndexByte(sys.alphabet, s[i])
+			if a < 0 {
+				return 0, errSyntax
+			}
+			p = (p + a) % m
+			if p == 0 {
+				p = m
+			}
+			p = (p * 2) % (m + 1)
+		}
+		return (m + 1 - p) % m, nil
+	}
+
+	for i := 0; i < len(s); i++ {
+		a := strings.IndexByte(sys.alphabet, s[i])
+		if a < 0 {
+			return 0, errSyntax
+		}
+		p = ((p + a) * sys.radix) % sys.modulus
+	}
+
+	if sys.checkLen == 2 {
+		p = (p * sys.radix) % sys.modulus
+	}
+
+	return (sys.modulus + 1 - p) % sys.modulus, nil
+}
+
+// Compute returns the check characters of s.
+//
+//      Mod97_10.Compute("794")     is  "44"
+//      Mod11_2.Compute("079")      is  "X"
+//
+// ConversionSyntax error is returned if s is empty or contains a character not in the alphabet of the system.
+//
+func (sys ISO7064) Compute(s string) (string, error) {
+	var (
+		c   int
+		err error
+	)
+
+	if c, err = sys.check(s); err != nil {
+		return "", err
+	}
+
+	if sys.checkLen == 2 {
+		return string([]byte{sys.checkChars[c/sys.radix], sys.checkChars[c%sys.radix]}), nil
+	}
+
+	return string(sys.checkChars[c]), nil
+}
+
+// Valid returns true if s ends with the check characters of the preceding characters.
+//
+//      Mod97_10.Valid("79444")    is  true
+//
+func (sys ISO7064) Valid(s string) (bool, error) {
+	var (
+		check string
+		err   error
+	)
+
+	if len(s) <= sys.checkLen {
+		return false, errSyntax
+	}
+
+	if check, err = sys.Compute(s[:len(s)-sys.checkLen]); err != nil {
+		return false, err
+	}
+
+	return check == s[len(s)-sys.checkLen:], nil
+}
+
+// ComputeQuad returns the check characters of the digits of n, which must be a non-negative integer.
+//
+func (sys ISO7064) ComputeQuad(n decnum.Quad) (string, error) {
+	var (
+		s   string
+		err error
+	)
+
+	if s, err = integerDigits(n); err != nil {
+		return "", err
+	}
+
+	return sys.Compute(s)
+}
+
+// ValidQuad returns true if check contains the check characters of the digits of n, which must be a non-negative integer.
+//
+// The check characters are passed separately, because they can contain letters, like 'X' for Mod11_2.
+//
+func (sys ISO7064) ValidQuad(n decnum.Quad, check string) (bool, error) {
+	var (
+		c   string
+		err error
+	)
+
+	if c, err = sys.ComputeQuad(n); err != nil {
+		return false, err
+	}
+
+	return c == check, nil
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                         MOD 97, IBAN and RF                          */
+/*                                                                      */
+/************************************************************************/
+
+// chunk of digits processed at each step by mod97.
+// The remainder has 2 digits, so that remainder and chunk always fit in the 34 digits of a Quad.
+//
+const mod97Chunk = decnum.DecquadPmax - 2
+
+var g_97 = decnum.FromInt32(97)
+
+// mod97 returns s modulo 97, s being a string of alphanumeric characters.
+// Letters are replaced by two digits, A=10, B=11, ..., Z=35, as required by ISO 13616 and ISO 11649.
+//
+// The digit string can be longer than 34 digits, so the modulo is computed by parts:
+// the remainder of a chunk is prepended to the next chunk.
+//
+func mod97(s string) (int, error) {
+	var (
+		buff      []byte
+		remainder decnum.Quad
+		q         decnum.Quad
+		err       error
+		r         int32
+	)
+
+	buff = make([]byte, 0, 2*len(s))
+
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(alphanumeric, s[i])
+		if v < 0 {
+			return 0, errSyntax
+		}
+		if v >= 10 {
+			buff = append(buff, byte('0'+v/10))
+		}
+		buff = append(buff, byte('0'+v%10))
+	}
+
+	remainder = decnum.Zero()
+
+	for len(buff) > 0 {
+		n := mod97Chunk
+		if n > len(buff) {
+			n = len(buff)
+		}
+
+		if q, err = decnum.FromString(remainder.String() + string(buff[:n])); err != nil {
+			return 0, err
+		}
+
+		remainder = q.Mod(g_97)
+
+		buff = buff[n:]
+	}
+
+	if r, err = remainder.ToInt32(decnum.RoundHalfEven); err != nil {
+		return 0, err
+	}
+
+	return int(r), nil
+}
+
+// normalize removes spaces and converts to upper case.
+//
+func normalize(s string) string {
+
+	return strings.ToUpper(strings.Join(strings.Fields(s), ""))
+}
+
+// ValidIBAN returns true if iban is a valid International Bank Account Number (ISO 13616).
+// Spaces are ignored, and lower case letters are accepted. E.g. "GB82 WEST 1234 5698 7654 32".
+//
+// Only the structure and the check digits are verified, not the country specific format of the BBAN.
+//
+func ValidIBAN(iban string) (bool, error) {
+	var (
+		r   int
+		err error
+	)
+
+	iban = normalize(iban)
+
+	if len(iban) < 5 || len(iban) > 34 || !isLetter(iban[0]) || !isLetter(iban[1]) || !isDigit(iban[2]) || !isDigit(iban[3]) {
+		return false, errSyntax
+	}
+
+	if r, err = mod97(iban[4:] + iban[:4]); err != nil {
+		return false, err
+	}
+
+	return r == 1, nil
+}
+
+// IBAN returns the IBAN made of the country code, the computed check digits, and the BBAN (Basic Bank Account Number).
+//
+//      IBAN("GB", "WEST12345698765432")     is    "GB82WEST12345698765432"
+//
+func IBAN(country string, bban string) (string, error) {
+	var (
+		r   int
+		err error
+	)
+
+	country = normalize(country)
+	bban = normalize(bban)
+
+	if len(country) != 2 || !isLetter(country[0]) || !isLetter(country[1]) || bban == "" || len(bban) > 30 {
+		return "", errSyntax
+	}
+
+	if r, err = mod97(bban + country + "00"); err != nil {
+		return "", err
+	}
+
+	return country + twoDigits(98-r) + bban, nil
+}
+
+// ValidRF returns true if ref is a valid RF creditor reference (ISO 11649).
+// Spaces are ignored, and lower case letters are accepted. E.g. "RF18 5390 0754 7034".
+//
+func ValidRF(ref string) (bool, error) {
+	var (
+		r   int
+		err error
+	)
+
+	ref = normalize(ref)
+
+	if len(ref) < 5 || len(ref) > 25 || ref[:2] != "RF" || !isDigit(ref[2]) || !isDigit(ref[3]) {
+		return false, errSyntax
+	}
+
+	if r, err = mod97(ref[4:] + ref[:4]); err != nil {
+		return false, err
+	}
+
+	return r == 1, nil
+}
+
+// RF returns the RF creditor reference made of "RF", the computed check digits, and the reference, which has at most 21 alphanumeric characters.
+//
+//      RF("539007547034")     is    "RF18539007547034"
+//
+func RF(reference string) (string, error) {
+	var (
+		r   int
+		err error
+	)
+
+	reference = normalize(reference)
+
+	if reference == "" || len(reference) > 21 {
+		return "", errSyntax
+	}
+
+	if r, err = mod97(reference + "RF00"); err != nil {
+		return "", err
+	}
+
+	return "RF" + twoDigits(98-r) + reference, nil
+}
+
+func isLetter(c byte) bool {
+
+	return c >= 'A' && c <= 'Z'
+}
+
+func isDigit(c byte) bool {
+
+	return c >= '0' && c <= '9'
+}
+
+// twoDigits returns n, in [0..99], as a string of two digits.
+//
+func twoDigits(n int) string {
+
+	return string([]byte{byte('0' + n/10), byte('0' + n%10)})
+}
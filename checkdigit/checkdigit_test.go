@@ -0,0 +1,229 @@
+package checkdigit
+
+import (
+	"log"
+	"testing"
+
+	"github.com/rin01/decnum"
+)
+
+// converts string to Quad or aborts.
+//
+func must_quad(s string) decnum.Quad {
+	var q decnum.Quad
+
+	q, _ = decnum.FromString(s)
+
+	if err := q.Error(); err != nil {
+		log.Fatalf("must_quad(\"%s\") failed: %s", s, err)
+	}
+
+	return q
+}
+
+func Test_luhn(t *testing.T) {
+
+	var samples = []struct {
+		n                     string
+		expected_check        int
+		expected_error_status decnum.Status
+	}{
+		{"7992739871", 3, 0},
+		{"0", 0, 0},
+		{"4", 2, 0},
+		{"453201511283036", 6, 0},
+		{"123456781234567812345678123456781", 2, 0},
+		{"79927398.710E2", 3, 0},
+		{"7992739871E1", 4, 0},
+		{"12.5", 0, decnum.InvalidOperation},
+		{"-12", 0, decnum.InvalidOperation},
+		{"NaN", 0, decnum.InvalidOperation},
+		{"Inf", 0, decnum.InvalidOperation},
+	}
+
+	for i, sp := range samples {
+		var status decnum.Status
+
+		check, err := Luhn(must_quad(sp.n))
+		if err != nil {
+			status = decnum.Status(err.(decnum.QuadError))
+		}
+
+		if status != sp.expected_error_status {
+			t.Fatalf("sample %d, Luhn <%s>:  \"%s\" (status) != \"%s\" (expected status)", i, sp.n, status, sp.expected_error_status)
+		}
+
+		if check != sp.expected_check {
+			t.Fatalf("sample %d, Luhn <%s>:  %d (output) != %d (expected result)", i, sp.n, check, sp.expected_check)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		valid, err := ValidLuhn(must_quad(sp.n).Mul(decnum.FromInt32(10)).Add(decnum.FromInt32(int32(check))))
+		if !valid || err != nil {
+			t.Fatalf("sample %d, ValidLuhn <%s> failed", i, sp.n)
+		}
+	}
+
+	if valid, _ := ValidLuhn(must_quad("79927398710")); valid {
+		t.Fatal("ValidLuhn(79927398710) should be false")
+	}
+}
+
+func Test_iso7064(t *testing.T) {
+
+	var samples = []struct {
+		system                ISO7064
+		s                     string
+		expected_check        string
+		expected_error_status decnum.Status
+	}{
+		{Mod11_2, "079", "X", 0},
+		{Mod11_2, "0794", "0", 0},
+		{Mod11_2, "123456789", "X", 0},
+		{Mod11_2, "1", "X", 0},
+		{Mod11_2, "12A", "", decnum.ConversionSyntax},
+		{Mod11_2, "", "", decnum.ConversionSyntax},
+		{Mod37_2, "G123498654321", "H", 0},
+		{Mod37_2, "ABC123", "G", 0},
+		{Mod37_2, "Z", "5", 0},
+		{Mod37_2, "abc", "", decnum.ConversionSyntax},
+		{Mod97_10, "794", "44", 0},
+		{Mod97_10, "123456", "76", 0},
+		{Mod97_10, "0", "01", 0},
+		{Mod661_26, "ABC", "JI", 0},
+		{Mod661_26, "ISOHJ", "TC", 0},
+		{Mod661_26, "Z", "LB", 0},
+		{Mod661_26, "A1", "", decnum.ConversionSyntax},
+		{Mod1271_36, "ISO79", "3W", 0},
+		{Mod1271_36, "ABC123", "PM", 0},
+		{Mod1271_36, "0", "01", 0},
+		{Mod11_10, "0794", "5", 0},
+		{Mod11_10, "123456789", "7", 0},
+		{Mod11_10, "5", "1", 0},
+		{Mod27_26, "JEFKEN", "E", 0},
+		{Mod27_26, "ABCDEF", "P", 0},
+		{Mod27_26, "A", "C", 0},
+		{Mod37_36, "A12425GABC1234002", "M", 0},
+		{Mod37_36, "ABC123", "0", 0},
+		{Mod37_36, "Z", "4", 0},
+	}
+
+	for i, sp := range samples {
+		var status decnum.Status
+
+		check, err := sp.system.Compute(sp.s)
+		if err != nil {
+			status = decnum.Status(err.(decnum.QuadError))
+		}
+
+		if status != sp.expected_error_status {
+			t.Fatalf("sample %d, %s <%s>:  \"%s\" (status) != \"%s\" (expected status)", i, sp.system, sp.s, status, sp.expected_error_status)
+		}
+
+		if check != sp.expected_check {
+			t.Fatalf("sample %d, %s <%s>:  \"%s\" (output) != \"%s\" (expected result)", i, sp.system, sp.s, check, sp.expected_check)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if valid, err := sp.system.Valid(sp.s + check); !valid || err != nil {
+			t.Fatalf("sample %d, %s <%s>: Valid failed", i, sp.system, sp.s+check)
+		}
+	}
+
+	// numeric systems, on Quad
+
+	if check, err := Mod11_2.ComputeQuad(must_quad("79")); check != "X" || err != nil {
+		t.Fatalf("Mod11_2.ComputeQuad(79) failed: %s %v", check, err)
+	}
+
+	if valid, err := Mod97_10.ValidQuad(must_quad("794"), "44"); !valid || err != nil {
+		t.Fatalf("Mod97_10.ValidQuad(794, 44) failed: %v", err)
+	}
+
+	if valid, _ := Mod97_10.Valid("79445"); valid {
+		t.Fatal("Mod97_10.Valid(79445) should be false")
+	}
+
+	if _, err := Mod11_10.ComputeQuad(must_quad("-794")); err == nil {
+		t.Fatal("Mod11_10.ComputeQuad(-794) should fail")
+	}
+}
+
+func Test_iban_rf(t *testing.T) {
+
+	var valid_samples = []string{
+		"GB82 WEST 1234 5698 7654 32",
+		"DE89370400440532013000",
+		"fr14 2004 1010 0505 0001 3m02 606",
+		"MT84MALT011000012345MTLCAST001S",
+		"LC55HEMM000100010012001200023015",
+	}
+
+	for _, s := range valid_samples {
+		if valid, err := ValidIBAN(s); !valid || err != nil {
+			t.Fatalf("ValidIBAN(%s) failed: %v", s, err)
+		}
+	}
+
+	var invalid_samples = []string{
+		"GB82 WEST 1234 5698 7654 31",
+		"GB28 WEST 1234 5698 7654 32",
+		"DE89370400440532013001",
+	}
+
+	for _, s := range invalid_samples {
+		if valid, err := ValidIBAN(s); valid || err != nil {
+			t.Fatalf("ValidIBAN(%s) should be false", s)
+		}
+	}
+
+	for _, s := range []string{"", "GB", "G182WEST", "GBX2WEST", "GB82WEST-123", "GB82WEST123456987654321234567890123"} {
+		if _, err := ValidIBAN(s); err != decnum.QuadError(decnum.ConversionSyntax) {
+			t.Fatalf("ValidIBAN(%s) should return ConversionSyntax error", s)
+		}
+	}
+
+	if iban, err := IBAN("GB", "WEST12345698765432"); iban != "GB82WEST12345698765432" || err != nil {
+		t.Fatalf("IBAN failed: %s %v", iban, err)
+	}
+
+	if iban, err := IBAN("de", "3704 0044 0532 0130 00"); iban != "DE89370400440532013000" || err != nil {
+		t.Fatalf("IBAN failed: %s %v", iban, err)
+	}
+
+	// RF creditor reference
+
+	if valid, err := ValidRF("RF18 5390 0754 7034"); !valid || err != nil {
+		t.Fatalf("ValidRF failed: %v", err)
+	}
+
+	if valid, err := ValidRF("RF18000000000539007547034"); !valid || err != nil {
+		t.Fatalf("ValidRF failed: %v", err)
+	}
+
+	if valid, err := ValidRF("RF19539007547034"); valid || err != nil {
+		t.Fatal("ValidRF(RF19539007547034) should be false")
+	}
+
+	if _, err := ValidRF("XX18539007547034"); err == nil {
+		t.Fatal("ValidRF(XX18539007547034) should fail")
+	}
+
+	if ref, err := RF("539007547034"); ref != "RF18539007547034" || err != nil {
+		t.Fatalf("RF failed: %s %v", ref, err)
+	}
+
+	if ref, err := RF("2348231"); ref != "RF712348231" || err != nil {
+		t.Fatalf("RF failed: %s %v", ref, err)
+	}
+
+	if _, err := RF("1234567890123456789012"); err == nil {
+		t.Fatal("RF with 22 characters should fail")
+	}
+}
@@ -0,0 +1,304 @@
+#include "mydecquad.h"
+
+
+/************************************************************************/
+/*                                                                      */
+/*     BigDecimal: decNumber with a precision chosen by the caller      */
+/*                                                                      */
+/*     The decNumber structures are allocated by Go, with the size      */
+/*     returned by mdb_size(). The result of an operation is always     */
+/*     large enough for the digits of the context and of the operands.  */
+/*                                                                      */
+/************************************************************************/
+
+
+/* size in bytes of a decNumber that can contain digits digits.
+*/
+size_t mdb_size(int32_t digits) {
+  int32_t  units;
+
+  units = (digits + DECDPUN - 1) / DECDPUN;
+
+  if ( units < DECNUMUNITS ) {
+      units = DECNUMUNITS;
+  }
+
+  return offsetof(decNumber, lsu) + units * sizeof(decNumberUnit);
+}
+
+
+/* fill a context for digits digits.
+
+   The exponent range is the largest one allowed by the decNumber mathematical functions, like decNumberPower(), and traps are disabled.
+*/
+static void mdb_context(decContext *set, int32_t digits, int round, uint32_t status) {
+
+  decContextDefault(set, DEC_INIT_BASE);
+  set->traps  = 0;                         // DEC_INIT_BASE sets traps, that would raise SIGFPE
+  set->digits = digits;
+  set->round  = round;
+  set->emax   = DEC_MAX_MATH;
+  set->emin   = -DEC_MAX_MATH;
+  set->status = status;
+}
+
+
+/* status of the result.
+
+   decNumber sets Rounded, Subnormal and Clamped flags, which are not used by this package.
+*/
+static uint32_t mdb_status(decContext *set) {
+
+  return decContextGetStatus(set) & ~(DEC_Rounded | DEC_Subnormal | DEC_Clamped);
+}
+
+
+/************************************************************************/
+/*                              operations                              */
+/************************************************************************/
+
+
+/* operation on a and b. For unary operations, b is ignored.
+
+   Returns the status.
+*/
+uint32_t mdb_operation(int op, decNumber *res, const decNumber *a, const decNumber *b, int32_t digits, int round, uint32_t status) {
+  decContext  set;
+
+  mdb_context(&set, digits, round, status);
+
+  switch ( op ) {
+  case MDB_PLUS:          decNumberPlus(res, a, &set);                  break;
+  case MDB_MINUS:         decNumberMinus(res, a, &set);                 break;
+  case MDB_ABS:           decNumberAbs(res, a, &set);                   break;
+  case MDB_SQRT:          decNumberSquareRoot(res, a, &set);            break;
+  case MDB_TO_INTEGRAL:   decNumberToIntegralValue(res, a, &set);       break;
+  case MDB_ADD:           decNumberAdd(res, a, b, &set);                break;
+  case MDB_SUBTRACT:      decNumberSubtract(res, a, b, &set);           break;
+  case MDB_MULTIPLY:      decNumberMultiply(res, a, b, &set);           break;
+  case MDB_DIVIDE:        decNumberDivide(res, a, b, &set);             break;
+  case MDB_DIVIDE_INT:    decNumberDivideInteger(res, a, b, &set);      break;
+  case MDB_REMAINDER:     decNumberRemainder(res, a, b, &set);          break;
+  case MDB_MAX:           decNumberMax(res, a, b, &set);                break;
+  case MDB_MIN:           decNumberMin(res, a, b, &set);                break;
+  case MDB_QUANTIZE:      decNumberQuantize(res, a, b, &set);           break;
+  case MDB_POWER:         decNumberPower(res, a, b, &set);              break;
+  default:
+      assert( 0 );
+  }
+
+  return mdb_status(&set);
+}
+
+
+/* rounds a to n digits after the decimal point. If n < 0, the integral part is rounded, e.g. 1234.5 with n=-2 gives 1200.
+
+   Returns the status.
+*/
+uint32_t mdb_round(decNumber *res, const decNumber *a, int32_t n, int32_t digits, int round, uint32_t status) {
+  decContext  set;
+  decNumber   quantizer;
+
+  mdb_context(&set, digits, round, status);
+
+  decNumberFromInt32(&quantizer, 1);
+  quantizer.exponent = -n;                        // 1E-n
+
+  decNumberQuantize(res, a, &quantizer, &set);    // e.g. quantize(1234.5678, 2)  --> 1234.57,   quantize(1234.5678, -2) --> 12E2
+
+  if ( n < 0 ) {
+      quantizer.exponent = 0;
+      decNumberQuantize(res, res, &quantizer, &set);   // right-shift the number, adding missing 0s on the left. E.g. 12E2 --> 1200E0
+  }
+
+  return mdb_status(&set);
+}
+
+
+/* compare.
+*/
+uint32_t mdb_compare(const decNumber *a, const decNumber *b) {
+  decContext  set;
+  decNumber   cmp_val;
+
+  mdb_context(&set, DECNUMDIGITS, DEC_ROUND_HALF_EVEN, 0);
+
+  decNumberCompare(&cmp_val, a, b, &set);   // result may be –1, 0, 1, or NaN. NaN is returned only if a or b is a NaN.
+
+  if ( decNumberIsNaN(&cmp_val) ) {
+      return CMP_NAN;
+  }
+
+  if ( decNumberIsZero(&cmp_val) ) {
+      return CMP_EQUAL;
+  }
+
+  if ( decNumberIsNegative(&cmp_val) ) {
+      return CMP_LESS;
+  }
+
+  return CMP_GREATER;
+}
+
+
+/************************************************************************/
+/*                           is_finite, etc                             */
+/************************************************************************/
+
+
+uint32_t mdb_is_finite(const decNumber *a) {
+
+  return decNumberIsFinite(a);
+}
+
+
+uint32_t mdb_is_infinite(const decNumber *a) {
+
+  return decNumberIsInfinite(a);
+}
+
+
+uint32_t mdb_is_nan(const decNumber *a) {
+
+  return decNumberIsNaN(a);
+}
+
+
+/* check if a is > 0 and not Nan.
+*/
+uint32_t mdb_is_positive(const decNumber *a) {
+
+  return !decNumberIsNaN(a) && !decNumberIsZero(a) && !decNumberIsNegative(a);
+}
+
+
+uint32_t mdb_is_zero(const decNumber *a) {
+
+  return decNumberIsZero(a);
+}
+
+
+/* check if a is < 0 and not Nan.
+*/
+uint32_t mdb_is_negative(const decNumber *a) {
+
+  return !decNumberIsNaN(a) && !decNumberIsZero(a) && decNumberIsNegative(a);
+}
+
+
+/* get exponent. As for decQuad, special values are returned for NaN, sNaN and Infinity.
+*/
+int32_t mdb_get_exponent(const decNumber *a) {
+
+  if ( decNumberIsSNaN(a) ) {
+      return DECFLOAT_sNaN;
+  }
+
+  if ( decNumberIsQNaN(a) ) {
+      return DECFLOAT_NaN;
+  }
+
+  if ( decNumberIsInfinite(a) ) {
+      return DECFLOAT_Inf;
+  }
+
+  return a->exponent;
+}
+
+
+/************************************************************************/
+/*                            conversions                               */
+/************************************************************************/
+
+
+/* conversion from string.
+
+   The number is rounded to digits digits.
+*/
+uint32_t mdb_from_string(decNumber *res, char *s, int32_t digits, int round) {
+  decContext  set;
+
+  mdb_context(&set, digits, round, 0);
+
+  decNumberFromString(res, s, &set);
+
+  return mdb_status(&set);
+}
+
+
+/* conversion from decQuad.
+
+   The number is rounded to digits digits, only if it has more digits. res must be large enough for 34 digits.
+*/
+uint32_t mdb_from_quad(decNumber *res, Quad a, int32_t digits, int round) {
+  decContext  set;
+
+  mdb_context(&set, digits, round, a.status);
+
+  decQuadToNumber(&a.val, res);
+
+  if ( res->digits > digits ) {
+      decNumberPlus(res, res, &set);
+  }
+
+  return mdb_status(&set);
+}
+
+
+/* conversion to decQuad, rounded with the specified rounding mode.
+*/
+Quad mdb_to_quad(const decNumber *a, int round, uint32_t status) {
+  decContext  set;
+  Quad        res;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+  decContextSetRounding(&set, round);      // change rounding mode
+  set.status = status;
+
+  decQuadFromNumber(&res.val, a, &set);
+  res.status = decContextGetStatus(&set) & ~(DEC_Rounded | DEC_Subnormal | DEC_Clamped);
+
+  return res;
+}
+
+
+/* a/b as a decQuad, rounded with the specified rounding mode.
+
+   The division is done with the decQuad context, so that the quotient is rounded only once, also if it is subnormal.
+   It is used to convert fractions, whose numerator and denominator can have any number of digits.
+*/
+Quad mdb_divide_to_quad(const decNumber *a, const decNumber *b, int round) {
+  decContext  set;
+  decNumber   quotient;           // DECNUMDIGITS is DECQUAD_Pmax
+  Quad        res;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+  decContextSetRounding(&set, round);      // change rounding mode
+
+  decNumberDivide(&quotient, a, b, &set);
+  decQuadFromNumber(&res.val, &quotient, &set);
+  res.status = mdb_status(&set);
+
+  return res;
+}
+
+
+/* write a into s, which must have a capacity of a->digits+14 bytes.
+
+   Returns the length of the string.
+*/
+size_t mdb_to_string(const decNumber *a, char *s) {
+
+  decNumberToString(a, s);
+
+  return strlen(s);
+}
+
+
+/* write the coefficient of a into bcd, one digit per byte. bcd must have a capacity of a->digits bytes.
+*/
+void mdb_to_BCD(const decNumber *a, uint8_t *bcd) {
+
+  decNumberGetBCD(a, bcd);
+}
+
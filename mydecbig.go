@@ -0,0 +1,690 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+import (
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                      BigDecimal value and context                    */
+/*                                                                      */
+/************************************************************************/
+
+// BigMaxDigits is the maximum precision of a BigContext.
+// It is the limit of the decNumber mathematical functions, like Pow.
+//
+const BigMaxDigits = C.DEC_MAX_MATH // 999999
+
+// BigContext contains the precision and the rounding mode used by BigDecimal operations.
+//
+// The zero value is a context of 34 digits, with RoundHalfEven, like Quad.
+//
+type BigContext struct {
+	digits   int32 // 0 means DecquadPmax
+	rounding RoundingMode
+	set      bool // false for the zero value, which uses RoundHalfEven
+}
+
+// NewBigContext returns a context with the specified precision, in number of significant digits, and rounding mode.
+//
+// digits must be in the range [1...BigMaxDigits]. Else, the operations using the context return NaN, and set the InvalidContext flag.
+//
+//     ctx := NewBigContext(50, RoundHalfEven)
+//     r, err := ctx.FromString("1.23456789012345678901234567890123456789")
+//
+func NewBigContext(digits int32, rounding RoundingMode) BigContext {
+
+	return BigContext{digits: digits, rounding: rounding, set: true}
+}
+
+// Digits returns the precision of the context, in number of significant digits.
+//
+func (ctx BigContext) Digits() int32 {
+
+	if !ctx.set {
+		return DecquadPmax
+	}
+
+	return ctx.digits
+}
+
+// Rounding returns the rounding mode of the context.
+//
+func (ctx BigContext) Rounding() RoundingMode {
+
+	if !ctx.set {
+		return RoundHalfEven
+	}
+
+	return ctx.rounding
+}
+
+// valid returns true if the precision of the context is in the range [1...BigMaxDigits].
+//
+func (ctx BigContext) valid() bool {
+
+	return ctx.Digits() >= 1 && ctx.Digits() <= BigMaxDigits
+}
+
+// BigDecimal contains an arbitrary-precision decimal floating-point value, the context used to compute it, and a status like Quad.
+//
+// The operations round their result to the precision of the context of the receiver, with its rounding mode:
+//
+//     ctx := NewBigContext(50, RoundHalfEven)
+//     r := ctx.FromInt64(1).Div(ctx.FromInt64(3))      // 0.33333333333333333333333333333333333333333333333333
+//
+// The exponent range is much larger than for Quad: the adjusted exponent is in [-999999, 999999].
+// The zero value is 0, with the zero value of BigContext.
+//
+// As for Quad operations, the status fields of the operands propagate into the result.
+//
+type BigDecimal struct {
+	num    []byte // decNumber structure, of the size given by mdb_size(). It is never modified after the operation that created it.
+	ctx    BigContext
+	status Status
+}
+
+var (
+	g_big_zero []byte = bigConstant("0")
+	g_big_nan  []byte = bigConstant("NaN")
+)
+
+// used only to initialize the global variables g_big_zero and g_big_nan.
+//
+func bigConstant(s string) []byte {
+
+	cs := C.CString(s)
+	defer C.free(unsafe.Pointer(cs))
+
+	num := newBigNumber(1)
+	C.mdb_from_string(bigPtr(num), cs, 1, C.int(RoundHalfEven))
+
+	return num
+}
+
+// newBigNumber returns a buffer that can contain a decNumber of the specified number of digits.
+//
+func newBigNumber(digits int32) []byte {
+
+	return make([]byte, C.mdb_size(C.int32_t(digits)))
+}
+
+// bigPtr returns a pointer to the decNumber stored in num.
+//
+func bigPtr(num []byte) *C.decNumber {
+
+	return (*C.decNumber)(unsafe.Pointer(&num[0]))
+}
+
+// ptr returns a pointer to the decNumber of a.
+//
+func (a BigDecimal) ptr() *C.decNumber {
+
+	if a.num == nil {
+		return bigPtr(g_big_zero)
+	}
+
+	return bigPtr(a.num)
+}
+
+// bigNaN returns NaN, with the specified context and status.
+//
+func bigNaN(ctx BigContext, status Status) BigDecimal {
+
+	return BigDecimal{num: g_big_nan, ctx: ctx, status: status}
+}
+
+// Context returns the context of a.
+//
+func (a BigDecimal) Context() BigContext {
+
+	return a.ctx
+}
+
+// WithContext returns a with the context ctx. The value is rounded to the precision of ctx if necessary.
+//
+func (a BigDecimal) WithContext(ctx BigContext) BigDecimal {
+
+	a.ctx = ctx
+
+	return a.operation(C.MDB_PLUS, a, ctx.Rounding())
+}
+
+// Digits returns the number of digits of the coefficient of a.
+//
+//      12.340     is      12340E-3      gives   5
+//
+// It returns 1 if a is zero, Infinite or NaN.
+//
+func (a BigDecimal) Digits() int32 {
+
+	return int32(a.ptr().digits)
+}
+
+// Status returns the status field of the BigDecimal.
+// It contains error flags, like 'DivisionByZero', and informational flags, like 'Inexact'.
+//
+func (a BigDecimal) Status() Status {
+
+	return a.status
+}
+
+// ErrorStatus returns the status field of the BigDecimal.
+// It contains only error flags.
+// Same as a.Status()&ErrorMask
+//
+func (a BigDecimal) ErrorStatus() Status {
+
+	return a.status & ErrorMask
+}
+
+// Error returns an error if an error flag bit has been set in BigDecimal's status field.
+//
+// It returns QuadError(a.ErrorStatus()), or nil if no error.
+//
+func (a BigDecimal) Error() error {
+
+	if a.status&ErrorMask == 0 {
+		return nil
+	}
+
+	return QuadError(a.status & ErrorMask)
+}
+
+// ClearStatus returns a copy of a, whith status field cleared.
+//
+func (a BigDecimal) ClearStatus() BigDecimal {
+
+	a.status = 0
+	return a
+}
+
+// SetStatusFlags returns a copy of a, setting the status flags specified by argument.
+//
+func (a BigDecimal) SetStatusFlags(statusflags Status) BigDecimal {
+
+	a.status |= statusflags
+	return a
+}
+
+// ClearStatusFlags returns a copy of a, clearing the status flags specified by argument.
+//
+func (a BigDecimal) ClearStatusFlags(statusflags Status) BigDecimal {
+
+	a.status &^= statusflags
+	return a
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                      arithmetic operations                           */
+/*                                                                      */
+/************************************************************************/
+
+// operation calls mdb_operation() with the context of a, and returns the result.
+// For unary operations, b is a.
+//
+func (a BigDecimal) operation(op C.int, b BigDecimal, rounding RoundingMode) BigDecimal {
+	var (
+		ctx    BigContext = a.ctx
+		status Status     = a.status | b.status
+		size   int32
+		num    []byte
+	)
+
+	if !ctx.valid() {
+		return bigNaN(ctx, status|InvalidContext)
+	}
+
+	size = ctx.Digits() // the result never has more digits than the context or the operands
+	if a.Digits() > size {
+		size = a.Digits()
+	}
+	if b.Digits() > size {
+		size = b.Digits()
+	}
+
+	num = newBigNumber(size)
+
+	status = Status(C.mdb_operation(op, bigPtr(num), a.ptr(), b.ptr(), C.int32_t(ctx.Digits()), C.int(rounding), C.uint32_t(status)))
+
+	return BigDecimal{num: num, ctx: ctx, status: status}
+}
+
+// Neg returns -a.
+//
+func (a BigDecimal) Neg() BigDecimal {
+
+	return a.operation(C.MDB_MINUS, a, a.ctx.Rounding())
+}
+
+// Add returns a + b.
+//
+func (a BigDecimal) Add(b BigDecimal) BigDecimal {
+
+	return a.operation(C.MDB_ADD, b, a.ctx.Rounding())
+}
+
+// Sub returns a - b.
+//
+func (a BigDecimal) Sub(b BigDecimal) BigDecimal {
+
+	return a.operation(C.MDB_SUBTRACT, b, a.ctx.Rounding())
+}
+
+// Mul returns a * b.
+//
+func (a BigDecimal) Mul(b BigDecimal) BigDecimal {
+
+	return a.operation(C.MDB_MULTIPLY, b, a.ctx.Rounding())
+}
+
+// Div returns a/b.
+//
+func (a BigDecimal) Div(b BigDecimal) BigDecimal {
+
+	return a.operation(C.MDB_DIVIDE, b, a.ctx.Rounding())
+}
+
+// DivInt returns the integral part of a/b.
+//
+func (a BigDecimal) DivInt(b BigDecimal) BigDecimal {
+
+	return a.operation(C.MDB_DIVIDE_INT, b, a.ctx.Rounding())
+}
+
+// Mod returns the modulo of a and b.
+//
+func (a BigDecimal) Mod(b BigDecimal) BigDecimal {
+
+	return a.operation(C.MDB_REMAINDER, b, a.ctx.Rounding())
+}
+
+// BigMax returns the larger of a and b, with the context of a.
+// If either a or b is NaN then the other argument is the result.
+//
+func BigMax(a BigDecimal, b BigDecimal) BigDecimal {
+
+	return a.operation(C.MDB_MAX, b, a.ctx.Rounding())
+}
+
+// BigMin returns the smaller of a and b, with the context of a.
+// If either a or b is NaN then the other argument is the result.
+//
+func BigMin(a BigDecimal, b BigDecimal) BigDecimal {
+
+	return a.operation(C.MDB_MIN, b, a.ctx.Rounding())
+}
+
+// ToIntegral returns the value of a rounded to an integral value.
+//
+// See Quad.ToIntegral.
+//
+func (a BigDecimal) ToIntegral(rounding RoundingMode) BigDecimal {
+
+	return a.operation(C.MDB_TO_INTEGRAL, a, rounding)
+}
+
+// Quantize rounds a to the same pattern as b.
+// b is just a model, its sign and coefficient value are ignored. Only its exponent is used.
+//
+// See Quad.Quantize. If the result needs more digits than the precision of the context, the InvalidOperation flag is set.
+//
+func (a BigDecimal) Quantize(b BigDecimal, rounding RoundingMode) BigDecimal {
+
+	return a.operation(C.MDB_QUANTIZE, b, rounding)
+}
+
+// Abs returns the absolute value of a.
+//
+func (a BigDecimal) Abs() BigDecimal {
+
+	return a.operation(C.MDB_ABS, a, a.ctx.Rounding())
+}
+
+// Pow returns a raised to the power of b.
+//
+// See Quad.Pow.
+//
+func (a BigDecimal) Pow(b BigDecimal) BigDecimal {
+
+	return a.operation(C.MDB_POWER, b, a.ctx.Rounding())
+}
+
+// Sqrt returns the square root of a. It is always rounded with RoundHalfEven mode, whatever the rounding mode of the context.
+//
+// Sqrt(a) is NaN if a < 0, and sets the InvalidOperation flag.
+//
+func (a BigDecimal) Sqrt() BigDecimal {
+
+	return a.operation(C.MDB_SQRT, a, RoundHalfEven)
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                            IsFinite, etc                             */
+/*                                                                      */
+/************************************************************************/
+
+// IsFinite returns true if a is not Infinite, nor Nan.
+//
+func (a BigDecimal) IsFinite() bool {
+
+	return C.mdb_is_finite(a.ptr()) != 0
+}
+
+// IsInfinite returns true if a is Infinite.
+//
+func (a BigDecimal) IsInfinite() bool {
+
+	return C.mdb_is_infinite(a.ptr()) != 0
+}
+
+// IsNaN returns true if a is Nan.
+//
+func (a BigDecimal) IsNaN() bool {
+
+	return C.mdb_is_nan(a.ptr()) != 0
+}
+
+// IsPositive returns true if a > 0 and not Nan.
+//
+func (a BigDecimal) IsPositive() bool {
+
+	return C.mdb_is_positive(a.ptr()) != 0
+}
+
+// IsZero returns true if a == 0.
+//
+func (a BigDecimal) IsZero() bool {
+
+	return C.mdb_is_zero(a.ptr()) != 0
+}
+
+// IsNegative returns true if a < 0 and not NaN.
+//
+func (a BigDecimal) IsNegative() bool {
+
+	return C.mdb_is_negative(a.ptr()) != 0
+}
+
+// GetExponent returns the exponent of a.
+// It can returns special values such as ExpNaN, ExpSignalingNaN or ExpInf if a is NaN, sNaN or Infinity.
+//
+func (a BigDecimal) GetExponent() int32 {
+
+	return int32(C.mdb_get_exponent(a.ptr()))
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                            comparison                                */
+/*                                                                      */
+/************************************************************************/
+
+// Greater is true if a > b.
+//
+func (a BigDecimal) Greater(b BigDecimal) bool {
+
+	return CmpFlag(C.mdb_compare(a.ptr(), b.ptr()))&CmpGreater != 0
+}
+
+// GreaterEqual is true if a >= b.
+//
+func (a BigDecimal) GreaterEqual(b BigDecimal) bool {
+
+	return CmpFlag(C.mdb_compare(a.ptr(), b.ptr()))&(CmpGreater|CmpEqual) != 0
+}
+
+// Equal is true if a == b.
+//
+func (a BigDecimal) Equal(b BigDecimal) bool {
+
+	return CmpFlag(C.mdb_compare(a.ptr(), b.ptr()))&CmpEqual != 0
+}
+
+// LessEqual is true if a <= b.
+//
+func (a BigDecimal) LessEqual(b BigDecimal) bool {
+
+	return CmpFlag(C.mdb_compare(a.ptr(), b.ptr()))&(CmpLess|CmpEqual) != 0
+}
+
+// Less is true if a < b.
+//
+func (a BigDecimal) Less(b BigDecimal) bool {
+
+	return CmpFlag(C.mdb_compare(a.ptr(), b.ptr()))&CmpLess != 0
+}
+
+// Cmp compares a and b.
+// It returns CmpLess, CmpEqual or CmpGreater, or CmpNaN if a or b is NaN.
+//
+func (a BigDecimal) Cmp(b BigDecimal) CmpFlag {
+
+	return CmpFlag(C.mdb_compare(a.ptr(), b.ptr()))
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                   conversion from string and numbers                 */
+/*                                                                      */
+/************************************************************************/
+
+// FromString returns a BigDecimal from a string, rounded to the precision of the context.
+//
+// The accepted strings are the same as for the package function FromString.
+//
+// This function returns result.Error() as a convenience.
+//
+func (ctx BigContext) FromString(s string) (result BigDecimal, err error) {
+	var (
+		cs  *C.char
+		num []byte
+	)
+
+	if !ctx.valid() {
+		result = bigNaN(ctx, InvalidContext)
+		return result, result.Error()
+	}
+
+	s = strings.TrimSpace(s)
+
+	cs = C.CString(s)
+	defer C.free(unsafe.Pointer(cs))
+
+	num = newBigNumber(ctx.Digits())
+
+	result = BigDecimal{num: num, ctx: ctx}
+	result.status = Status(C.mdb_from_string(bigPtr(num), cs, C.int32_t(ctx.Digits()), C.int(ctx.Rounding())))
+
+	return result, result.Error()
+}
+
+// FromInt64 returns a BigDecimal from a int64 value, rounded to the precision of the context.
+//
+func (ctx BigContext) FromInt64(value int64) BigDecimal {
+
+	result, _ := ctx.FromString(strconv.FormatInt(value, 10))
+
+	return result
+}
+
+// FromQuad returns a BigDecimal from a Quad, rounded to the precision of the context if necessary.
+// The status of a is kept.
+//
+func (ctx BigContext) FromQuad(a Quad) BigDecimal {
+	var (
+		size int32
+		num  []byte
+	)
+
+	if !ctx.valid() {
+		return bigNaN(ctx, a.Status()|InvalidContext)
+	}
+
+	size = ctx.Digits()
+	if size < DecquadPmax {
+		size = DecquadPmax // the Quad is converted before rounding
+	}
+
+	num = newBigNumber(size)
+
+	return BigDecimal{num: num, ctx: ctx, status: Status(C.mdb_from_quad(bigPtr(num), C.struct_Quad(a), C.int32_t(ctx.Digits()), C.int(ctx.Rounding())))}
+}
+
+// ToQuad returns the value of a as a Quad, rounded to 34 digits with the specified rounding mode.
+//
+// The Inexact flag is set if the value has changed. If a is out of the range of Quad, Overflow or Underflow flag is set.
+// The status of a is kept.
+//
+func (a BigDecimal) ToQuad(rounding RoundingMode) Quad {
+
+	return Quad(C.mdb_to_quad(a.ptr(), C.int(rounding), C.uint32_t(a.status)))
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                      conversion to string                            */
+/*                                                                      */
+/************************************************************************/
+
+// BigToString returns the string representation of a BigDecimal number.
+// It uses exponential notation as often as Quad.QuadToString.
+//
+func (a BigDecimal) BigToString() string {
+	var (
+		buff   []byte
+		length C.size_t
+	)
+
+	buff = make([]byte, a.Digits()+14) // sign, digits, decimal point, E+xxxxxxxxx, terminal \0
+
+	length = C.mdb_to_string(a.ptr(), (*C.char)(unsafe.Pointer(&buff[0])))
+
+	if a.IsZero() && buff[0] == '-' { // as for Quad, -0 is written without sign
+		return string(buff[1:length])
+	}
+
+	return string(buff[:length])
+}
+
+// AppendBig appends string representation of BigDecimal into byte slice.
+//
+//       AppendBig() writes a number without exp notation if its exponent is in the range [-precision of the context...0].
+//       Else, falls back on BigToString(), which will use exponential notation.
+//
+func AppendBig(dst []byte, a BigDecimal) []byte {
+	var (
+		exp      int32
+		digits   int32
+		integral int32 // number of digits of the integral part
+		bcd      []byte
+	)
+
+	exp = a.GetExponent()
+	digits = a.Digits()
+
+	if !a.IsFinite() || exp > 0 || -exp > a.ctx.Digits() {
+		return append(dst, a.BigToString()...)
+	}
+
+	bcd = make([]byte, digits)
+	C.mdb_to_BCD(a.ptr(), (*C.uint8_t)(unsafe.Pointer(&bcd[0])))
+
+	if a.IsNegative() {
+		dst = append(dst, '-')
+	}
+
+	integral = digits + exp
+
+	if integral <= 0 { // ==== write integral part ====
+		dst = append(dst, '0')
+	} else {
+		for _, d := range bcd[:integral] {
+			dst = append(dst, '0'+d)
+		}
+	}
+
+	if exp == 0 { // if no fractional part, just return
+		return dst
+	}
+
+	dst = append(dst, '.') // ==== write fractional part ====
+
+	for ; integral < 0; integral++ {
+		dst = append(dst, '0')
+	}
+
+	for _, d := range bcd[integral:] {
+		dst = append(dst, '0'+d)
+	}
+
+	return dst
+}
+
+// String is the preferred way to display a BigDecimal number.
+// It calls AppendBig internally.
+//
+func (a BigDecimal) String() string {
+
+	return string(AppendBig(nil, a))
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                      rounding and truncating                         */
+/*                                                                      */
+/************************************************************************/
+
+// RoundWithMode rounds (or truncate) 'a' to n digits after the decimal point, with the mode passed as argument.
+// If n < 0, the integral part is rounded, e.g. 1234.5 with n=-2 gives 1200.
+//
+// If the result needs more digits than the precision of the context, Invalid Operation flag is set, and NaN is returned.
+//
+func (a BigDecimal) RoundWithMode(n int32, rounding RoundingMode) BigDecimal {
+	var (
+		ctx    BigContext = a.ctx
+		size   int32
+		num    []byte
+		status Status
+	)
+
+	if !ctx.valid() {
+		return bigNaN(ctx, a.status|InvalidContext)
+	}
+
+	size = ctx.Digits()
+	if a.Digits() > size {
+		size = a.Digits()
+	}
+
+	num = newBigNumber(size)
+
+	status = Status(C.mdb_round(bigPtr(num), a.ptr(), C.int32_t(n), C.int32_t(ctx.Digits()), C.int(rounding), C.uint32_t(a.status)))
+
+	return BigDecimal{num: num, ctx: ctx, status: status}
+}
+
+// Round rounds (or truncate) 'a', with RoundHalfEven mode.
+//
+func (a BigDecimal) Round(n int32) BigDecimal {
+
+	return a.RoundWithMode(n, RoundHalfEven)
+}
+
+// Truncate truncates 'a'.
+// It is like rounding with RoundDown.
+//
+func (a BigDecimal) Truncate(n int32) BigDecimal {
+
+	return a.RoundWithMode(n, RoundDown)
+}
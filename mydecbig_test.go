@@ -0,0 +1,255 @@
+package decnum
+
+import (
+	"testing"
+)
+
+// converts a string to BigDecimal or aborts.
+//
+func must_big(ctx BigContext, s string) BigDecimal {
+
+	r, err := ctx.FromString(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+func Test_big_operations(t *testing.T) {
+
+	ctx50 := NewBigContext(50, RoundHalfEven)
+	ctx100 := NewBigContext(100, RoundHalfEven)
+	floor40 := NewBigContext(40, RoundFloor)
+
+	samples := []struct {
+		ctx             BigContext
+		operation       string
+		a               string
+		b               string
+		expected_result string
+		expected_status Status
+	}{
+		{ctx50, "div", "1", "3", "0.33333333333333333333333333333333333333333333333333", Inexact},
+		{ctx50, "div", "2", "3", "0.66666666666666666666666666666666666666666666666667", Inexact},
+		{floor40, "div", "-1", "3", "-0.3333333333333333333333333333333333333334", Inexact},
+		{ctx50, "div", "1", "0", "Infinity", DivisionByZero},
+		{ctx100, "sqrt", "2", "", "1.414213562373095048801688724209698078569671875376948073176679737990732478462107038850387534327641573", Inexact},
+		{ctx100, "pow", "2", "200", "1606938044258990275541962092341162602522202993782792835301376", 0},
+		{ctx50, "pow", "2", "200", "1.6069380442589902755419620923411626025222029937828E+60", Inexact},
+		{ctx50, "pow", "2", "0.5", "1.4142135623730950488016887242096980785696718753769", Inexact},
+		{ctx50, "add", "1E+40", "0.1", "10000000000000000000000000000000000000000.1", 0},
+		{ctx50, "sub", "1E+40", "0.1", "9999999999999999999999999999999999999999.9", 0},
+		{ctx50, "mul", "123456789012345678901234567890", "1000000000000000000001", "1.2345678901234567890135802467901234567890123456789E+50", 0},
+		{ctx50, "mul", "123456789012345678901234567891", "1000000000000000000001", "1.2345678901234567890135802468001234567890123456789E+50", Inexact},
+		{ctx100, "mul", "123456789012345678901234567890", "1000000000000000000001", "123456789012345678901358024679012345678901234567890", 0},
+		{ctx50, "divint", "7", "2", "3", 0},
+		{ctx50, "mod", "7", "2", "1", 0},
+		{ctx50, "max", "7", "NaN", "7", 0},
+		{ctx50, "min", "7", "-2", "-2", 0},
+		{ctx50, "abs", "-2.5", "", "2.5", 0},
+		{ctx50, "neg", "2.5", "", "-2.5", 0},
+		{ctx50, "quantize", "134.6454", "0.01", "134.65", Inexact},
+		{ctx50, "integral", "12.5", "", "12", 0}, // as for Quad, Inexact is not set
+		{ctx50, "round", "1234.5678", "2", "1234.57", Inexact},
+		{ctx50, "round", "1234.5678", "-2", "1200", Inexact},
+		{ctx50, "truncate", "1234.5678", "2", "1234.56", Inexact},
+		{ctx50, "round", "1E+60", "0", "NaN", InvalidOperation},
+		{ctx50, "sqrt", "-1", "", "NaN", InvalidOperation},
+		{ctx50, "add", "9E+999999", "9E+999999", "Infinity", Overflow | Inexact},
+	}
+
+	for i, sp := range samples {
+		var r BigDecimal
+
+		a := must_big(sp.ctx, sp.a)
+
+		switch sp.operation {
+		case "add":
+			r = a.Add(must_big(sp.ctx, sp.b))
+		case "sub":
+			r = a.Sub(must_big(sp.ctx, sp.b))
+		case "mul":
+			r = a.Mul(must_big(sp.ctx, sp.b))
+		case "div":
+			r = a.Div(must_big(sp.ctx, sp.b))
+		case "divint":
+			r = a.DivInt(must_big(sp.ctx, sp.b))
+		case "mod":
+			r = a.Mod(must_big(sp.ctx, sp.b))
+		case "max":
+			r = BigMax(a, must_big(sp.ctx, sp.b))
+		case "min":
+			r = BigMin(a, must_big(sp.ctx, sp.b))
+		case "abs":
+			r = a.Abs()
+		case "neg":
+			r = a.Neg()
+		case "pow":
+			r = a.Pow(must_big(sp.ctx, sp.b))
+		case "sqrt":
+			r = a.Sqrt()
+		case "quantize":
+			r = a.Quantize(must_big(sp.ctx, sp.b), RoundHalfEven)
+		case "integral":
+			r = a.ToIntegral(RoundHalfEven)
+		case "round":
+			r = a.Round(must_int32(sp.b))
+		case "truncate":
+			r = a.Truncate(must_int32(sp.b))
+		default:
+			panic("impossible")
+		}
+
+		if r.String() != sp.expected_result || r.Status()&(ErrorMask|Inexact) != sp.expected_status {
+			t.Fatalf("sample %d, %s %s %s: %s %s != %s %s (expected)", i, sp.operation, sp.a, sp.b, r, r.Status(), sp.expected_result, sp.expected_status)
+		}
+
+		if r.Context() != sp.ctx {
+			t.Fatalf("sample %d, %s %s %s: context of result has changed", i, sp.operation, sp.a, sp.b)
+		}
+	}
+}
+
+func Test_big_context(t *testing.T) {
+
+	var zero BigDecimal
+
+	if zero.String() != "0" || zero.Context().Digits() != DecquadPmax || zero.Context().Rounding() != RoundHalfEven {
+		t.Fatalf("zero value: %s %d %s", zero, zero.Context().Digits(), zero.Context().Rounding())
+	}
+
+	if r := zero.Add(BigContext{}.FromInt64(1)).Div(BigContext{}.FromInt64(3)); r.String() != "0.3333333333333333333333333333333333" {
+		t.Fatalf("zero context: %s", r)
+	}
+
+	for _, digits := range []int32{0, -1, BigMaxDigits + 1} {
+		if r, err := NewBigContext(digits, RoundHalfEven).FromString("1"); err == nil || !r.IsNaN() || r.Status()&InvalidContext == 0 {
+			t.Fatalf("invalid context %d: %s %s", digits, r, r.Status())
+		}
+	}
+
+	a := must_big(NewBigContext(50, RoundHalfEven), "1.23456789012345678901234567890123456789")
+
+	if r := a.WithContext(NewBigContext(10, RoundDown)); r.String() != "1.234567890" || r.Status() != Inexact || r.Context().Digits() != 10 {
+		t.Fatalf("WithContext: %s %s", r, r.Status())
+	}
+
+	if r := a.SetStatusFlags(DivisionByZero).Add(a); r.Error() == nil || r.ClearStatusFlags(DivisionByZero).Error() != nil {
+		t.Fatalf("status propagation: %s %s", r, r.Status())
+	}
+
+	if d := a.Digits(); d != 39 {
+		t.Fatalf("Digits: %d", d)
+	}
+
+	ctx5000 := NewBigContext(5000, RoundHalfEven)
+
+	if r := ctx5000.FromInt64(2).Sqrt(); r.Digits() != 5000 || r.Status() != Inexact || !r.Mul(r).Sub(ctx5000.FromInt64(2)).Abs().Less(must_big(ctx5000, "1E-4998")) {
+		t.Fatalf("sqrt(2) with 5000 digits: %d digits, %s", r.Digits(), r.Status())
+	}
+}
+
+func Test_big_conversions(t *testing.T) {
+
+	ctx50 := NewBigContext(50, RoundHalfEven)
+	ctx10 := NewBigContext(10, RoundHalfEven)
+
+	a := must_big(ctx50, "1.23456789012345678901234567890123456789")
+
+	if q := a.ToQuad(RoundHalfEven); q.String() != "1.234567890123456789012345678901235" || q.Status() != Inexact {
+		t.Fatalf("ToQuad RoundHalfEven: %s %s", q, q.Status())
+	}
+
+	if q := a.ToQuad(RoundDown); q.String() != "1.234567890123456789012345678901234" || q.Status() != Inexact {
+		t.Fatalf("ToQuad RoundDown: %s %s", q, q.Status())
+	}
+
+	if q := must_big(ctx50, "1E+7000").ToQuad(RoundHalfEven); !q.IsInfinite() || q.Status()&Overflow == 0 {
+		t.Fatalf("ToQuad overflow: %s %s", q, q.Status())
+	}
+
+	samples := []struct {
+		ctx             BigContext
+		a               string
+		expected_result string
+		expected_status Status
+	}{
+		{ctx50, "1.234567890123456789012345678901234", "1.234567890123456789012345678901234", 0},
+		{ctx10, "1.234567890123456789012345678901234", "1.234567890", Inexact},
+		{ctx50, "-0.00", "0.00", 0},
+		{ctx50, "1E-6176", "1E-6176", 0},
+		{ctx50, "-Inf", "-Infinity", 0},
+		{ctx50, "NaN", "NaN", 0},
+	}
+
+	for i, sp := range samples {
+		r := sp.ctx.FromQuad(must_quad(sp.a))
+
+		if r.String() != sp.expected_result || r.Status() != sp.expected_status {
+			t.Fatalf("sample %d, FromQuad %s: %s %s != %s %s (expected)", i, sp.a, r, r.Status(), sp.expected_result, sp.expected_status)
+		}
+
+		if r.IsFinite() && r.Status() == 0 && r.ToQuad(RoundHalfEven).Bytes() != must_quad(sp.a).Bytes() {
+			t.Fatalf("sample %d, round trip %s: %s", i, sp.a, r.ToQuad(RoundHalfEven))
+		}
+	}
+
+	if r := ctx50.FromInt64(-9223372036854775808); r.String() != "-9223372036854775808" {
+		t.Fatalf("FromInt64: %s", r)
+	}
+
+	if r := ctx10.FromInt64(-9223372036854775808); r.String() != "-9.223372037E+18" || r.Status() != Inexact {
+		t.Fatalf("FromInt64 rounded: %s", r)
+	}
+
+	// formatting
+
+	formats := []struct {
+		a                  string
+		expected_string    string
+		expected_to_string string
+	}{
+		{"0.0000001", "0.0000001", "1E-7"},
+		{"-12.345", "-12.345", "-12.345"},
+		{"1E+3", "1E+3", "1E+3"},
+		{"1E-50", "0.00000000000000000000000000000000000000000000000001", "1E-50"},
+		{"1E-51", "1E-51", "1E-51"},
+		{"-0E-3", "0.000", "0.000"},
+		{"sNaN", "sNaN", "sNaN"},
+	}
+
+	for i, sp := range formats {
+		r := must_big(ctx50, sp.a)
+
+		if s := r.String(); s != sp.expected_string {
+			t.Fatalf("sample %d, String %s: %s != %s (expected)", i, sp.a, s, sp.expected_string)
+		}
+
+		if s := r.BigToString(); s != sp.expected_to_string {
+			t.Fatalf("sample %d, BigToString %s: %s != %s (expected)", i, sp.a, s, sp.expected_to_string)
+		}
+	}
+
+	// comparison
+
+	b := must_big(ctx50, "1.5")
+	c := must_big(ctx10, "1.50")
+	nan := must_big(ctx50, "NaN")
+
+	if !b.Equal(c) || !b.LessEqual(c) || !b.GreaterEqual(c) || b.Less(c) || b.Greater(c) || !a.Less(b) || !b.Greater(a) {
+		t.Fatalf("comparison of %s, %s, %s", a, b, c)
+	}
+
+	if b.Equal(nan) || b.Less(nan) || nan.Greater(b) {
+		t.Fatalf("comparison with NaN should be false")
+	}
+
+	if !b.IsPositive() || b.IsNegative() || !b.Neg().IsNegative() || !nan.IsNaN() || nan.IsFinite() || !must_big(ctx50, "Inf").IsInfinite() || !must_big(ctx50, "0.00").IsZero() {
+		t.Fatalf("IsPositive, IsNegative, IsNaN, IsFinite, IsInfinite or IsZero failed")
+	}
+
+	if e := c.GetExponent(); e != -2 {
+		t.Fatalf("GetExponent: %d", e)
+	}
+}
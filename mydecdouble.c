@@ -0,0 +1,607 @@
+#include "mydecquad.h"
+
+
+/************************************************************************/
+/*                 global constant for Round and Truncate               */
+/************************************************************************/
+
+/* decNumber constants for decDouble rounding.
+
+   It contains
+      - 1e0
+      - 1e-1
+      - ...
+      - 1e-DECDOUBLE_Pmax      (1e-16)
+*/
+static decDouble G_DECDOUBLE_QUANTIZER[DECDOUBLE_Pmax+1];  // 0...16
+
+
+/* decNumber constants for decDouble rounding.
+
+   It contains
+      - 1e0
+      - 1e1
+      - ...
+      - 1e(DECDOUBLE_Pmax+1)   (1e17)
+
+    See G_DECQUAD_INTEGRAL_PART_QUANTIZER in mydecquad.c for the reason of the extra quantizer.
+*/
+static decDouble G_DECDOUBLE_INTEGRAL_PART_QUANTIZER[DECDOUBLE_Pmax+2];  // 0...17
+
+
+/************************************************************************/
+/*                                 init                                 */
+/************************************************************************/
+
+
+/* initialize the global constants used by the Double functions.
+
+   It is called by Go in init() function.
+
+   Exit(1) if an error occurs.
+*/
+void mdd_init(void) {
+
+  decContext   set;
+  const char  *s;
+  int          i;
+
+
+  assert( DECDOUBLE_Pmax == 16 );              // we have 16 digits max precision (number of significant digits).
+
+
+  //----- fill decContext -----
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+
+
+  //----- fill G_DECDOUBLE_QUANTIZER[] -----
+
+  decDoubleFromInt32(&G_DECDOUBLE_QUANTIZER[0], 1);                         //  store  1e0  in G_DECDOUBLE_QUANTIZER[0]
+
+  for ( i=1; i<=DECDOUBLE_Pmax; i++ ) {                                     // in G_DECDOUBLE_QUANTIZER[1..DECDOUBLE_Pmax]
+      decDoubleCopy(&G_DECDOUBLE_QUANTIZER[i], &G_DECDOUBLE_QUANTIZER[0]);
+
+      decDoubleSetExponent(&G_DECDOUBLE_QUANTIZER[i], &set, -i);            // store 1e-1 .. 1e-DECDOUBLE_Pmax
+  }
+
+  assert( decDoubleGetExponent(&G_DECDOUBLE_QUANTIZER[DECDOUBLE_Pmax]) == -DECDOUBLE_Pmax );  // -16
+
+
+  //----- fill G_DECDOUBLE_INTEGRAL_PART_QUANTIZER[] -----
+
+  decDoubleFromInt32(&G_DECDOUBLE_INTEGRAL_PART_QUANTIZER[0], 1);                //  store  1e0  in G_DECDOUBLE_INTEGRAL_PART_QUANTIZER[0]
+
+  for ( i=1; i<=DECDOUBLE_Pmax+1; i++ ) {                                        // in G_DECDOUBLE_INTEGRAL_PART_QUANTIZER[1..DECDOUBLE_Pmax+1]
+      decDoubleCopy(&G_DECDOUBLE_INTEGRAL_PART_QUANTIZER[i], &G_DECDOUBLE_INTEGRAL_PART_QUANTIZER[0]);
+
+      decDoubleSetExponent(&G_DECDOUBLE_INTEGRAL_PART_QUANTIZER[i], &set, i);    // store 1e1 .. 1e(DECDOUBLE_Pmax+1)
+  }
+
+  assert( decDoubleGetExponent(&G_DECDOUBLE_INTEGRAL_PART_QUANTIZER[DECDOUBLE_Pmax+1]) == DECDOUBLE_Pmax+1 );  // 17
+
+
+  //----- check for errors or any warning -----
+
+  if ( set.status ) {
+      s = decContextStatusToString(&set);
+      fprintf(stderr, "INITIALIZATION mydecdouble.c:mdd_init() FAILED: decNumber quantizer initialization failed. %s\n", s);
+      exit(1);
+  }
+
+}
+
+
+/************************************************************************/
+/*                        arithmetic operations                         */
+/************************************************************************/
+
+
+/* returns 0E0.
+*/
+decDouble mdd_zero() {
+  decDouble  val;
+
+  decDoubleZero(&val);
+
+  return val;
+}
+
+
+/* returns NaN.
+*/
+decDouble mdd_nan() {
+  decContext set;
+  decDouble  val;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+
+  decDoubleFromString(&val, "Nan", &set);
+
+  return val;
+}
+
+
+/* unary minus.
+*/
+Double mdd_minus(Double a) {
+  decContext  set;
+  Double      res;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+  set.status = a.status;
+
+  decDoubleMinus(&res.val, &a.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* addition.
+*/
+Double mdd_add(Double a, Double b) {
+  decContext  set;
+  Double      res;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+  set.status = a.status | b.status;
+
+  decDoubleAdd(&res.val, &a.val, &b.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* subtraction.
+*/
+Double mdd_subtract(Double a, Double b) {
+  decContext  set;
+  Double      res;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+  set.status = a.status | b.status;
+
+  decDoubleSubtract(&res.val, &a.val, &b.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* multiplication.
+*/
+Double mdd_multiply(Double a, Double b) {
+  decContext  set;
+  Double      res;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+  set.status = a.status | b.status;
+
+  decDoubleMultiply(&res.val, &a.val, &b.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* division.
+*/
+Double mdd_divide(Double a, Double b) {
+  decContext  set;
+  Double      res;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+  set.status = a.status | b.status;
+
+  decDoubleDivide(&res.val, &a.val, &b.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* integer division.
+*/
+Double mdd_divide_integer(Double a, Double b) {
+  decContext  set;
+  Double      res;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+  set.status = a.status | b.status;
+
+  decDoubleDivideInteger(&res.val, &a.val, &b.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* modulo.
+*/
+Double mdd_remainder(Double a, Double b) {
+  decContext  set;
+  Double      res;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+  set.status = a.status | b.status;
+
+  decDoubleRemainder(&res.val, &a.val, &b.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* max.
+*/
+Double mdd_max(Double a, Double b) {
+  decContext  set;
+  Double      res;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+  set.status = a.status | b.status;
+
+  decDoubleMax(&res.val, &a.val, &b.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* min.
+*/
+Double mdd_min(Double a, Double b) {
+  decContext  set;
+  Double      res;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+  set.status = a.status | b.status;
+
+  decDoubleMin(&res.val, &a.val, &b.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* to integral value.
+*/
+Double mdd_to_integral(Double a, int round) {
+  decContext  set;
+  Double      res;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+  set.status = a.status;
+
+  decDoubleToIntegralValue(&res.val, &a.val, &set, round); // The DEC_Inexact flag is not set by this function, even if rounding ocurred.
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* quantize.
+*/
+Double mdd_quantize(Double a, Double b, int round) {
+  decContext  set;
+  Double      res;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+  decContextSetRounding(&set, round);      // change rounding mode
+  set.status = a.status | b.status;
+
+  decDoubleQuantize(&res.val, &a.val, &b.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* absolute value.
+*/
+Double mdd_abs(Double a) {
+  decContext  set;
+  Double      res;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+  set.status = a.status;
+
+  decDoubleAbs(&res.val, &a.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* power.
+
+   decDouble has no power function, so decNumberPower() is used, as for mdq_power().
+*/
+Double mdd_power(Double a, Double b) {
+  decContext  set;
+  Double      res;
+  decNumber   a_num;   // DECNUMDIGITS is DECIMAL128_Pmax, which is more than enough for decDouble
+  decNumber   b_num;
+  decNumber   res_num;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+  set.status = a.status | b.status;
+
+  decDoubleToNumber(&a.val, &a_num);
+  decDoubleToNumber(&b.val, &b_num);
+
+  decNumberPower(&res_num, &a_num, &b_num, &set);
+
+  decDoubleFromNumber(&res.val, &res_num, &set);
+  res.status = decContextGetStatus(&set) & ~(DEC_Rounded | DEC_Subnormal | DEC_Clamped);   // decNumber sets these flags, which are not used by this package
+
+  return res;
+}
+
+
+/* square root.
+
+   decNumberSquareRoot() rounds with DEC_ROUND_HALF_EVEN, to the 16 digits of the context.
+*/
+Double mdd_sqrt(Double a) {
+  decContext  set;
+  Double      res;
+  decNumber   a_num;
+  decNumber   res_num;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+  set.status = a.status;
+
+  decDoubleToNumber(&a.val, &a_num);
+
+  decNumberSquareRoot(&res_num, &a_num, &set);
+
+  decDoubleFromNumber(&res.val, &res_num, &set);
+  res.status = decContextGetStatus(&set) & ~(DEC_Rounded | DEC_Subnormal | DEC_Clamped);   // decNumber sets these flags, which are not used by this package
+
+  return res;
+}
+
+
+/************************************************************************/
+/*                           is_finite, etc                             */
+/************************************************************************/
+
+
+/* check if a is Finite number.
+*/
+uint32_t mdd_is_finite(decDouble a) {
+
+  return decDoubleIsFinite(&a);
+}
+
+
+/* check if a is Infinite.
+*/
+uint32_t mdd_is_infinite(decDouble a) {
+
+  return decDoubleIsInfinite(&a);
+}
+
+
+/* check if a is Nan.
+*/
+uint32_t mdd_is_nan(decDouble a) {
+
+  return decDoubleIsNaN(&a);
+}
+
+
+/* check if a is > 0 and not Nan.
+*/
+uint32_t mdd_is_positive(decDouble a) {
+
+  return decDoubleIsPositive(&a);
+}
+
+
+/* check if a is == 0.
+*/
+uint32_t mdd_is_zero(decDouble a) {
+
+  return decDoubleIsZero(&a);
+}
+
+
+/* check if a is < 0 and not Nan.
+*/
+uint32_t mdd_is_negative(decDouble a) {
+
+  return decDoubleIsNegative(&a);
+}
+
+
+/* check if the encoding of a is canonical.
+*/
+uint32_t mdd_is_canonical(decDouble a) {
+
+  return decDoubleIsCanonical(&a);
+}
+
+
+/* get exponent.
+*/
+int32_t mdd_get_exponent(decDouble a) {
+
+  return decDoubleGetExponent(&a);
+}
+
+
+/************************************************************************/
+/*                               comparison                             */
+/************************************************************************/
+
+
+/* compare.
+*/
+uint32_t mdd_compare(Double a, Double b) {
+  decContext      set;
+  decDouble       cmp_val;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+  set.status = a.status | b.status;
+
+
+  decDoubleCompare(&cmp_val, &a.val, &b.val, &set); // result may be –1, 0, 1, or NaN. NaN is returned only if a or b is a NaN.
+
+  if ( decDoubleIsNaN(&cmp_val) ) {
+      return CMP_NAN;
+  }
+
+  if ( decDoubleIsZero(&cmp_val) ) {
+      return CMP_EQUAL;
+  }
+
+  if ( decDoubleIsPositive(&cmp_val) ) {
+      return CMP_GREATER;
+  }
+
+  assert( decDoubleIsNegative(&cmp_val) );
+
+  return CMP_LESS;
+}
+
+
+/************************************************************************/
+/*                    conversion from string or numbers                 */
+/************************************************************************/
+
+
+/* conversion from string.
+*/
+Double mdd_from_string(char *s) {
+  decContext  set;
+  Double      res;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+
+  decDoubleFromString(&res.val, s, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* conversion from int32.
+*/
+Double mdd_from_int32(int32_t value) {
+  Double      res;
+
+  decDoubleFromInt32(&res.val, value);
+  res.status = 0; // never fails
+
+  return res;
+}
+
+
+/* conversion from int64.
+
+   A int64 can have 19 digits, so the result is rounded if it has more than 16 digits, and Inexact flag is set.
+*/
+Double mdd_from_int64(int64_t value) {
+  char         buff[30]; // more than enough to store a int64     max val: 9,223,372,036,854,775,807
+  decContext  set;
+  Double      res;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+
+  sprintf(buff, "%lld", (long long int)value); // write value into buffer
+
+  decDoubleFromString(&res.val, buff, &set);   // rounds to 16 digits if necessary
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/************************************************************************/
+/*                         conversion to and from Quad                  */
+/************************************************************************/
+
+
+/* widening to decQuad. It is always exact.
+*/
+Quad mdd_to_quad(Double a) {
+  Quad        res;
+
+  decDoubleToWider(&a.val, &res.val);
+  res.status = a.status;
+
+  return res;
+}
+
+
+/* narrowing from decQuad.
+
+   The coefficient is rounded to 16 digits with DEC_ROUND_HALF_EVEN. Inexact, Overflow or Underflow flags are set if necessary.
+*/
+Double mdd_from_quad(Quad a) {
+  decContext  set;
+  Double      res;
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+  set.status = a.status;
+
+  decDoubleFromWider(&res.val, &a.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/************************************************************************/
+/*                       rounding and truncating                        */
+/************************************************************************/
+
+
+Double mdd_roundM(Double a, int32_t n, int round) {
+  decContext        set;
+  decDouble         r;
+  decDouble        *operation_quantizer;
+  Double            res;
+
+
+  decContextDefault(&set, DEC_INIT_DECDOUBLE);
+  set.status = a.status;
+
+
+  // if n is out-of-range, return Invalid_operation
+
+  if ( n > 16 || n < -17 ) {
+      decContextSetStatus(&set, DEC_Invalid_operation); // add flag to status
+
+      res.val = mdd_nan();
+      res.status = decContextGetStatus(&set);
+      return res;
+  }
+
+
+  // operation
+
+  decContextSetRounding(&set, round);                               // change rounding mode
+
+  if ( n >= 0 ) {   // round or truncate fractional part
+      operation_quantizer = &G_DECDOUBLE_QUANTIZER[n];                  // n is [0..16]
+
+      decDoubleQuantize(&res.val, &a.val, operation_quantizer, &set);   // rounding, e.g. quantize(1234.5678, 2)  --> 1234.57
+
+  } else {          // n < 0, round or truncate integral part
+      operation_quantizer = &G_DECDOUBLE_INTEGRAL_PART_QUANTIZER[-n];   // -n is [0..17]
+
+      decDoubleQuantize(&r, &a.val, operation_quantizer, &set);               // rounding, e.g. quantize(1234.5678, -2) --> 12E2
+      decDoubleQuantize(&res.val, &r, &G_DECDOUBLE_QUANTIZER[0], &set);   // right-shift the number, adding missing 0s on the left. E.g. 12E2 --> 1200E0
+  }
+
+
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
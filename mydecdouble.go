@@ -0,0 +1,667 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                          Double value and status                     */
+/*                                                                      */
+/************************************************************************/
+
+// Double contains a 64bits decimal floating-point value, with 16 digits of precision, and a status like Quad.
+//
+// The value needs 8 bytes instead of 16 for a Quad. It is useful to store many numbers that fit in 16 digits, e.g. prices.
+// The operations on Double round their result to 16 digits.
+//
+// A Double can always be converted to a Quad without loss, with ToQuad.
+// A Quad is converted to a Double with Quad.ToDouble, which sets Inexact or Overflow flags if the value doesn't fit.
+//
+type Double C.Double // array of 8 bytes, + 2 bytes for status field
+
+const (
+	DecdoublePmax   = C.DECDOUBLE_Pmax   // number of digits in coefficient == 16
+	DecdoubleBytes  = C.DECDOUBLE_Bytes  // size in bytes of decDouble == 8
+	DecdoubleString = C.DECDOUBLE_String // buffer capacity for C.decDoubleToString()
+)
+
+func init() {
+	C.mdd_init()
+
+	if DecdoubleBytes != 8 { // 8 bytes == 64 bits
+		panic("DECDOUBLE_Bytes != 8")
+	}
+}
+
+var (
+	g_double_nan  Double = Double{val: C.mdd_nan(), status: 0}  // a constant Double with value NaN. It runs BEFORE init().
+	g_double_zero Double = Double{val: C.mdd_zero(), status: 0} // a constant Double with value 0.   It runs BEFORE init().
+	g_double_one  Double = DoubleFromInt32(1)                   // a constant Double with value 1.   It runs BEFORE init().
+)
+
+// Status returns the status field of the Double.
+// It contains error flags, like 'DivisionByZero', and informational flags, like 'Inexact'.
+//
+func (a Double) Status() Status {
+
+	return Status(a.status)
+}
+
+// ErrorStatus returns the status field of the Double.
+// It contains only error flags.
+// Same as a.Status()&ErrorMask
+//
+func (a Double) ErrorStatus() Status {
+
+	return Status(a.status) & ErrorMask
+}
+
+// Error returns an error if an error flag bit has been set in Double's status field.
+//
+// It returns QuadError(a.ErrorStatus()), or nil if no error.
+//
+func (a Double) Error() error {
+	var errorFlags Status
+
+	errorFlags = Status(a.status) & ErrorMask
+
+	if errorFlags == 0 {
+		return nil
+	}
+
+	return QuadError(errorFlags)
+}
+
+// DoubleZero returns 0 Double value.
+//
+//     r = DoubleZero()  // assign 0 to the Double r
+//
+func DoubleZero() (r Double) {
+
+	return g_double_zero
+}
+
+// DoubleOne returns 1 Double value.
+//
+//     r = DoubleOne()  // assign 1 to the Double r
+//
+func DoubleOne() (r Double) {
+
+	return g_double_one
+}
+
+// DoubleNaN returns NaN Double value.
+//
+//     r = DoubleNaN()  // assign NaN to the Double r
+//
+func DoubleNaN() (r Double) {
+
+	return g_double_nan
+}
+
+// MustParseDouble returns a Double from a string, like DoubleFromString.
+// It panics if the string is not a valid number.
+//
+// The Inexact flag is not an error: a string with more than 16 digits is rounded.
+//
+func MustParseDouble(s string) Double {
+	var val Double
+	var err error
+
+	if val, err = DoubleFromString(s); err != nil {
+		panic(fmt.Sprintf("decnum: MustParseDouble(%q) failed: %s", s, err.Error()))
+	}
+
+	return val
+}
+
+// ClearStatus returns a copy of a, whith status field cleared.
+//
+func (a Double) ClearStatus() Double {
+
+	a.status = 0
+	return a
+}
+
+// SetStatusFlags returns a copy of a, setting the status flags specified by argument.
+// Status of result is a.status|statusflags.
+//
+func (a Double) SetStatusFlags(statusflags Status) Double {
+
+	a.status |= C.uint16_t(statusflags)
+
+	return a
+}
+
+// ClearStatusFlags returns a copy of a, clearing the status flags specified by argument.
+// Status of result is a.status&^statusflags.
+//
+func (a Double) ClearStatusFlags(statusflags Status) Double {
+
+	a.status &^= C.uint16_t(statusflags)
+
+	return a
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                      arithmetic operations                           */
+/*                                                                      */
+/************************************************************************/
+
+// Neg returns -a.
+//
+func (a Double) Neg() Double {
+
+	return Double(C.mdd_minus(C.struct_Double(a)))
+}
+
+// Add returns a + b.
+//
+func (a Double) Add(b Double) Double {
+
+	return Double(C.mdd_add(C.struct_Double(a), C.struct_Double(b)))
+}
+
+// Sub returns a - b.
+//
+func (a Double) Sub(b Double) Double {
+
+	return Double(C.mdd_subtract(C.struct_Double(a), C.struct_Double(b)))
+}
+
+// Mul returns a * b.
+//
+func (a Double) Mul(b Double) Double {
+
+	return Double(C.mdd_multiply(C.struct_Double(a), C.struct_Double(b)))
+}
+
+// Div returns a/b.
+//
+func (a Double) Div(b Double) Double {
+
+	return Double(C.mdd_divide(C.struct_Double(a), C.struct_Double(b)))
+}
+
+// DivInt returns the integral part of a/b.
+//
+func (a Double) DivInt(b Double) Double {
+
+	return Double(C.mdd_divide_integer(C.struct_Double(a), C.struct_Double(b)))
+}
+
+// Mod returns the modulo of a and b.
+//
+func (a Double) Mod(b Double) Double {
+
+	return Double(C.mdd_remainder(C.struct_Double(a), C.struct_Double(b)))
+}
+
+// DoubleMax returns the larger of a and b.
+// If either a or b is NaN then the other argument is the result.
+//
+func DoubleMax(a Double, b Double) Double {
+
+	return Double(C.mdd_max(C.struct_Double(a), C.struct_Double(b)))
+}
+
+// DoubleMin returns the smaller of a and b.
+// If either a or b is NaN then the other argument is the result.
+//
+func DoubleMin(a Double, b Double) Double {
+
+	return Double(C.mdd_min(C.struct_Double(a), C.struct_Double(b)))
+}
+
+// ToIntegral returns the value of a rounded to an integral value.
+//
+// See Quad.ToIntegral.
+//
+func (a Double) ToIntegral(rounding RoundingMode) Double {
+
+	return Double(C.mdd_to_integral(C.struct_Double(a), C.int(rounding)))
+}
+
+// Quantize rounds a to the same pattern as b.
+// b is just a model, its sign and coefficient value are ignored. Only its exponent is used.
+//
+// See Quad.Quantize. If the result needs more than 16 digits, the InvalidOperation flag is set.
+//
+func (a Double) Quantize(b Double, rounding RoundingMode) Double {
+
+	return Double(C.mdd_quantize(C.struct_Double(a), C.struct_Double(b), C.int(rounding)))
+}
+
+// Abs returns the absolute value of a.
+//
+func (a Double) Abs() Double {
+
+	return Double(C.mdd_abs(C.struct_Double(a)))
+}
+
+// Pow returns a raised to the power of b, rounded to 16 digits.
+//
+// See Quad.Pow.
+//
+func (a Double) Pow(b Double) Double {
+
+	return Double(C.mdd_power(C.struct_Double(a), C.struct_Double(b)))
+}
+
+// Sqrt returns the square root of a, correctly rounded to 16 digits with RoundHalfEven mode.
+//
+// Sqrt(a) is NaN if a < 0, and sets the InvalidOperation flag.
+//
+func (a Double) Sqrt() Double {
+
+	return Double(C.mdd_sqrt(C.struct_Double(a)))
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                            IsFinite, etc                             */
+/*                                                                      */
+/************************************************************************/
+
+// IsFinite returns true if a is not Infinite, nor Nan.
+//
+func (a Double) IsFinite() bool {
+
+	return C.mdd_is_finite(a.val) != 0
+}
+
+// IsInfinite returns true if a is Infinite.
+//
+func (a Double) IsInfinite() bool {
+
+	return C.mdd_is_infinite(a.val) != 0
+}
+
+// IsNaN returns true if a is Nan.
+//
+func (a Double) IsNaN() bool {
+
+	return C.mdd_is_nan(a.val) != 0
+}
+
+// IsPositive returns true if a > 0 and not Nan.
+//
+func (a Double) IsPositive() bool {
+
+	return C.mdd_is_positive(a.val) != 0
+}
+
+// IsZero returns true if a == 0.
+//
+func (a Double) IsZero() bool {
+
+	return C.mdd_is_zero(a.val) != 0
+}
+
+// IsNegative returns true if a < 0 and not NaN.
+//
+func (a Double) IsNegative() bool {
+
+	return C.mdd_is_negative(a.val) != 0
+}
+
+// GetExponent returns the exponent of a.
+// It can returns special values such as ExpNaN, ExpSignalingNaN or ExpInf if a is NaN, sNaN or Infinity.
+//
+func (a Double) GetExponent() int32 {
+
+	return int32(C.mdd_get_exponent(a.val))
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                            comparison                                */
+/*                                                                      */
+/************************************************************************/
+
+// Greater is true if a > b.
+//
+func (a Double) Greater(b Double) bool {
+
+	return CmpFlag(C.mdd_compare(C.struct_Double(a), C.struct_Double(b)))&CmpGreater != 0
+}
+
+// GreaterEqual is true if a >= b.
+//
+func (a Double) GreaterEqual(b Double) bool {
+
+	return CmpFlag(C.mdd_compare(C.struct_Double(a), C.struct_Double(b)))&(CmpGreater|CmpEqual) != 0
+}
+
+// Equal is true if a == b.
+//
+func (a Double) Equal(b Double) bool {
+
+	return CmpFlag(C.mdd_compare(C.struct_Double(a), C.struct_Double(b)))&CmpEqual != 0
+}
+
+// LessEqual is true if a <= b.
+//
+func (a Double) LessEqual(b Double) bool {
+
+	return CmpFlag(C.mdd_compare(C.struct_Double(a), C.struct_Double(b)))&(CmpLess|CmpEqual) != 0
+}
+
+// Less is true if a < b.
+//
+func (a Double) Less(b Double) bool {
+
+	return CmpFlag(C.mdd_compare(C.struct_Double(a), C.struct_Double(b)))&CmpLess != 0
+}
+
+// Cmp compares a and b.
+// It returns CmpLess, CmpEqual or CmpGreater, or CmpNaN if a or b is NaN.
+//
+func (a Double) Cmp(b Double) CmpFlag {
+
+	return CmpFlag(C.mdd_compare(C.struct_Double(a), C.struct_Double(b)))
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                   conversion from string and numbers                 */
+/*                                                                      */
+/************************************************************************/
+
+// DoubleFromString returns a Double from a string.
+//
+// The accepted strings are the same as for FromString.
+// If the number has more than 16 digits, it is rounded, and the Inexact flag is set.
+//
+// This function returns result.Error() as a convenience.
+//
+func DoubleFromString(s string) (result Double, err error) {
+	var cs *C.char
+
+	s = strings.TrimSpace(s)
+
+	cs = C.CString(s)
+	defer C.free(unsafe.Pointer(cs))
+
+	result = Double(C.mdd_from_string(cs))
+
+	return result, result.Error()
+}
+
+// DoubleFromInt32 returns a Double from a int32 value.
+//
+// No error occurs.
+//
+func DoubleFromInt32(value int32) Double {
+
+	return Double(C.mdd_from_int32(C.int32_t(value)))
+}
+
+// DoubleFromInt64 returns a Double from a int64 value.
+//
+// A int64 can have up to 19 digits. If value has more than 16 digits, it is rounded, and the Inexact flag is set.
+//
+func DoubleFromInt64(value int64) Double {
+
+	return Double(C.mdd_from_int64(C.int64_t(value)))
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                       conversion to and from Quad                    */
+/*                                                                      */
+/************************************************************************/
+
+// ToQuad returns the value of a as a Quad.
+//
+// The conversion is always exact. The status of a is kept.
+//
+func (a Double) ToQuad() Quad {
+
+	return Quad(C.mdd_to_quad(C.struct_Double(a)))
+}
+
+// ToDouble returns the value of a as a Double.
+//
+// The coefficient is rounded to 16 digits with RoundHalfEven, and the Inexact flag is set if the value has changed.
+// If a is too large for a Double, the result is Infinity and the Overflow flag is set.
+// If a is too small, the result is a subnormal number or 0, and the Underflow flag is set.
+//
+//      1.2345678901234567890    gives   1.234567890123457           Inexact
+//      1E+385                   gives   Infinity                    Overflow|Inexact
+//
+func (a Quad) ToDouble() Double {
+
+	return Double(C.mdd_from_quad(C.struct_Quad(a)))
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                      conversion to string                            */
+/*                                                                      */
+/************************************************************************/
+
+// DoubleToString returns the string representation of a Double number.
+// It uses exponential notation as often as Quad.QuadToString.
+//
+func (a Double) DoubleToString() string {
+
+	return a.ToQuad().QuadToString()
+}
+
+// AppendDouble appends string representation of Double into byte slice.
+// The number is written as AppendQuad would write it.
+//
+func AppendDouble(dst []byte, a Double) []byte {
+
+	return AppendQuad(dst, a.ToQuad())
+}
+
+// String is the preferred way to display a Double number.
+// It calls AppendDouble internally.
+//
+func (a Double) String() string {
+	var buffer []byte
+
+	buffer = pool.Get().([]byte)[:0]
+	defer pool.Put(buffer)
+
+	ss := AppendDouble(buffer[:0], a)
+
+	return string(ss)
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                      conversion to number                            */
+/*                                                                      */
+/************************************************************************/
+
+// ToInt32 returns the int32 value from a.
+//
+func (a Double) ToInt32(rounding RoundingMode) (int32, error) {
+
+	return a.ToQuad().ToInt32(rounding)
+}
+
+// ToInt64 returns the int64 value from a.
+//
+func (a Double) ToInt64(rounding RoundingMode) (int64, error) {
+
+	return a.ToQuad().ToInt64(rounding)
+}
+
+// ToFloat64 returns the float64 value from a.
+//
+func (a Double) ToFloat64() (float64, error) {
+
+	return a.ToQuad().ToFloat64()
+}
+
+// ToBCD returns the coefficient of a, one digit per byte, and its exponent.
+//
+// bcd contains the 16 digits of the coefficient, most significant digit first, with leading zeros.
+// See Quad.ToBCD.
+//
+func (a Double) ToBCD() (bcd [DecdoublePmax]byte, exp int32, negative bool, err error) {
+	var quadBCD [DecquadPmax]byte
+
+	if quadBCD, exp, negative, err = a.ToQuad().ToBCD(); err != nil {
+		return bcd, 0, false, err
+	}
+
+	copy(bcd[:], quadBCD[DecquadPmax-DecdoublePmax:]) // the coefficient of a Double has at most 16 digits
+
+	return bcd, exp, negative, nil
+}
+
+// Bytes returns the internal byte representation of the value field of the Double.
+//
+func (a Double) Bytes() (res [DecdoubleBytes]byte) {
+
+	for i, b := range a.val {
+		res[i] = byte(b)
+	}
+
+	return res
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                      rounding and truncating                         */
+/*                                                                      */
+/************************************************************************/
+
+// RoundWithMode rounds (or truncate) 'a', with the mode passed as argument.
+//
+//  n must be in the range [-17...16]. Else, Invalid Operation flag is set, and NaN is returned.
+//
+func (a Double) RoundWithMode(n int32, rounding RoundingMode) Double {
+
+	return Double(C.mdd_roundM(C.struct_Double(a), C.int32_t(n), C.int(rounding)))
+}
+
+// Round rounds (or truncate) 'a', with RoundHalfEven mode.
+//
+//  n must be in the range [-17...16]. Else, Invalid Operation flag is set, and NaN is returned.
+//
+func (a Double) Round(n int32) Double {
+
+	return Double(C.mdd_roundM(C.struct_Double(a), C.int32_t(n), C.int(RoundHalfEven)))
+}
+
+// Truncate truncates 'a'.
+// It is like rounding with RoundDown.
+//
+//  n must be in the range [-17...16]. Else, Invalid Operation flag is set, and NaN is returned.
+//
+func (a Double) Truncate(n int32) Double {
+
+	return Double(C.mdd_roundM(C.struct_Double(a), C.int32_t(n), C.int(RoundDown)))
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                  decimal64 interchange encodings                     */
+/*                                                                      */
+/************************************************************************/
+
+// The value of a Double is an IEEE 754 decimal64 number, in DPD encoding. ToDPD and ToBID return it in the DPD or BID encoding.
+// The bytes are in big-endian order, the first byte containing the sign bit:
+//
+//      1            is    22 38 00 00 00 00 00 00 in DPD
+//      1            is    31 c0 00 00 00 00 00 01 in BID
+//
+// A Quad is converted with Quad.ToDouble, which sets the Inexact or Overflow flag in the status of the Double if the value doesn't fit.
+
+// IsCanonical returns true if the encoding of a is canonical.
+//
+func (a Double) IsCanonical() bool {
+
+	if C.mdd_is_canonical(a.val) != 0 {
+		return true
+	}
+
+	return false
+}
+
+// ToDPD returns the DPD encoding of a, in big-endian order.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Double) ToDPD() (b [DecdoubleBytes]byte) {
+
+	bytes := a.Bytes()
+
+	for i := range b {
+		if g_little_endian {
+			b[i] = bytes[DecdoubleBytes-1-i]
+		} else {
+			b[i] = bytes[i]
+		}
+	}
+
+	return b
+}
+
+// DoubleFromDPD returns a Double from its DPD encoding, in big-endian order.
+//
+// If the encoding of b is not canonical, NaN and an InvalidOperation error are returned.
+// On success, the status of the result is 0.
+//
+func DoubleFromDPD(b [DecdoubleBytes]byte) (Double, error) {
+	var r Double
+
+	for i := range r.val {
+		if g_little_endian {
+			r.val[i] = b[DecdoubleBytes-1-i]
+		} else {
+			r.val[i] = b[i]
+		}
+	}
+
+	if !r.IsCanonical() {
+		return DoubleNaN().SetStatusFlags(InvalidOperation), QuadError(InvalidOperation)
+	}
+
+	return r, nil
+}
+
+// ToBID returns the BID encoding of a, in big-endian order.
+//
+//      9999999999999999     gives   6c 73 86 f2 6f c0 ff ff
+//
+// The conversion is always exact.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Double) ToBID() (b [DecdoubleBytes]byte) {
+
+	binary.BigEndian.PutUint64(b[:], g_bid64.encode(a.ToQuad()))
+
+	return b
+}
+
+// DoubleFromBID returns a Double from its BID encoding, in big-endian order.
+//
+// All encodings are accepted, and the result is exact.
+// As required by IEEE 754, a non-canonical coefficient, larger than 10^16 - 1, is replaced by 0, keeping the sign and the exponent.
+// A NaN payload larger than 10^15 - 1 is replaced by 0.
+//
+func DoubleFromBID(b [DecdoubleBytes]byte) Double {
+
+	return g_bid64.decode(binary.BigEndian.Uint64(b[:])).ToDouble()
+}
@@ -0,0 +1,294 @@
+package decnum
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+// converts a string to Double or aborts.
+//
+func must_double(s string) Double {
+
+	return MustParseDouble(s)
+}
+
+func Test_double_operations(t *testing.T) {
+
+	samples := []struct {
+		operation       string
+		a               string
+		b               string
+		expected_result string
+		expected_status Status
+	}{
+		{"add", "12.34", "0.66", "13.00", 0},
+		{"add", "9999999999999999", "1", "1.000000000000000E+16", 0},
+		{"add", "9999999999999999", "2", "1.000000000000000E+16", Inexact},
+		{"add", "1234567890123456", "0.5", "1234567890123456", Inexact},
+		{"sub", "1", "0.0000000000000001", "0.9999999999999999", 0},
+		{"mul", "1.5", "-2", "-3.0", 0},
+		{"mul", "9.999999999999999E+384", "10", "Infinity", Overflow | Inexact},
+		{"div", "1", "3", "0.3333333333333333", Inexact},
+		{"div", "2", "3", "0.6666666666666667", Inexact},
+		{"div", "1", "0", "Infinity", DivisionByZero},
+		{"divint", "7", "2", "3", 0},
+		{"mod", "7", "2", "1", 0},
+		{"max", "7", "NaN", "7", 0},
+		{"min", "7", "-2", "-2", 0},
+		{"abs", "-2.5", "", "2.5", 0},
+		{"neg", "2.5", "", "-2.5", 0},
+		{"pow", "2", "10", "1024", 0},
+		{"pow", "2", "0.5", "1.414213562373095", Inexact},
+		{"pow", "1E-300", "2", "0E-398", Inexact | Underflow},
+		{"sqrt", "2", "", "1.414213562373095", Inexact},
+		{"sqrt", "16", "", "4", 0},
+		{"sqrt", "2E-398", "", "1.414213562373095E-199", Inexact},
+		{"sqrt", "-1", "", "NaN", InvalidOperation},
+		{"quantize", "134.6454", "0.01", "134.65", Inexact},
+		{"quantize", "1E+15", "0.01", "NaN", InvalidOperation},
+		{"integral", "12.5", "", "12", 0},
+		{"round", "1234.5678", "2", "1234.57", Inexact},
+		{"round", "1234.5678", "-2", "1200", Inexact},
+		{"round", "1234.5678", "17", "NaN", InvalidOperation},
+		{"truncate", "1234.5678", "2", "1234.56", Inexact},
+	}
+
+	for i, sp := range samples {
+		var r Double
+
+		a := must_double(sp.a)
+
+		switch sp.operation {
+		case "add":
+			r = a.Add(must_double(sp.b))
+		case "sub":
+			r = a.Sub(must_double(sp.b))
+		case "mul":
+			r = a.Mul(must_double(sp.b))
+		case "div":
+			r = a.Div(must_double(sp.b))
+		case "divint":
+			r = a.DivInt(must_double(sp.b))
+		case "mod":
+			r = a.Mod(must_double(sp.b))
+		case "max":
+			r = DoubleMax(a, must_double(sp.b))
+		case "min":
+			r = DoubleMin(a, must_double(sp.b))
+		case "abs":
+			r = a.Abs()
+		case "neg":
+			r = a.Neg()
+		case "pow":
+			r = a.Pow(must_double(sp.b))
+		case "sqrt":
+			r = a.Sqrt()
+		case "quantize":
+			r = a.Quantize(must_double(sp.b), RoundHalfEven)
+		case "integral":
+			r = a.ToIntegral(RoundHalfEven)
+		case "round":
+			n, _ := must_double(sp.b).ToInt32(RoundHalfEven)
+			r = a.Round(n)
+		case "truncate":
+			n, _ := must_double(sp.b).ToInt32(RoundHalfEven)
+			r = a.Truncate(n)
+		default:
+			panic("impossible")
+		}
+
+		if r.String() != sp.expected_result || r.Status() != sp.expected_status {
+			t.Fatalf("sample %d, %s %s %s: %s %s != %s %s (expected)", i, sp.operation, sp.a, sp.b, r, r.Status(), sp.expected_result, sp.expected_status)
+		}
+	}
+}
+
+func Test_double_comparison(t *testing.T) {
+
+	a := must_double("1.5")
+	b := must_double("1.50")
+	c := must_double("2")
+	nan := DoubleNaN()
+
+	if !a.Equal(b) || !a.Less(c) || !a.LessEqual(b) || !c.Greater(a) || !c.GreaterEqual(a) || a.Greater(c) {
+		t.Fatalf("comparison of %s, %s, %s", a, b, c)
+	}
+
+	if a.Equal(nan) || a.Less(nan) || a.Greater(nan) || nan.Equal(nan) {
+		t.Fatalf("comparison with NaN should be false")
+	}
+
+	if !DoubleZero().IsZero() || !DoubleOne().IsPositive() || !nan.IsNaN() || !must_double("-Inf").IsInfinite() || !must_double("-1").IsNegative() || !a.IsFinite() {
+		t.Fatalf("IsZero, IsPositive, IsNaN, IsInfinite, IsNegative or IsFinite failed")
+	}
+
+	if e := b.GetExponent(); e != -2 {
+		t.Fatalf("GetExponent %s: %d", b, e)
+	}
+}
+
+func Test_double_conversions(t *testing.T) {
+
+	samples := []struct {
+		s               string
+		expected_result string
+		expected_status Status
+	}{
+		{"12.34", "12.34", 0},
+		{"1234567890123456", "1234567890123456", 0},
+		{"1.2345678901234567890", "1.234567890123457", Inexact},
+		{"-0.00", "0.00", 0},
+		{"9.999999999999999E+384", "9.999999999999999E+384", 0},
+		{"1E+385", "Infinity", Overflow | Inexact},
+		{"1E-398", "1E-398", 0},
+		{"1.5E-398", "2E-398", Underflow | Inexact},
+		{"NaN", "NaN", 0},
+		{"-Inf", "-Infinity", 0},
+		{"hello", "NaN", ConversionSyntax},
+	}
+
+	for i, sp := range samples {
+		q, _ := FromString(sp.s)
+
+		d := q.ToDouble()
+		if d.String() != sp.expected_result || d.Status()&(ErrorMask|Inexact) != sp.expected_status {
+			t.Fatalf("sample %d, ToDouble %s: %s %s != %s %s (expected)", i, sp.s, d, d.Status(), sp.expected_result, sp.expected_status)
+		}
+
+		d, _ = DoubleFromString(sp.s)
+		if d.String() != sp.expected_result || d.Status()&(ErrorMask|Inexact) != sp.expected_status {
+			t.Fatalf("sample %d, DoubleFromString %s: %s %s != %s %s (expected)", i, sp.s, d, d.Status(), sp.expected_result, sp.expected_status)
+		}
+
+		if d.IsFinite() && d.Error() == nil {
+			if w := d.ToQuad(); w.String() != d.String() || w.ToDouble().Bytes() != d.Bytes() {
+				t.Fatalf("sample %d, round trip %s: %s", i, sp.s, w)
+			}
+		}
+	}
+
+	if d := DoubleFromInt64(1234567890123456789); d.String() != "1.234567890123457E+18" || d.Status() != Inexact {
+		t.Fatalf("DoubleFromInt64: %s %s", d, d.Status())
+	}
+
+	if n, err := must_double("-123456789012.7").ToInt64(RoundHalfEven); err != nil || n != -123456789013 {
+		t.Fatalf("ToInt64: %d %v", n, err)
+	}
+
+	if f, err := must_double("0.25").ToFloat64(); err != nil || f != 0.25 {
+		t.Fatalf("ToFloat64: %g %v", f, err)
+	}
+
+	if bcd, exp, negative, err := must_double("-12.345").ToBCD(); err != nil || bcd != [DecdoublePmax]byte{11: 1, 12: 2, 13: 3, 14: 4, 15: 5} || exp != -3 || !negative {
+		t.Fatalf("ToBCD: %v %d %t %v", bcd, exp, negative, err)
+	}
+
+	if s := must_double("1E-7").DoubleToString(); s != "1E-7" {
+		t.Fatalf("DoubleToString: %s", s)
+	}
+
+	if s := string(AppendDouble([]byte("x = "), must_double("1E-7"))); s != "x = 0.0000001" {
+		t.Fatalf("AppendDouble: %s", s)
+	}
+}
+
+func Test_double_encodings(t *testing.T) {
+
+	samples := []struct {
+		a            string
+		expected_dpd string
+		expected_bid string
+	}{
+		{"1", "2238000000000001", "31c0000000000001"},
+		{"-12.345", "a22c0000000049c5", "b160000000003039"},
+		{"0", "2238000000000000", "31c0000000000000"},
+		{"-0", "a238000000000000", "b1c0000000000000"},
+		{"9999999999999999", "6e38ff3fcff3fcff", "6c7386f26fc0ffff"},
+		{"9007199254740992", "6e380737d54f019e", "6c70000000000000"}, // 2^53, first coefficient with bits 62-61 set to 11 in BID
+		{"9.999999999999999E+384", "77fcff3fcff3fcff", "77fb86f26fc0ffff"},
+		{"1E-398", "0000000000000001", "0000000000000001"},
+		{"-Inf", "f800000000000000", "f800000000000000"},
+		{"NaN", "7c00000000000000", "7c00000000000000"},
+		{"-sNaN123", "fe000000000000a3", "fe0000000000007b"},
+	}
+
+	for i, sp := range samples {
+		a := must_double(sp.a)
+
+		dpd := a.ToDPD()
+		bid := a.ToBID()
+
+		if hex.EncodeToString(dpd[:]) != sp.expected_dpd || hex.EncodeToString(bid[:]) != sp.expected_bid {
+			t.Fatalf("sample %d, %s: %x %x != %s %s (expected)", i, sp.a, dpd, bid, sp.expected_dpd, sp.expected_bid)
+		}
+
+		if r, err := DoubleFromDPD(dpd); err != nil || r.Bytes() != a.Bytes() {
+			t.Fatalf("sample %d, DoubleFromDPD %x: %s %v", i, dpd, r, err)
+		}
+
+		if r := DoubleFromBID(bid); r.Bytes() != a.Bytes() || r.Status() != 0 {
+			t.Fatalf("sample %d, DoubleFromBID %x: %s %s", i, bid, r, r.Status())
+		}
+	}
+
+	// non-canonical encodings
+
+	if r, err := DoubleFromDPD([8]byte{0x22, 0x38, 0, 0, 0, 0, 0x03, 0xff}); err == nil || !r.IsNaN() || r.Status() != InvalidOperation {
+		t.Fatalf("DoubleFromDPD of non-canonical declet should fail: %s", r)
+	}
+
+	non_canonical := []struct {
+		bid             string
+		expected_result string
+	}{
+		{"6c7386f26fc10000", "0"},        // coefficient 10^16
+		{"edffffffffffffff", "-0E+49"},   // coefficient 2^53 + 2^51 - 1, exponent 49
+		{"7c038d7ea4c68000", "NaN"},      // payload 10^15
+		{"7c0000000000007b", "NaN123"},   // payload 123
+		{"7a00000000000001", "Infinity"}, // trailing bits are ignored
+	}
+
+	for i, sp := range non_canonical {
+		var b [8]byte
+
+		hex.Decode(b[:], []byte(sp.bid))
+
+		if r := DoubleFromBID(b); r.Bytes() != must_double(sp.expected_result).Bytes() {
+			t.Fatalf("non-canonical sample %d, DoubleFromBID %s: %s != %s (expected)", i, sp.bid, r, sp.expected_result)
+		}
+	}
+
+	// checked narrowing from Quad
+
+	d := must_quad("1.23456789012345678").ToDouble()
+
+	if bid := d.ToBID(); hex.EncodeToString(bid[:]) != "2fe462d53c8abac1" || d.Status() != Inexact {
+		t.Fatalf("narrowing: %x %s", bid, d.Status())
+	}
+
+	if d := must_quad("1E+385").ToDouble(); d.Status() != Overflow|Inexact {
+		t.Fatalf("narrowing overflow: %s %s", d, d.Status())
+	}
+
+	// round trip of random values
+
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		s := strconv.FormatUint(rnd.Uint64()%1e16>>uint(rnd.Intn(54)), 10) + "E" + strconv.Itoa(rnd.Intn(767)-398)
+		if rnd.Intn(2) == 0 {
+			s = "-" + s
+		}
+		a := must_double(s)
+
+		if r := DoubleFromBID(a.ToBID()); r.Bytes() != a.Bytes() {
+			t.Fatalf("BID round trip of %s: %s", s, r)
+		}
+
+		if r, err := DoubleFromDPD(a.ToDPD()); err != nil || r.Bytes() != a.Bytes() {
+			t.Fatalf("DPD round trip of %s: %s", s, r)
+		}
+	}
+}
@@ -0,0 +1,501 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+import (
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                          Fixed value and status                      */
+/*                                                                      */
+/************************************************************************/
+
+// Fixed is a fixed-point number, stored as an int64 unscaled value and a scale, which is the number of digits after the decimal point.
+// It also contains a status like Quad.
+//
+//      unscaled 12345, scale 2       is    123.45
+//
+// The operations on Fixed are written in Go, without cgo call, and are much faster than the operations on Quad.
+// It is useful for loops on many amounts, like summing cents. The result can then be converted to a Quad without loss, with ToQuad.
+// A Quad is converted to a Fixed with Quad.ToFixed.
+//
+// The scale is between 0 and FixedMaxScale.
+// If a result doesn't fit in int64, the result is 0 and the Overflow flag is set in its status.
+// If a result is rounded, the Inexact flag is set.
+//
+// As for Quad, the status of the operands is propagated to the result, so that it is possible to check the status only at the end of a calculation.
+//
+type Fixed struct {
+	unscaled int64
+	scale    int32
+	status   Status
+}
+
+const FixedMaxScale = 18 // largest scale of Fixed, as 10^18 is the largest power of 10 in int64
+
+// NewFixed returns the Fixed unscaled * 10^-scale.
+//
+//      NewFixed(12345, 2)      gives   123.45
+//      NewFixed(-5, 3)         gives   -0.005
+//
+// If scale is not between 0 and FixedMaxScale, the result is 0 with scale 0, and the InvalidOperation flag is set.
+//
+func NewFixed(unscaled int64, scale int32) Fixed {
+
+	if scale < 0 || scale > FixedMaxScale {
+		return Fixed{status: InvalidOperation}
+	}
+
+	return Fixed{unscaled: unscaled, scale: scale}
+}
+
+// Unscaled returns the unscaled value of a.
+//
+func (a Fixed) Unscaled() int64 {
+
+	return a.unscaled
+}
+
+// Scale returns the scale of a, which is the number of digits after the decimal point.
+//
+func (a Fixed) Scale() int32 {
+
+	return a.scale
+}
+
+// Status returns the status field of the Fixed.
+// It contains error flags, like 'Overflow', and informational flags, like 'Inexact'.
+//
+func (a Fixed) Status() Status {
+
+	return a.status
+}
+
+// ErrorStatus returns the status field of the Fixed.
+// It contains only error flags.
+// Same as a.Status()&ErrorMask
+//
+func (a Fixed) ErrorStatus() Status {
+
+	return a.status & ErrorMask
+}
+
+// Error returns an error if an error flag bit has been set in Fixed's status field.
+//
+// It returns QuadError(a.ErrorStatus()), or nil if no error.
+//
+func (a Fixed) Error() error {
+	var errorFlags Status
+
+	errorFlags = a.status & ErrorMask
+
+	if errorFlags == 0 {
+		return nil
+	}
+
+	return QuadError(errorFlags)
+}
+
+// String returns the value of a, with all the digits of the scale.
+//
+//      unscaled 12345, scale 2       gives   "123.45"
+//      unscaled -5, scale 3          gives   "-0.005"
+//
+func (a Fixed) String() string {
+	var s string
+
+	s = strconv.FormatUint(magnitude(a.unscaled), 10)
+
+	if len(s) <= int(a.scale) {
+		s = strings.Repeat("0", int(a.scale)-len(s)+1) + s
+	}
+
+	if a.scale > 0 {
+		s = s[:len(s)-int(a.scale)] + "." + s[len(s)-int(a.scale):]
+	}
+
+	if a.unscaled < 0 {
+		s = "-" + s
+	}
+
+	return s
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                            arithmetic                                */
+/*                                                                      */
+/************************************************************************/
+
+// magnitude returns the absolute value of n, which is correct also for math.MinInt64.
+//
+func magnitude(n int64) uint64 {
+
+	if n < 0 {
+		return uint64(-n) // -math.MinInt64 wraps to math.MinInt64, which is 1<<63 as uint64
+	}
+
+	return uint64(n)
+}
+
+// fromMagnitude returns the signed value of m. ok is false if it doesn't fit in int64.
+//
+func fromMagnitude(m uint64, negative bool) (n int64, ok bool) {
+
+	if negative {
+		if m > 1<<63 {
+			return 0, false
+		}
+		return -int64(m), true // -int64(1<<63) is math.MinInt64
+	}
+
+	if m > math.MaxInt64 {
+		return 0, false
+	}
+
+	return int64(m), true
+}
+
+// mulPow10 returns n * 10^e, e being between 0 and FixedMaxScale. ok is false if the result doesn't fit in int64.
+//
+func mulPow10(n int64, e int32) (r int64, ok bool) {
+	var p int64 = int64(g_uint64_pow10[e])
+
+	if n > math.MaxInt64/p || n < math.MinInt64/p {
+		return 0, false
+	}
+
+	return n * p, true
+}
+
+// valid returns true if rounding is one of the rounding modes RoundCeiling, RoundDown, etc.
+//
+func (rounding RoundingMode) valid() bool {
+
+	switch rounding {
+	case RoundCeiling, RoundDown, RoundFloor, RoundHalfDown, RoundHalfEven, RoundHalfUp, RoundUp, Round05Up:
+		return true
+	}
+	return false
+}
+
+// roundQuotient returns the quotient q of a division with remainder rem and divisor d, rounded with the rounding mode.
+// negative is the sign of the quotient. ok is false if the rounding mode is not valid.
+//
+// q must be less than 2^64 - 1, so that it can be incremented.
+//
+func roundQuotient(q uint64, rem uint64, d uint64, negative bool, rounding RoundingMode) (r uint64, ok bool) {
+	var up bool
+
+	if rem == 0 {
+		return q, true
+	}
+
+	switch rounding {
+	case RoundDown:
+	case RoundUp:
+		up = true
+	case RoundCeiling:
+		up = !negative
+	case RoundFloor:
+		up = negative
+	case RoundHalfUp:
+		up = rem >= d-rem
+	case RoundHalfDown:
+		up = rem > d-rem
+	case RoundHalfEven:
+		up = rem > d-rem || (rem == d-rem && q&1 == 1)
+	case Round05Up:
+		up = q%10 == 0 || q%10 == 5
+	default:
+		return 0, false
+	}
+
+	if up {
+		q++
+	}
+
+	return q, true
+}
+
+// fixedResult returns the Fixed with magnitude m, rounded if rem is not 0, and the status of a and b.
+//
+func fixedResult(m uint64, rem uint64, d uint64, negative bool, scale int32, rounding RoundingMode, a Fixed, b Fixed) Fixed {
+
+	status := a.status | b.status
+
+	if m > 1<<63 { // doesn't fit in int64, even after rounding
+		return Fixed{scale: scale, status: status | Overflow}
+	}
+
+	m, ok := roundQuotient(m, rem, d, negative, rounding)
+	if !ok {
+		return Fixed{scale: scale, status: status | InvalidOperation}
+	}
+
+	n, ok := fromMagnitude(m, negative)
+	if !ok {
+		return Fixed{scale: scale, status: status | Overflow}
+	}
+
+	if rem != 0 {
+		status |= Inexact
+	}
+
+	return Fixed{unscaled: n, scale: scale, status: status}
+}
+
+// Neg returns -a.
+//
+// If a is the smallest int64 value, the Overflow flag is set.
+//
+func (a Fixed) Neg() Fixed {
+
+	if a.unscaled == math.MinInt64 {
+		return Fixed{scale: a.scale, status: a.status | Overflow}
+	}
+
+	return Fixed{unscaled: -a.unscaled, scale: a.scale, status: a.status}
+}
+
+// int128 is a signed 128-bit integer in two's complement.
+//
+type int128 struct {
+	hi, lo uint64
+}
+
+// scaled128 returns n * 10^e as an int128, e being between 0 and FixedMaxScale. It never overflows.
+//
+func scaled128(n int64, e int32) int128 {
+
+	hi, lo := bits.Mul64(magnitude(n), g_uint64_pow10[e])
+
+	if n < 0 {
+		return int128{hi, lo}.neg()
+	}
+
+	return int128{hi, lo}
+}
+
+func (a int128) neg() int128 {
+
+	lo, borrow := bits.Sub64(0, a.lo, 0)
+	hi, _ := bits.Sub64(0, a.hi, borrow)
+
+	return int128{hi, lo}
+}
+
+func (a int128) add(b int128) int128 {
+
+	lo, carry := bits.Add64(a.lo, b.lo, 0)
+	hi, _ := bits.Add64(a.hi, b.hi, carry)
+
+	return int128{hi, lo}
+}
+
+// int64 returns a as an int64. ok is false if it doesn't fit.
+//
+func (a int128) int64() (n int64, ok bool) {
+
+	if (a.hi == 0 && a.lo>>63 == 0) || (a.hi == math.MaxUint64 && a.lo>>63 == 1) {
+		return int64(a.lo), true
+	}
+
+	return 0, false
+}
+
+// addFixed returns a + b, or a - b if subtract is true.
+// The operands are aligned on the largest scale with 128-bit integers, so that an operand which doesn't fit in int64 with this scale doesn't overflow if the result fits.
+//
+func addFixed(a Fixed, b Fixed, subtract bool) Fixed {
+
+	scale := a.scale
+	if b.scale > scale {
+		scale = b.scale
+	}
+
+	x := scaled128(a.unscaled, scale-a.scale)
+	y := scaled128(b.unscaled, scale-b.scale)
+
+	if subtract {
+		y = y.neg()
+	}
+
+	r, ok := x.add(y).int64()
+	if !ok {
+		return Fixed{scale: scale, status: a.status | b.status | Overflow}
+	}
+
+	return Fixed{unscaled: r, scale: scale, status: a.status | b.status}
+}
+
+// Add returns a + b.
+//
+// The scale of the result is the largest scale of a and b, so that the result is exact.
+// If the result doesn't fit in int64, it is 0 and the Overflow flag is set.
+//
+//      1.5 + 2.25        gives   3.75
+//
+func (a Fixed) Add(b Fixed) Fixed {
+
+	return addFixed(a, b, false)
+}
+
+// Sub returns a - b.
+//
+// The scale of the result is the largest scale of a and b, so that the result is exact.
+// If the result doesn't fit in int64, it is 0 and the Overflow flag is set.
+//
+func (a Fixed) Sub(b Fixed) Fixed {
+
+	return addFixed(a, b, true)
+}
+
+// Mul returns a * b, rounded to the scale of a with the rounding passed as argument.
+//
+// The scale of the result is the scale of a, as for an amount multiplied by a rate.
+//
+//      12.34 * 0.075     gives   0.93     (with RoundHalfEven, the exact product is 0.92550)
+//
+// If the result is rounded, the Inexact flag is set.
+// If the result doesn't fit in int64, it is 0 and the Overflow flag is set.
+// If rounding is not valid, the result is 0 and the InvalidOperation flag is set.
+//
+func (a Fixed) Mul(b Fixed, rounding RoundingMode) Fixed {
+	var d uint64 = g_uint64_pow10[b.scale]
+
+	if !rounding.valid() { // checked even if the result is exact
+		return Fixed{scale: a.scale, status: a.status | b.status | InvalidOperation}
+	}
+
+	negative := (a.unscaled < 0) != (b.unscaled < 0)
+
+	hi, lo := bits.Mul64(magnitude(a.unscaled), magnitude(b.unscaled))
+
+	if hi >= d { // quotient doesn't fit in uint64
+		return Fixed{scale: a.scale, status: a.status | b.status | Overflow}
+	}
+
+	q, rem := bits.Div64(hi, lo, d) // a*b has scale a.scale + b.scale
+
+	return fixedResult(q, rem, d, negative, a.scale, rounding, a, b)
+}
+
+// Div returns a / b, rounded to the scale of a with the rounding passed as argument.
+//
+// The scale of the result is the scale of a.
+//
+//      10.00 / 3         gives   3.33     (with RoundHalfEven)
+//      10.00 / 0.3       gives   33.33    (with RoundHalfEven)
+//
+// If the result is rounded, the Inexact flag is set.
+// If b is 0, the result is 0 and the DivisionByZero flag is set, or DivisionUndefined if a is also 0.
+// If the result doesn't fit in int64, it is 0 and the Overflow flag is set.
+// If rounding is not valid, the result is 0 and the InvalidOperation flag is set.
+//
+func (a Fixed) Div(b Fixed, rounding RoundingMode) Fixed {
+
+	if !rounding.valid() { // checked even if the result is exact
+		return Fixed{scale: a.scale, status: a.status | b.status | InvalidOperation}
+	}
+
+	if b.unscaled == 0 {
+		if a.unscaled == 0 {
+			return Fixed{scale: a.scale, status: a.status | b.status | DivisionUndefined}
+		}
+		return Fixed{scale: a.scale, status: a.status | b.status | DivisionByZero}
+	}
+
+	negative := (a.unscaled < 0) != (b.unscaled < 0)
+	d := magnitude(b.unscaled)
+
+	hi, lo := bits.Mul64(magnitude(a.unscaled), g_uint64_pow10[b.scale]) // a/b with scale a.scale is a*10^b.scale / b
+
+	if hi >= d { // quotient doesn't fit in uint64
+		return Fixed{scale: a.scale, status: a.status | b.status | Overflow}
+	}
+
+	q, rem := bits.Div64(hi, lo, d)
+
+	return fixedResult(q, rem, d, negative, a.scale, rounding, a, b)
+}
+
+// Rescale returns a with the specified scale. If the scale is decreased, the value is rounded with the rounding passed as argument.
+//
+//      123.456 with scale 2      gives   123.46     (with RoundHalfEven)
+//      123.456 with scale 5      gives   123.45600
+//
+// If scale is not between 0 and FixedMaxScale, or if rounding is not valid, the result is 0 and the InvalidOperation flag is set.
+// If the result is rounded, the Inexact flag is set.
+// If the result doesn't fit in int64, it is 0 and the Overflow flag is set.
+//
+func (a Fixed) Rescale(scale int32, rounding RoundingMode) Fixed {
+
+	if scale < 0 || scale > FixedMaxScale || !rounding.valid() {
+		return Fixed{status: a.status | InvalidOperation}
+	}
+
+	if scale >= a.scale {
+		n, ok := mulPow10(a.unscaled, scale-a.scale)
+		if !ok {
+			return Fixed{scale: scale, status: a.status | Overflow}
+		}
+		return Fixed{unscaled: n, scale: scale, status: a.status}
+	}
+
+	d := g_uint64_pow10[a.scale-scale]
+	m := magnitude(a.unscaled)
+
+	return fixedResult(m/d, m%d, d, a.unscaled < 0, scale, rounding, a, Fixed{})
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                       conversion to and from Quad                    */
+/*                                                                      */
+/************************************************************************/
+
+// ToQuad returns the value of a as a Quad, with exponent -scale.
+//
+// The conversion is always exact. The status of a is kept.
+//
+func (a Fixed) ToQuad() Quad {
+
+	return FromUnscaled(a.unscaled, a.scale).SetStatusFlags(a.status)
+}
+
+// ToFixed returns a as a Fixed with the specified scale, rounded with the rounding passed as argument.
+//
+//      123.456 with scale 2      gives   123.46     Inexact     (with RoundHalfEven)
+//      1.2E+3 with scale 2       gives   1200.00
+//
+// If the result is rounded, the Inexact flag is set.
+// If a is Infinite or NaN, if scale is not between 0 and FixedMaxScale, or if the unscaled value doesn't fit in int64, the result is 0 and the InvalidOperation flag is set.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToFixed(scale int32, rounding RoundingMode) Fixed {
+	var result C.Ret_int64_t
+
+	if scale < 0 || scale > FixedMaxScale {
+		return Fixed{status: InvalidOperation}
+	}
+
+	result = C.mdq_to_unscaled(C.struct_Quad(a), C.int32_t(scale), C.int(rounding))
+
+	if Status(result.status)&ErrorMask != 0 {
+		return Fixed{scale: scale, status: InvalidOperation}
+	}
+
+	return Fixed{unscaled: int64(result.val), scale: scale, status: Status(result.status)}
+}
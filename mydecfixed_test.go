@@ -0,0 +1,206 @@
+package decnum
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func Test_fixed(t *testing.T) {
+
+	samples := []struct {
+		a               Fixed
+		op              string
+		b               Fixed
+		rounding        RoundingMode
+		expected_result string
+		expected_status Status
+	}{
+		{NewFixed(150, 2), "+", NewFixed(225, 2), RoundHalfEven, "3.75", 0},
+		{NewFixed(15, 1), "+", NewFixed(225, 2), RoundHalfEven, "3.75", 0},
+		{NewFixed(15, 1), "-", NewFixed(225, 2), RoundHalfEven, "-0.75", 0},
+		{NewFixed(math.MaxInt64, 0), "+", NewFixed(1, 0), RoundHalfEven, "0", Overflow},
+		{NewFixed(math.MinInt64, 0), "-", NewFixed(1, 0), RoundHalfEven, "0", Overflow},
+		{NewFixed(math.MinInt64, 0), "+", NewFixed(-1, 0), RoundHalfEven, "0", Overflow},
+		{NewFixed(-1, 0), "-", NewFixed(math.MinInt64, 0), RoundHalfEven, "9223372036854775807", 0},
+		{NewFixed(math.MaxInt64/10+1, 0), "+", NewFixed(0, 1), RoundHalfEven, "0.0", Overflow},
+		{NewFixed(1234, 2), "*", NewFixed(75, 3), RoundHalfEven, "0.93", Inexact},
+		{NewFixed(1234, 2), "*", NewFixed(75, 3), RoundDown, "0.92", Inexact},
+		{NewFixed(-1234, 2), "*", NewFixed(75, 3), RoundFloor, "-0.93", Inexact},
+		{NewFixed(-1234, 2), "*", NewFixed(75, 3), RoundCeiling, "-0.92", Inexact},
+		{NewFixed(1250, 2), "*", NewFixed(1, 1), RoundHalfEven, "1.25", 0},
+		{NewFixed(125, 1), "*", NewFixed(1, 1), RoundHalfEven, "1.2", Inexact},
+		{NewFixed(125, 1), "*", NewFixed(1, 1), RoundHalfUp, "1.3", Inexact},
+		{NewFixed(125, 1), "*", NewFixed(1, 1), RoundHalfDown, "1.2", Inexact},
+		{NewFixed(135, 1), "*", NewFixed(1, 1), RoundHalfEven, "1.4", Inexact},
+		{NewFixed(101, 1), "*", NewFixed(1, 1), Round05Up, "1.1", Inexact},
+		{NewFixed(111, 1), "*", NewFixed(1, 1), Round05Up, "1.1", Inexact},
+		{NewFixed(math.MaxInt64, 0), "*", NewFixed(2, 0), RoundHalfEven, "0", Overflow},
+		{NewFixed(math.MinInt64, 0), "*", NewFixed(1, 0), RoundHalfEven, "-9223372036854775808", 0},
+		{NewFixed(math.MinInt64, 0), "*", NewFixed(-1, 0), RoundHalfEven, "0", Overflow},
+		{NewFixed(math.MaxInt64, 18), "*", NewFixed(math.MaxInt64, 18), RoundHalfEven, "0.000000000000000000", Overflow},
+		{NewFixed(1000, 2), "/", NewFixed(3, 0), RoundHalfEven, "3.33", Inexact},
+		{NewFixed(1000, 2), "/", NewFixed(3, 1), RoundHalfEven, "33.33", Inexact},
+		{NewFixed(-2000, 2), "/", NewFixed(3, 0), RoundHalfEven, "-6.67", Inexact},
+		{NewFixed(-2000, 2), "/", NewFixed(3, 0), RoundDown, "-6.66", Inexact},
+		{NewFixed(1, 0), "/", NewFixed(1, 18), RoundHalfEven, "1000000000000000000", 0},
+		{NewFixed(10, 0), "/", NewFixed(1, 18), RoundHalfEven, "0", Overflow},
+		{NewFixed(1, 0), "/", NewFixed(0, 2), RoundHalfEven, "0", DivisionByZero},
+		{NewFixed(0, 0), "/", NewFixed(0, 2), RoundHalfEven, "0", DivisionUndefined},
+		{NewFixed(1, 0), "/", NewFixed(3, 0), RoundingMode(99), "0", InvalidOperation},
+		{NewFixed(6, 0), "/", NewFixed(3, 0), RoundingMode(99), "0", InvalidOperation},
+		{NewFixed(1, 0), "/", NewFixed(0, 0), RoundingMode(99), "0", InvalidOperation},
+		{NewFixed(5, 0), "*", NewFixed(2, 0), RoundingMode(99), "0", InvalidOperation},
+		{NewFixed(1, 19), "+", NewFixed(1, 0), RoundHalfEven, "1", InvalidOperation},
+	}
+
+	for i, sp := range samples {
+		var r Fixed
+
+		switch sp.op {
+		case "+":
+			r = sp.a.Add(sp.b)
+		case "-":
+			r = sp.a.Sub(sp.b)
+		case "*":
+			r = sp.a.Mul(sp.b, sp.rounding)
+		case "/":
+			r = sp.a.Div(sp.b, sp.rounding)
+		}
+
+		if r.String() != sp.expected_result || r.Status() != sp.expected_status {
+			t.Fatalf("sample %d, %s %s %s %s: %s %s != %s %s (expected)", i, sp.a, sp.op, sp.b, sp.rounding, r, r.Status(), sp.expected_result, sp.expected_status)
+		}
+	}
+
+	// Neg, Rescale and status propagation
+
+	if r := NewFixed(math.MinInt64, 2).Neg(); r.Error() == nil {
+		t.Fatalf("Neg of MinInt64 should overflow: %s", r)
+	}
+
+	if r := NewFixed(123456, 3).Rescale(2, RoundHalfEven); r.String() != "123.46" || r.Status() != Inexact {
+		t.Fatalf("Rescale 2: %s %s", r, r.Status())
+	}
+
+	if r := NewFixed(123456, 3).Rescale(5, RoundHalfEven); r.String() != "123.45600" || r.Status() != 0 {
+		t.Fatalf("Rescale 5: %s %s", r, r.Status())
+	}
+
+	if r := NewFixed(math.MaxInt64, 0).Rescale(1, RoundHalfEven); r.Status() != Overflow {
+		t.Fatalf("Rescale should overflow: %s %s", r, r.Status())
+	}
+
+	if r := NewFixed(12300, 2).Rescale(1, RoundingMode(99)); r.Status() != InvalidOperation {
+		t.Fatalf("Rescale with invalid rounding: %s %s", r, r.Status())
+	}
+
+	if r := NewFixed(1, 0).Div(NewFixed(0, 0), RoundHalfEven).Add(NewFixed(1, 0)); r.Status() != DivisionByZero || r.Error() == nil {
+		t.Fatalf("status should be propagated: %s", r.Status())
+	}
+
+	// conversion to and from Quad
+
+	conversions := []struct {
+		a               string
+		scale           int32
+		rounding        RoundingMode
+		expected_result string
+		expected_status Status
+	}{
+		{"123.456", 2, RoundHalfEven, "123.46", Inexact},
+		{"123.455", 2, RoundDown, "123.45", Inexact},
+		{"1.2E+3", 2, RoundHalfEven, "1200.00", 0},
+		{"-0.005", 3, RoundHalfEven, "-0.005", 0},
+		{"9223372036854775807", 0, RoundHalfEven, "9223372036854775807", 0},
+		{"9223372036854775808", 0, RoundHalfEven, "0", InvalidOperation},
+		{"1", 19, RoundHalfEven, "0", InvalidOperation},
+		{"NaN", 2, RoundHalfEven, "0.00", InvalidOperation},
+		{"-Inf", 2, RoundHalfEven, "0.00", InvalidOperation},
+	}
+
+	for i, sp := range conversions {
+		r := must_quad(sp.a).ToFixed(sp.scale, sp.rounding)
+
+		if r.String() != sp.expected_result || r.Status() != sp.expected_status {
+			t.Fatalf("sample %d, ToFixed %s %d %s: %s %s != %s %s (expected)", i, sp.a, sp.scale, sp.rounding, r, r.Status(), sp.expected_result, sp.expected_status)
+		}
+
+		if r.Status() == 0 && r.ToQuad().String() != r.String() {
+			t.Fatalf("sample %d, ToQuad of %s: %s", i, r, r.ToQuad())
+		}
+	}
+
+	if q := NewFixed(1, 0).Add(NewFixed(math.MaxInt64, 0)).ToQuad(); q.Status() != Overflow {
+		t.Fatalf("ToQuad should keep the status: %s", q.Status())
+	}
+
+	// comparison with Quad
+
+	rnd := rand.New(rand.NewSource(1))
+	roundings := []RoundingMode{RoundCeiling, RoundDown, RoundFloor, RoundHalfDown, RoundHalfEven, RoundHalfUp, RoundUp, Round05Up}
+
+	for i := 0; i < 20000; i++ {
+		a := NewFixed(rnd.Int63()>>uint(rnd.Intn(63))-rnd.Int63()>>uint(rnd.Intn(63)), int32(rnd.Intn(FixedMaxScale+1)))
+		b := NewFixed(rnd.Int63()>>uint(rnd.Intn(63))-rnd.Int63()>>uint(rnd.Intn(63)), int32(rnd.Intn(FixedMaxScale+1)))
+		rounding := roundings[rnd.Intn(len(roundings))]
+		scale := a.Add(b).Scale() // largest scale
+
+		results := []struct {
+			op       string
+			r        Fixed
+			expected Quad
+			scale    int32
+		}{
+			{"+", a.Add(b), a.ToQuad().Add(b.ToQuad()), scale},
+			{"-", a.Sub(b), a.ToQuad().Sub(b.ToQuad()), scale},
+			{"*", a.Mul(b, rounding), a.ToQuad().Mul(b.ToQuad()), a.scale}, // exact, as each operand has at most 19 digits
+			{"/", a.Div(b, rounding), a.ToQuad().Div(b.ToQuad()), a.scale},
+		}
+
+		for _, res := range results {
+			if res.op == "/" && (b.unscaled == 0 || res.expected.Status()&Inexact != 0) {
+				continue // the quotient rounded to 34 digits can't be rounded again
+			}
+
+			expected := res.expected.ToFixed(res.scale, rounding)
+
+			if expected.Status()&InvalidOperation != 0 {
+				expected = Fixed{scale: res.scale, status: Overflow}
+			}
+
+			if res.r != expected {
+				t.Fatalf("%s %s %s %s: %s %s != %s %s (expected)", a, res.op, b, rounding, res.r, res.r.Status(), expected, expected.Status())
+			}
+		}
+	}
+}
+
+var benchFixedSamples = []Fixed{NewFixed(1234, 2), NewFixed(-99, 2), NewFixed(100000, 2), NewFixed(1, 2), NewFixed(555, 2)}
+
+func Benchmark_Fixed_Add(b *testing.B) {
+
+	for i := 0; i < b.N; i++ {
+		var sum Fixed = NewFixed(0, 2)
+
+		for _, a := range benchFixedSamples {
+			sum = sum.Add(a)
+		}
+	}
+}
+
+func Benchmark_Fixed_Add_Quad(b *testing.B) {
+	var samples []Quad
+
+	for _, a := range benchFixedSamples {
+		samples = append(samples, a.ToQuad())
+	}
+
+	for i := 0; i < b.N; i++ {
+		var sum Quad = FromInt32(0)
+
+		for _, a := range samples {
+			sum = sum.Add(a)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package decnum
+
+/************************************************************************/
+/*                                                                      */
+/*                  generic algorithms on decimal numbers               */
+/*                                                                      */
+/************************************************************************/
+
+// Decimal is the method set common to the decimal types of this package: Quad, Double and BigDecimal.
+//
+// It is used as a type constraint by the generic functions of this package, and can be used by your own generic functions:
+//
+//     func Total[T decnum.Decimal[T]](prices []T, quantities []T) T { ... }
+//
+//     total := decnum.Sum(quads)      // T is Quad
+//     mean := decnum.Mean(doubles)    // T is Double
+//
+// Single doesn't satisfy Decimal, as it has no arithmetic.
+//
+type Decimal[T any] interface {
+	Add(b T) T
+	Sub(b T) T
+	Mul(b T) T
+	Div(b T) T
+	Neg() T
+	Abs() T
+	Quantize(b T, rounding RoundingMode) T
+	RoundWithMode(n int32, rounding RoundingMode) T
+	Cmp(b T) CmpFlag
+	Less(b T) bool
+	Greater(b T) bool
+	Equal(b T) bool
+	IsNaN() bool
+	IsZero() bool
+	IsFinite() bool
+	Status() Status
+	Error() error
+	SetStatusFlags(statusflags Status) T
+	ClearStatusFlags(statusflags Status) T
+	String() string
+
+	fromInt64(value int64) T // value converted to T. For BigDecimal, with the context of the receiver.
+}
+
+// fromInt64 returns value as a Quad. The receiver is not used.
+//
+func (a Quad) fromInt64(value int64) Quad {
+
+	return FromInt64(value)
+}
+
+// fromInt64 returns value as a Double. The receiver is not used.
+//
+func (a Double) fromInt64(value int64) Double {
+
+	return DoubleFromInt64(value)
+}
+
+// fromInt64 returns value as a BigDecimal, with the context of a.
+//
+func (a BigDecimal) fromInt64(value int64) BigDecimal {
+
+	return a.ctx.FromInt64(value)
+}
+
+// emptyResult returns NaN with the InvalidOperation flag, which is the result of Mean, MinOf and MaxOf for an empty slice.
+//
+func emptyResult[T Decimal[T]]() T {
+	var zero T
+
+	return zero.fromInt64(0).Div(zero.fromInt64(0)).ClearStatusFlags(DivisionUndefined).SetStatusFlags(InvalidOperation) // 0/0 is NaN
+}
+
+// Sum returns the sum of values.
+//
+// The status flags of all values propagate into the result, as if the values were added with Add.
+// For BigDecimal, the context of values[0] is used.
+//
+// The sum of an empty slice is 0.
+//
+func Sum[T Decimal[T]](values []T) T {
+	var (
+		zero T
+		sum  T
+	)
+
+	if len(values) == 0 {
+		return zero.fromInt64(0)
+	}
+
+	sum = values[0]
+
+	for _, v := range values[1:] {
+		sum = sum.Add(v)
+	}
+
+	return sum
+}
+
+// Mean returns the arithmetic mean of values, that is Sum(values)/len(values).
+//
+// The mean of an empty slice is NaN, and the InvalidOperation flag is set.
+//
+func Mean[T Decimal[T]](values []T) T {
+
+	if len(values) == 0 {
+		return emptyResult[T]()
+	}
+
+	return Sum(values).Div(values[0].fromInt64(int64(len(values))))
+}
+
+// MinOf returns the smallest of values.
+//
+// As for Min, NaN values are ignored, unless all values are NaN.
+// The status flags of all values propagate into the result.
+//
+// The minimum of an empty slice is NaN, and the InvalidOperation flag is set.
+//
+func MinOf[T Decimal[T]](values []T) T {
+
+	return extremum(values, CmpLess)
+}
+
+// MaxOf returns the largest of values.
+//
+// As for Max, NaN values are ignored, unless all values are NaN.
+// The status flags of all values propagate into the result.
+//
+// The maximum of an empty slice is NaN, and the InvalidOperation flag is set.
+//
+func MaxOf[T Decimal[T]](values []T) T {
+
+	return extremum(values, CmpGreater)
+}
+
+// extremum returns the first value v such that v.Cmp(w) is never cmp, for all other non-NaN values w.
+//
+func extremum[T Decimal[T]](values []T, cmp CmpFlag) T {
+	var (
+		r      T
+		status Status
+	)
+
+	if len(values) == 0 {
+		return emptyResult[T]()
+	}
+
+	r = values[0]
+
+	for _, v := range values {
+		status |= v.Status()
+
+		if v.Cmp(r) == cmp || (r.IsNaN() && !v.IsNaN()) {
+			r = v
+		}
+	}
+
+	return r.SetStatusFlags(status)
+}
+
+// RoundAll returns a new slice, containing values rounded with RoundWithMode(n, rounding).
+//
+// Each result keeps the status flags of its value. Use StatusOf to get the flags of all the results.
+//
+func RoundAll[T Decimal[T]](values []T, n int32, rounding RoundingMode) []T {
+	var result []T = make([]T, len(values))
+
+	for i, v := range values {
+		result[i] = v.RoundWithMode(n, rounding)
+	}
+
+	return result
+}
+
+// QuantizeAll returns a new slice, containing values quantized with Quantize(pattern, rounding).
+//
+// Each result keeps the status flags of its value and of pattern. Use StatusOf to get the flags of all the results.
+//
+//     prices = QuantizeAll(prices, MustParse("0.01"), RoundHalfUp)
+//
+func QuantizeAll[T Decimal[T]](values []T, pattern T, rounding RoundingMode) []T {
+	var result []T = make([]T, len(values))
+
+	for i, v := range values {
+		result[i] = v.Quantize(pattern, rounding)
+	}
+
+	return result
+}
+
+// StatusOf returns the status flags of all values, ORed together.
+//
+//     if StatusOf(prices)&ErrorMask != 0 { ... }
+//
+func StatusOf[T Decimal[T]](values []T) Status {
+	var status Status
+
+	for _, v := range values {
+		status |= v.Status()
+	}
+
+	return status
+}
@@ -0,0 +1,169 @@
+package decnum
+
+import (
+	"testing"
+)
+
+// converts strings to a slice of Quad, Double or BigDecimal.
+//
+func must_quads(s ...string) []Quad {
+	var r []Quad
+
+	for _, v := range s {
+		r = append(r, must_quad(v))
+	}
+
+	return r
+}
+
+func must_doubles(s ...string) []Double {
+	var r []Double
+
+	for _, v := range s {
+		r = append(r, must_double(v))
+	}
+
+	return r
+}
+
+func must_bigs(ctx BigContext, s ...string) []BigDecimal {
+	var r []BigDecimal
+
+	for _, v := range s {
+		r = append(r, must_big(ctx, v))
+	}
+
+	return r
+}
+
+// strings returns the String() of each value.
+//
+func strings_of[T Decimal[T]](values []T) []string {
+	var r []string
+
+	for _, v := range values {
+		r = append(r, v.String())
+	}
+
+	return r
+}
+
+func Test_generic_algorithms(t *testing.T) {
+
+	samples := []struct {
+		values           []string
+		expected_sum     string
+		expected_mean    string
+		expected_min     string
+		expected_max     string
+		expected_rounded string
+	}{
+		{[]string{"1.25", "2.5", "-3"}, "0.75", "0.25", "-3", "2.5", "[1.2 2.5 -3.0]"},
+		{[]string{"1", "2"}, "3", "1.5", "1", "2", "[1.0 2.0]"},
+		{[]string{"NaN", "2", "1"}, "NaN", "NaN", "1", "2", "[NaN 2.0 1.0]"},
+		{[]string{"7"}, "7", "7", "7", "7", "[7.0]"},
+	}
+
+	for i, sp := range samples {
+		check := func(kind string, sum, mean, min, max string, rounded []string) {
+			if sum != sp.expected_sum || mean != sp.expected_mean || min != sp.expected_min || max != sp.expected_max || sliceString(rounded) != sp.expected_rounded {
+				t.Fatalf("sample %d, %s %v: %s %s %s %s %s != %s %s %s %s %s (expected)", i, kind, sp.values, sum, mean, min, max, sliceString(rounded),
+					sp.expected_sum, sp.expected_mean, sp.expected_min, sp.expected_max, sp.expected_rounded)
+			}
+		}
+
+		q := must_quads(sp.values...)
+		check("Quad", Sum(q).String(), Mean(q).String(), MinOf(q).String(), MaxOf(q).String(), strings_of(RoundAll(q, 1, RoundHalfEven)))
+
+		d := must_doubles(sp.values...)
+		check("Double", Sum(d).String(), Mean(d).String(), MinOf(d).String(), MaxOf(d).String(), strings_of(RoundAll(d, 1, RoundHalfEven)))
+
+		b := must_bigs(NewBigContext(50, RoundHalfEven), sp.values...)
+		check("BigDecimal", Sum(b).String(), Mean(b).String(), MinOf(b).String(), MaxOf(b).String(), strings_of(RoundAll(b, 1, RoundHalfEven)))
+	}
+}
+
+// sliceString returns the slice formatted as "[a b c]".
+//
+func sliceString(s []string) string {
+	var r string
+
+	for i, v := range s {
+		if i > 0 {
+			r += " "
+		}
+		r += v
+	}
+
+	return "[" + r + "]"
+}
+
+func Test_generic_status(t *testing.T) {
+
+	// precision of each type
+
+	if r := Mean(must_quads("1", "1", "0")); r.String() != "0.6666666666666666666666666666666667" || r.Status() != Inexact {
+		t.Fatalf("Mean Quad: %s %s", r, r.Status())
+	}
+
+	if r := Mean(must_doubles("1", "1", "0")); r.String() != "0.6666666666666667" || r.Status() != Inexact {
+		t.Fatalf("Mean Double: %s %s", r, r.Status())
+	}
+
+	if r := Mean(must_bigs(NewBigContext(40, RoundDown), "1", "1", "0")); r.String() != "0.6666666666666666666666666666666666666666" || r.Status() != Inexact {
+		t.Fatalf("Mean BigDecimal: %s %s", r, r.Status())
+	}
+
+	// status accumulation
+
+	q := must_quads("1", "2", "3")
+	q[1] = q[1].SetStatusFlags(Overflow)
+
+	if r := Sum(q); r.Error() == nil {
+		t.Fatalf("Sum should propagate status")
+	}
+
+	if r := MinOf(q); r.String() != "1" || r.Status()&Overflow == 0 {
+		t.Fatalf("MinOf should propagate status: %s %s", r, r.Status())
+	}
+
+	if r := MaxOf(q); r.String() != "3" || r.Status()&Overflow == 0 {
+		t.Fatalf("MaxOf should propagate status: %s %s", r, r.Status())
+	}
+
+	if s := StatusOf(RoundAll(q, 0, RoundHalfEven)); s&Overflow == 0 {
+		t.Fatalf("RoundAll should keep status: %s", s)
+	}
+
+	r := QuantizeAll(must_quads("1.005", "2.5", "1E+40"), must_quad("0.01"), RoundHalfUp)
+
+	if s := sliceString(strings_of(r)); s != "[1.01 2.50 NaN]" {
+		t.Fatalf("QuantizeAll: %s", s)
+	}
+
+	if s := StatusOf(r); s != Inexact|InvalidOperation || r[0].Status() != Inexact || r[1].Status() != 0 {
+		t.Fatalf("QuantizeAll status: %s", s)
+	}
+
+	// empty slices
+
+	if r := Sum([]Quad{}); r.String() != "0" || r.Status() != 0 {
+		t.Fatalf("Sum of empty slice: %s %s", r, r.Status())
+	}
+
+	if r := Mean([]Double{}); !r.IsNaN() || r.Status() != InvalidOperation {
+		t.Fatalf("Mean of empty slice: %s %s", r, r.Status())
+	}
+
+	if r := MinOf([]BigDecimal{}); !r.IsNaN() || r.Status() != InvalidOperation {
+		t.Fatalf("MinOf of empty slice: %s %s", r, r.Status())
+	}
+
+	if r := MaxOf(must_quads("NaN", "NaN")); !r.IsNaN() {
+		t.Fatalf("MaxOf of NaN: %s", r)
+	}
+
+	if c := must_quad("1").Cmp(must_quad("NaN")); c != CmpNaN {
+		t.Fatalf("Cmp with NaN: %s", c)
+	}
+}
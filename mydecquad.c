@@ -44,7 +44,7 @@ static decQuad G_DECQUAD_INTEGRAL_PART_QUANTIZER[DECQUAD_Pmax+2];  // 0...35
 /*                          init and context                            */
 /************************************************************************/
 
-static decQuad static_one;  // contains 1, only used by mdq_to_int64
+static decQuad static_one;  // contains 1, only used by mdq_to_int64_via_string and mdq_to_unscaled
 
 
 /* initialize the global constants used by this library.
@@ -73,7 +73,7 @@ void mdq_init(void) {
 
   //----- put 1 in static_one -----
 
-  decQuadFromInt32(&static_one, 1); // IMPORTANT: this means that mdq_to_int64 can only be called after Go init() has been run, as it uses static_one. Method ToInt32() cannot be called to initialize Go global variables.
+  decQuadFromInt32(&static_one, 1); // IMPORTANT: this means that mdq_to_int64_via_string and mdq_to_unscaled can only be called after Go init() has been run, as it uses static_one. Method ToInt32() cannot be called to initialize Go global variables.
 
 
   //----- fill decContext -----
@@ -123,6 +123,11 @@ void mdq_init(void) {
   assert( decQuadGetExponent(&G_DECQUAD_INTEGRAL_PART_QUANTIZER[DECQUAD_Pmax+1]) == DECQUAD_Pmax+1 );  // 35
 
 
+  //----- convert Pi, used by mydecquad_trig.c -----
+
+  mdq_trig_init();
+
+
   //----- check for errors or any warning -----
 
   if ( set.status ) {
@@ -197,6 +202,23 @@ Quad mdq_add(Quad a, Quad b) {
 }
 
 
+/* addition, with rounding mode.
+*/
+Quad mdq_add_mode(Quad a, Quad b, int round) {
+  decContext  set;
+  Quad        res;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+  decContextSetRounding(&set, round);      // change rounding mode
+  set.status = a.status | b.status;
+
+  decQuadAdd(&res.val, &a.val, &b.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
 /* subtraction.
 */
 Quad mdq_subtract(Quad a, Quad b) {
@@ -213,6 +235,23 @@ Quad mdq_subtract(Quad a, Quad b) {
 }
 
 
+/* subtraction, with rounding mode.
+*/
+Quad mdq_subtract_mode(Quad a, Quad b, int round) {
+  decContext  set;
+  Quad        res;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+  decContextSetRounding(&set, round);      // change rounding mode
+  set.status = a.status | b.status;
+
+  decQuadSubtract(&res.val, &a.val, &b.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
 /* multiplication.
 */
 Quad mdq_multiply(Quad a, Quad b) {
@@ -229,6 +268,23 @@ Quad mdq_multiply(Quad a, Quad b) {
 }
 
 
+/* multiplication, with rounding mode.
+*/
+Quad mdq_multiply_mode(Quad a, Quad b, int round) {
+  decContext  set;
+  Quad        res;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+  decContextSetRounding(&set, round);      // change rounding mode
+  set.status = a.status | b.status;
+
+  decQuadMultiply(&res.val, &a.val, &b.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
 /* division.
 */
 Quad mdq_divide(Quad a, Quad b) {
@@ -245,6 +301,23 @@ Quad mdq_divide(Quad a, Quad b) {
 }
 
 
+/* division, with rounding mode.
+*/
+Quad mdq_divide_mode(Quad a, Quad b, int round) {
+  decContext  set;
+  Quad        res;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+  decContextSetRounding(&set, round);      // change rounding mode
+  set.status = a.status | b.status;
+
+  decQuadDivide(&res.val, &a.val, &b.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
 /* integer division.
 */
 Quad mdq_divide_integer(Quad a, Quad b) {
@@ -358,6 +431,32 @@ Quad mdq_abs(Quad a) {
 }
 
 
+/* power.
+
+   decQuad has no power function, so decNumberPower() is used.
+*/
+Quad mdq_power(Quad a, Quad b) {
+  decContext  set;
+  Quad        res;
+  decNumber   a_num;   // DECNUMDIGITS is DECIMAL128_Pmax, defined in decimal128.h
+  decNumber   b_num;
+  decNumber   res_num;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+  set.status = a.status | b.status;
+
+  decQuadToNumber(&a.val, &a_num);
+  decQuadToNumber(&b.val, &b_num);
+
+  decNumberPower(&res_num, &a_num, &b_num, &set);
+
+  decQuadFromNumber(&res.val, &res_num, &set);
+  res.status = decContextGetStatus(&set) & ~(DEC_Rounded | DEC_Subnormal | DEC_Clamped);   // decNumber sets these flags, which are not used by this package
+
+  return res;
+}
+
+
 /************************************************************************/
 /*                           is_finite, etc                             */
 /************************************************************************/
@@ -419,6 +518,14 @@ uint32_t mdq_is_negative(decQuad a) {
 }
 
 
+/* check if the encoding of a is canonical.
+*/
+uint32_t mdq_is_canonical(decQuad a) {
+
+  return decQuadIsCanonical(&a);
+}
+
+
 /* get exponent.
 */
 int32_t mdq_get_exponent(decQuad a) {
@@ -495,9 +602,62 @@ Quad mdq_from_int32(int32_t value) {
 }
 
 
+/* write the magnitude of an integer into a decQuad, with exponent 0.
+   sign is DECFLOAT_Sign for negative numbers, else 0.
+
+   As an uint64_t has at most 20 digits, it never fails.
+*/
+static void mdq_from_magnitude(decQuad *res, uint64_t magnitude, int32_t sign) {
+  uint8_t      bcd[DECQUAD_Pmax];
+  int          i;
+
+  memset(bcd, 0, sizeof(bcd));
+
+  for ( i = DECQUAD_Pmax-1; magnitude != 0; i-- ) {
+      bcd[i] = magnitude % 10;
+      magnitude /= 10;
+  }
+
+  decQuadFromBCD(res, 0, bcd, sign);
+}
+
+
 /* conversion from int64.
+
+   The coefficient is written directly, without conversion to string.
 */
 Quad mdq_from_int64(int64_t value) {
+  Quad        res;
+
+  if ( value < 0 ) {
+      mdq_from_magnitude(&res.val, -(uint64_t)value, DECFLOAT_Sign);   // -(uint64_t) is also correct for INT64_MIN
+  } else {
+      mdq_from_magnitude(&res.val, (uint64_t)value, 0);
+  }
+
+  res.status = 0; // never fails
+
+  return res;
+}
+
+
+/* conversion from uint64.
+*/
+Quad mdq_from_uint64(uint64_t value) {
+  Quad        res;
+
+  mdq_from_magnitude(&res.val, value, 0);
+  res.status = 0; // never fails
+
+  return res;
+}
+
+
+/* former conversion from int64, through a string.
+
+   It is only used by the tests and benchmarks of mdq_from_int64.
+*/
+Quad mdq_from_int64_via_string(int64_t value) {
   char         buff[30]; // more than enough to store a int64     max val: 9,223,372,036,854,775,807
   decContext  set;
   Quad        res;
@@ -604,9 +764,291 @@ Ret_int32_t mdq_to_int32(Quad a, int round) {
 }
 
 
+/* convert decQuad to uint32_t
+*/
+Ret_uint32_t mdq_to_uint32(Quad a, int round) {
+  decContext      set;
+  Ret_uint32_t    res;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+
+  res.val = decQuadToUInt32(&a.val, &set, round);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* round a to an integral value, and write its magnitude in *magnitude, and its sign in *negative.
+
+   The magnitude is computed directly from the digits of the coefficient and the exponent, without conversion to string.
+   The rounding is also done on the digits, as decQuadToIntegralValue() would do.
+   If a is Inf or NaN, or if the magnitude is larger than UINT64_MAX, DEC_Invalid_operation is set in set->status.
+
+   Returns 0 if the magnitude is valid.
+*/
+static int mdq_integral_magnitude(Quad a, int round, decContext *set, uint64_t *magnitude, uint32_t *negative) {
+  uint8_t      bcd[DECQUAD_Pmax];
+  int32_t      exp;
+  int32_t      end;             // the integral part is bcd[0..end), followed by exp zeros
+  uint8_t      first   = 0;     // first discarded digit
+  uint32_t     sticky  = 0;     // 1 if a discarded digit after first is not 0
+  uint32_t     increment;
+  uint64_t     mag     = 0;
+  uint8_t      digit;
+  int          i;
+
+  if ( ! decQuadIsFinite(&a.val) ) {
+      decContextSetStatus(set, DEC_Invalid_operation);
+      return 1;
+  }
+
+  *negative = decQuadGetCoefficient(&a.val, bcd) != 0;
+  exp       = decQuadGetExponent(&a.val);
+
+  if ( decQuadIsZero(&a.val) ) {                  // e.g. 0E+100
+      *magnitude = 0;
+      return 0;
+  }
+
+  end = DECQUAD_Pmax;
+
+  if ( exp < 0 ) {
+      end = DECQUAD_Pmax + exp;
+
+      if ( end >= 0 ) {
+          first = bcd[end];
+          i = end + 1;
+      } else {
+          end = 0;              // all digits are after the first discarded digit, which is 0
+          i = 0;
+      }
+
+      for ( ; i < DECQUAD_Pmax && ! sticky; i++ ) {
+          sticky = bcd[i];
+      }
+
+      exp = 0;
+  }
+
+  // integral part
+
+  i = DECQUAD_Pmax - decQuadDigits(&a.val);       // skip leading zeros
+
+  if ( end - i + (i < end ? exp : 0) > 20 ) {    // UINT64_MAX has 20 digits
+      decContextSetStatus(set, DEC_Invalid_operation);
+      return 1;
+  }
+
+  for ( ; i < end + exp && end > 0; i++ ) {      // digits of the coefficient, followed by exp zeros
+      digit = i < end ? bcd[i] : 0;
+
+      if ( mag > UINT64_MAX/10 || (mag == UINT64_MAX/10 && digit > UINT64_MAX%10) ) {
+          decContextSetStatus(set, DEC_Invalid_operation);
+          return 1;
+      }
+
+      mag = mag*10 + digit;
+  }
+
+  // rounding
+
+  switch ( round ) {
+  case DEC_ROUND_CEILING:    increment = (first | sticky) && ! *negative;                      break;
+  case DEC_ROUND_FLOOR:      increment = (first | sticky) && *negative;                        break;
+  case DEC_ROUND_UP:         increment = (first | sticky) != 0;                                break;
+  case DEC_ROUND_HALF_UP:    increment = first >= 5;                                           break;
+  case DEC_ROUND_HALF_DOWN:  increment = first > 5 || (first == 5 && sticky);                  break;
+  case DEC_ROUND_HALF_EVEN:  increment = first > 5 || (first == 5 && (sticky || mag % 2));     break;
+  case DEC_ROUND_05UP:       increment = (first | sticky) && (mag % 5 == 0);                   break;  // last digit is 0 or 5
+  default:                   increment = 0;                                                    break;  // DEC_ROUND_DOWN
+  }
+
+  if ( increment ) {
+      if ( mag == UINT64_MAX ) {
+          decContextSetStatus(set, DEC_Invalid_operation);
+          return 1;
+      }
+      mag++;
+  }
+
+  *magnitude = mag;
+
+  return 0;
+}
+
+
 /* convert decQuad to int64_t
 */
 Ret_int64_t mdq_to_int64(Quad a, int round) {
+  decContext   set;
+  uint64_t     magnitude;
+  uint32_t     negative;
+  Ret_int64_t  res = {.val = 0};
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+
+  if ( mdq_integral_magnitude(a, round, &set, &magnitude, &negative) == 0 ) {
+      if ( negative && magnitude <= (uint64_t)INT64_MAX + 1 ) {
+          res.val = (int64_t)(0 - magnitude);                  // also correct for INT64_MIN
+      } else if ( ! negative && magnitude <= INT64_MAX ) {
+          res.val = (int64_t)magnitude;
+      } else {
+          decContextSetStatus(&set, DEC_Invalid_operation);
+      }
+  }
+
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* convert decQuad to uint64_t
+
+   A negative number is an error, except if it is rounded to -0.
+*/
+Ret_uint64_t mdq_to_uint64(Quad a, int round) {
+  decContext    set;
+  uint64_t      magnitude;
+  uint32_t      negative;
+  Ret_uint64_t  res = {.val = 0};
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+
+  if ( mdq_integral_magnitude(a, round, &set, &magnitude, &negative) == 0 ) {
+      if ( negative && magnitude != 0 ) {
+          decContextSetStatus(&set, DEC_Invalid_operation);
+      } else {
+          res.val = magnitude;
+      }
+  }
+
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+#define MDQ_SCALEB_MAX  (2*(DECQUAD_Emax + DECQUAD_Pmax))   // 12356, largest magnitude of the second operand of decQuadScaleB
+
+
+/* conversion from an unscaled integer and a scale, giving unscaled * 10^-scale.
+
+   E.g. 12345 with scale 2 gives 123.45.
+   The result is exact, except if the exponent -scale is out of the range of decQuad, which may set Overflow, Underflow or Inexact.
+*/
+Quad mdq_from_unscaled(int64_t unscaled, int32_t scale) {
+  decContext  set;
+  decQuad     n;
+  Quad        res;
+
+  res = mdq_from_int64(unscaled);
+
+  // decQuadScaleB rejects a scale larger than MDQ_SCALEB_MAX with Invalid_operation.
+  // Beyond it, the result overflows or underflows anyway, as the unscaled integer has at most 19 digits.
+
+  if ( scale > MDQ_SCALEB_MAX ) {
+      scale = MDQ_SCALEB_MAX;
+  } else if ( scale < -MDQ_SCALEB_MAX ) {
+      scale = -MDQ_SCALEB_MAX;
+  }
+
+  if ( scale != 0 ) {
+      decContextDefault(&set, DEC_INIT_DECQUAD);
+
+      decQuadFromInt32(&n, scale);
+      decQuadCopyNegate(&n, &n);                    // -scale, also valid for INT32_MIN
+
+      decQuadScaleB(&res.val, &res.val, &n, &set);  // exponent of res becomes -scale
+      res.status = decContextGetStatus(&set);
+  }
+
+  return res;
+}
+
+
+/* a rounded to scale digits after the decimal point, and returned as the unscaled integer.
+
+   E.g. 123.456 with scale 2 gives 12346.
+   The status contains Inexact if a has been rounded.
+*/
+Ret_int64_t mdq_to_unscaled(Quad a, int32_t scale, int round) {
+  decContext   set;
+  decQuad      n;
+  decQuad      quantizer;
+  Quad         q;
+  Ret_int64_t  res;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+  decContextSetRounding(&set, round);
+
+  decQuadFromInt32(&n, scale);
+  decQuadCopyNegate(&n, &n);                          // -scale
+  decQuadScaleB(&quantizer, &static_one, &n, &set);   // 1E-scale
+
+  decQuadQuantize(&q.val, &a.val, &quantizer, &set);  // a rounded to scale digits. Invalid_operation if the coefficient has more than 34 digits.
+
+  decQuadCopyNegate(&n, &n);                          // scale
+  decQuadScaleB(&q.val, &q.val, &n, &set);            // exact, the exponent of q becomes 0
+
+  if ( set.status & DEC_Errors ) {
+      res.val    = 0;
+      res.status = decContextGetStatus(&set);
+      return res;
+  }
+
+  q.status = 0;
+
+  res = mdq_to_int64(q, round);                       // q is an integer, so no rounding occurs
+  res.status |= set.status & DEC_Inexact;             // Inexact if a has been rounded by the quantize
+
+  return res;
+}
+
+
+/* conversion from packed BCD, with p.exp as exponent.
+
+   p.packed contains a pad nibble, which must be 0, 34 digits and the sign nibble, from 0xA to 0xF (0xB and 0xD are negative).
+   If a nibble is not valid, or if the exponent is out of range, the result is NaN with Invalid_operation status.
+*/
+Quad mdq_from_packed(Packed_BCD p) {
+  Quad  res = {.status = 0};
+
+  if ( p.exp > DECQUAD_Emax - DECQUAD_Pmax + 1 || p.exp < DECQUAD_Emin - DECQUAD_Pmax + 1 ) {   // also rejects the special exponents of Infinity and NaN
+      res.val    = mdq_nan();
+      res.status = DEC_Invalid_operation;
+      return res;
+  }
+
+  if ( decQuadFromPackedChecked(&res.val, p.exp, p.packed) == NULL ) {
+      res.val    = mdq_nan();
+      res.status = DEC_Invalid_operation;
+  }
+
+  return res;
+}
+
+
+/* conversion to packed BCD.
+
+   The sign nibble is 0xC, or 0xD if a is negative, including -0.
+   a must be finite.
+*/
+Packed_BCD mdq_to_packed(decQuad a) {
+  Packed_BCD  res;
+
+  decQuadToPacked(&a, &res.exp, res.packed);
+
+  return res;
+}
+
+
+/* former conversion of decQuad to int64_t, through a string.
+
+   It is only used by the tests and benchmarks of mdq_to_int64.
+*/
+Ret_int64_t mdq_to_int64_via_string(Quad a, int round) {
   decContext   set;
   decQuad      a_integral;
   decQuad      a_integral_quantized;
@@ -9,7 +9,6 @@ import "C"
 import (
 	"fmt"
 	"math"
-	"strconv"
 	"strings"
 	"sync"
 	"unsafe"
@@ -282,12 +281,25 @@ func DecNumberMacros() string {
 	return decNumberMacros
 }
 
-// g_nan, g_zero and g_one are private variable, because else, a user of the package can change their value by doing decnum.G_ZERO = ...
+// g_nan, g_zero, g_one, etc are private variable, because else, a user of the package can change their value by doing decnum.G_ZERO = ...
 
 var (
-	g_nan  Quad = nan_for_varinit()     // a constant Quad with value NaN. It runs BEFORE init().
-	g_zero Quad = zero_for_varinit()    // a constant Quad with value 0.   It runs BEFORE init().
-	g_one  Quad = quad_for_varinit("1") // a constant Quad with value 1.   It runs BEFORE init().
+	g_nan  Quad = nan_for_varinit()  // a constant Quad with value NaN. It runs BEFORE init().
+	g_zero Quad = zero_for_varinit() // a constant Quad with value 0.   It runs BEFORE init().
+	g_one  Quad = MustParse("1")     // a constant Quad with value 1.   It runs BEFORE init().
+
+	g_pi    Quad = MustParse("3.141592653589793238462643383279503")  // Pi, rounded to 34 digits
+	g_e     Quad = MustParse("2.718281828459045235360287471352662")  // e, rounded to 34 digits
+	g_ln2   Quad = MustParse("0.6931471805599453094172321214581766") // natural logarithm of 2, rounded to 34 digits
+	g_ln10  Quad = MustParse("2.302585092994045684017991454684364")  // natural logarithm of 10, rounded to 34 digits
+	g_sqrt2 Quad = MustParse("1.414213562373095048801688724209698")  // square root of 2, rounded to 34 digits
+
+	g_max_quad           Quad = MustParse("9.999999999999999999999999999999999E+6144") // largest finite Quad
+	g_smallest_normal    Quad = MustParse("1E-6143")                                   // smallest positive normal Quad
+	g_smallest_subnormal Quad = MustParse("1E-6176")                                   // smallest positive subnormal Quad
+	g_epsilon            Quad = MustParse("1E-33")                                     // difference between 1 and the next Quad, 1.000000000000000000000000000000001
+	g_pos_inf            Quad = MustParse("Infinity")
+	g_neg_inf            Quad = MustParse("-Infinity")
 )
 
 // used only to initialize the global variable g_nan.
@@ -314,16 +326,19 @@ func zero_for_varinit() (r Quad) {
 	return Quad{val: val, status: 0}
 }
 
-// used only to initialize some global variables, like g_one.
+// MustParse returns a Quad from a string, like FromString.
+// It panics if the string is not a valid number.
 //
-// So, it runs BEFORE init().
+// It is useful to initialize package-level variables, as it can be called before init():
+//
+//     var taxRate = decnum.MustParse("0.196")
 //
-func quad_for_varinit(s string) (r Quad) {
+func MustParse(s string) Quad {
 	var val Quad
 	var err error
 
 	if val, err = FromString(s); err != nil {
-		panic("decnum: initialization error in quad_for_varinit() " + err.Error())
+		panic(fmt.Sprintf("decnum: MustParse(%q) failed: %s", s, err.Error()))
 	}
 
 	return val
@@ -395,6 +410,85 @@ func NaN() (r Quad) {
 	return g_nan
 }
 
+// Pi returns the value of Pi, rounded to 34 digits: 3.141592653589793238462643383279503
+//
+func Pi() (r Quad) {
+
+	return g_pi
+}
+
+// E returns the value of e, the base of natural logarithms, rounded to 34 digits: 2.718281828459045235360287471352662
+//
+func E() (r Quad) {
+
+	return g_e
+}
+
+// Ln2 returns the natural logarithm of 2, rounded to 34 digits: 0.6931471805599453094172321214581766
+//
+func Ln2() (r Quad) {
+
+	return g_ln2
+}
+
+// Ln10 returns the natural logarithm of 10, rounded to 34 digits: 2.302585092994045684017991454684364
+//
+func Ln10() (r Quad) {
+
+	return g_ln10
+}
+
+// Sqrt2 returns the square root of 2, rounded to 34 digits: 1.414213562373095048801688724209698
+//
+func Sqrt2() (r Quad) {
+
+	return g_sqrt2
+}
+
+// MaxQuad returns the largest finite Quad value: 9.999999999999999999999999999999999E+6144
+//
+func MaxQuad() (r Quad) {
+
+	return g_max_quad
+}
+
+// SmallestNormal returns the smallest positive normal Quad value: 1E-6143
+//
+// Smaller numbers are subnormal, and have less than 34 digits of precision.
+//
+func SmallestNormal() (r Quad) {
+
+	return g_smallest_normal
+}
+
+// SmallestSubnormal returns the smallest positive Quad value: 1E-6176
+//
+func SmallestSubnormal() (r Quad) {
+
+	return g_smallest_subnormal
+}
+
+// Epsilon returns 1E-33, the difference between 1 and the next larger Quad value, 1.000000000000000000000000000000001
+//
+func Epsilon() (r Quad) {
+
+	return g_epsilon
+}
+
+// PositiveInfinity returns +Infinity Quad value.
+//
+func PositiveInfinity() (r Quad) {
+
+	return g_pos_inf
+}
+
+// NegativeInfinity returns -Infinity Quad value.
+//
+func NegativeInfinity() (r Quad) {
+
+	return g_neg_inf
+}
+
 // Copy returns a copy of a.
 //
 // But it is easier to just use '=' :
@@ -561,6 +655,30 @@ func (a Quad) Abs() Quad {
 	return Quad(C.mdq_abs(C.struct_Quad(a)))
 }
 
+// Pow returns a raised to the power of b.
+//
+// If b is an integral value, the result is exact if possible, else it is rounded to 34 digits.
+// If b is not integral, a must not be negative, and the result is rounded to 34 digits.
+//
+//      Pow(0, 0)               is NaN, and sets the InvalidOperation flag.
+//      Pow(negative, 0.5)      is NaN, and sets the InvalidOperation flag.
+//      Pow(0, negative)        is +Inf.
+//
+func (a Quad) Pow(b Quad) Quad {
+
+	return Quad(C.mdq_power(C.struct_Quad(a), C.struct_Quad(b)))
+}
+
+// Sqrt returns the square root of a, correctly rounded with RoundHalfEven mode.
+//
+//      Sqrt(-0) is -0.
+//      Sqrt(a) is NaN if a < 0, and sets the InvalidOperation flag.
+//
+func (a Quad) Sqrt() Quad {
+
+	return Quad(C.mdq_sqrt_mode(C.struct_Quad(a), C.int(RoundHalfEven)))
+}
+
 /************************************************************************/
 /*                                                                      */
 /*                            IsFinite, etc                             */
@@ -683,6 +801,22 @@ func (a Quad) IsNegative() bool {
 	return false
 }
 
+// IsCanonical returns true if the encoding of a is canonical.
+//
+// All results of operations are canonical. Only a Quad created from bytes can be non-canonical, e.g. with a declet which is not one of the 1000 preferred encodings.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) IsCanonical() bool {
+
+	if C.mdq_is_canonical(a.val) != 0 {
+		return true
+	}
+
+	return false
+}
+
 // GetExponent returns the exponent of a.
 //
 //      The representation of a number is:
@@ -789,6 +923,17 @@ func (a Quad) Less(b Quad) bool {
 	return false
 }
 
+// Cmp compares a and b.
+// It returns CmpLess, CmpEqual or CmpGreater, or CmpNaN if a or b is NaN.
+//
+// The status fields of a and b are not checked.
+// If you need to check them, you can call a.Error() and b.Error().
+//
+func (a Quad) Cmp(b Quad) CmpFlag {
+
+	return CmpFlag(C.mdq_compare(C.struct_Quad(a), C.struct_Quad(b)))
+}
+
 /************************************************************************/
 /*                                                                      */
 /*                   conversion from string and numbers                 */
@@ -840,14 +985,30 @@ func FromInt32(value int32) Quad {
 //
 // No error occurs.
 //
-// Note that FromInt64 is slower than FromInt32, because the underlying C decNumber package has no function that converts directly from int64.
-// So, int64 is first converted to string, and then to Quad.
+// The underlying C decNumber package has no function that converts directly from int64, so the digits of value are written directly into the coefficient.
 //
 func FromInt64(value int64) Quad {
 
 	return Quad(C.mdq_from_int64(C.int64_t(value)))
 }
 
+// FromUint64 returns a Quad from a uint64 value.
+//
+// No error occurs.
+//
+func FromUint64(value uint64) Quad {
+
+	return Quad(C.mdq_from_uint64(C.uint64_t(value)))
+}
+
+// fromInt64ViaString is the former implementation of FromInt64, which converts value to string, and then to Quad.
+// It is only used by tests and benchmarks.
+//
+func fromInt64ViaString(value int64) Quad {
+
+	return Quad(C.mdq_from_int64_via_string(C.int64_t(value)))
+}
+
 /************************************************************************/
 /*                                                                      */
 /*                      conversion to string                            */
@@ -1053,11 +1214,12 @@ func (a Quad) ToInt32(rounding RoundingMode) (int32, error) {
 // ToInt64 returns the int64 value from a.
 // The rounding passed as argument is used, instead of the rounding mode of context which is ignored.
 //
+// If a is Infinite or NaN, or if the rounded value doesn't fit in int64, InvalidOperation error is returned.
+//
 // The status field of a is not checked.
 // If you need to check the status of a, you can call a.Error().
 //
-// Note that ToInt64 is slower than ToInt32, because the underlying C decNumber package has no function that converts directly to int64.
-// So, the number is first converted to string, and then to int64.
+// The underlying C decNumber package has no function that converts directly to int64, so the value is computed from the digits of the coefficient.
 //
 func (a Quad) ToInt64(rounding RoundingMode) (int64, error) {
 	var result C.Ret_int64_t
@@ -1071,30 +1233,119 @@ func (a Quad) ToInt64(rounding RoundingMode) (int64, error) {
 	return int64(result.val), nil
 }
 
-// ToFloat64 returns the float64 value from a.
+// toInt64ViaString is the former implementation of ToInt64, which converts a to string, and then to int64.
+// It is only used by tests and benchmarks.
+//
+func (a Quad) toInt64ViaString(rounding RoundingMode) (int64, error) {
+	var result C.Ret_int64_t
+
+	result = C.mdq_to_int64_via_string(C.struct_Quad(a), C.int(rounding))
+
+	if Status(result.status)&ErrorMask != 0 {
+		return 0, newError(Status(result.status))
+	}
+
+	return int64(result.val), nil
+}
+
+// ToUint32 returns the uint32 value from a.
+// The rounding passed as argument is used.
+//
+// If a is Infinite or NaN, or if the rounded value is negative or doesn't fit in uint32, InvalidOperation error is returned.
+// -0, or a negative number rounded to 0 (e.g. -0.2), gives 0.
 //
 // The status field of a is not checked.
 // If you need to check the status of a, you can call a.Error().
 //
-func (a Quad) ToFloat64() (float64, error) {
-	var (
-		err error
-		val float64
-	)
+func (a Quad) ToUint32(rounding RoundingMode) (uint32, error) {
+	var result C.Ret_uint32_t
 
-	if a.IsNaN() { // because strconv.ParseFloat doesn't parse signaling sNaN
-		return math.NaN(), nil
+	result = C.mdq_to_uint32(C.struct_Quad(a), C.int(rounding))
+
+	if Status(result.status)&ErrorMask != 0 {
+		return 0, newError(Status(result.status))
 	}
 
-	if val, err = strconv.ParseFloat(a.String(), 64); err != nil {
+	return uint32(result.val), nil
+}
+
+// ToUint64 returns the uint64 value from a.
+// The rounding passed as argument is used.
+//
+// If a is Infinite or NaN, or if the rounded value is negative or doesn't fit in uint64, InvalidOperation error is returned.
+// -0, or a negative number rounded to 0 (e.g. -0.2), gives 0.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToUint64(rounding RoundingMode) (uint64, error) {
+	var result C.Ret_uint64_t
+
+	result = C.mdq_to_uint64(C.struct_Quad(a), C.int(rounding))
+
+	if Status(result.status)&ErrorMask != 0 {
+		return 0, newError(Status(result.status))
+	}
+
+	return uint64(result.val), nil
+}
+
+// ToFloat64 returns the float64 value from a, correctly rounded.
+//
+// If a is too large for float64, NaN and an InvalidOperation error are returned.
+// See also ToFloat64Status, which returns the status of the conversion.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToFloat64() (float64, error) {
+
+	val, status := a.ToFloat64Status()
+
+	if status&Overflow != 0 {
 		return math.NaN(), QuadError(InvalidOperation)
 	}
 
 	return val, nil
 }
 
+// ToBCD returns the coefficient of a, one digit per byte, and its exponent.
+//
+//      The representation of a number is:
+//
+//           (-1)^sign  coefficient * 10^exponent
+//           where coefficient is an integer storing 34 digits.
+//
+// bcd contains the 34 digits of the coefficient, most significant digit first, with leading zeros.
+// negative is true if a is negative and not zero.
+//
+//         E.g.     -12.345     is     -12345E-3      gives    bcd = [0 0 ... 0 1 2 3 4 5]    exp = -3    negative = true
+//
+// If a is Infinite or NaN, InvalidOperation error is returned.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToBCD() (bcd [DecquadPmax]byte, exp int32, negative bool, err error) {
+	var ret C.Ret_BCD
+
+	ret = C.mdq_to_BCD(a.val) // sign will be 1 for negative and non-zero number, else, 0
+
+	if ret.inf_nan != 0 {
+		return bcd, 0, false, QuadError(InvalidOperation)
+	}
+
+	for i := 0; i < DecquadPmax; i++ {
+		bcd[i] = byte(ret.BCD[i])
+	}
+
+	return bcd, int32(ret.exp), ret.sign != 0, nil
+}
+
 // Bytes returns the internal byte representation of the value field of the Quad.
-// It is not useful, except for educational purpose.
+// It is in the byte order of the machine. FromBytes is the inverse function.
+//
+// For a format independent of the machine, use MarshalBinary, which returns the bytes in big-endian order.
 //
 func (a Quad) Bytes() (res [DecquadBytes]byte) {
 
@@ -6,12 +6,18 @@
 #include <string.h>
 #include <stdlib.h>
 #include <assert.h>
+#include <stddef.h>
 #include "decQuad.h"      // this header includes "decContext.h"
 #include "decimal128.h"   // interface to decNumber, used for decNumberPower(). Also for definition of DECDPUN.
+#include "decDouble.h"
+#include "decimal64.h"    // interface to decNumber for decDouble
+#include "decSingle.h"
+#include "decimal32.h"    // interface to decNumber for decSingle
 
 
 #define MDQ_INFINITE    1     // result is Inf or -Inf
 #define MDQ_NAN         2     // result is Nan
+#define MDQ_SNAN        3     // result is signaling sNaN
 
 
 #define CMP_LESS        1
@@ -35,6 +41,23 @@ typedef struct Quad {
 } Quad;
 
 
+// Double is the 64bits decimal number structure, with a status field like Quad.
+//
+typedef struct Double {
+    decDouble   val;
+    uint16_t    status;
+} Double;
+
+
+// Single is the 32bits decimal number structure, with a status field like Quad.
+// decSingle is only a storage format: there is no arithmetic on it.
+//
+typedef struct Single {
+    decSingle   val;
+    uint16_t    status;
+} Single;
+
+
 // struct used to pass BCD string from C to Go, by value.
 //
 typedef struct Ret_BCD {
@@ -44,6 +67,25 @@ typedef struct Ret_BCD {
   uint32_t   sign;
 } Ret_BCD;
 
+// struct used to pass the coefficient from C to Go, by value, as two integers.
+//
+typedef struct Ret_coefficient {
+  uint64_t   hi;        // 15 most significant digits of the coefficient
+  uint64_t   lo;        // 19 least significant digits of the coefficient
+  int32_t    exp;
+  uint32_t   sign;      // sign bit, also set for -0 and negative NaN
+  uint32_t   inf_nan;   // 0, MDQ_INFINITE, MDQ_NAN or MDQ_SNAN
+} Ret_coefficient;
+
+#define MDQ_PACKED_BYTES  (DECQUAD_Pmax/2 + 1)   // 18 bytes: a pad nibble, 34 digits and the sign nibble
+
+// struct used to pass packed BCD between C and Go, by value.
+//
+typedef struct Packed_BCD {
+  uint8_t    packed[MDQ_PACKED_BYTES];
+  int32_t    exp;
+} Packed_BCD;
+
 // struct used to pass string from C to Go, by value.
 //
 typedef struct Ret_str {
@@ -65,6 +107,20 @@ typedef struct Ret_int64_t {
   uint16_t    status;
 } Ret_int64_t;
 
+// struct used to pass uint32 from C to Go, by value.
+//
+typedef struct Ret_uint32_t {
+  uint32_t    val;
+  uint16_t    status;
+} Ret_uint32_t;
+
+// struct used to pass uint64 from C to Go, by value.
+//
+typedef struct Ret_uint64_t {
+  uint64_t    val;
+  uint16_t    status;
+} Ret_uint64_t;
+
 
 decQuad       mdq_zero();
 decQuad       mdq_nan();
@@ -72,9 +128,13 @@ decQuad       mdq_nan();
 
 Quad          mdq_minus(Quad a);
 Quad          mdq_add(Quad a, Quad b);
+Quad          mdq_add_mode(Quad a, Quad b, int round);
 Quad          mdq_subtract(Quad a, Quad b);
+Quad          mdq_subtract_mode(Quad a, Quad b, int round);
 Quad          mdq_multiply(Quad a, Quad b);
+Quad          mdq_multiply_mode(Quad a, Quad b, int round);
 Quad          mdq_divide(Quad a, Quad b);
+Quad          mdq_divide_mode(Quad a, Quad b, int round);
 Quad          mdq_divide_integer(Quad a, Quad b);
 Quad          mdq_remainder(Quad a, Quad b);
 Quad          mdq_max(Quad a, Quad b);
@@ -82,6 +142,7 @@ Quad          mdq_min(Quad a, Quad b);
 Quad          mdq_to_integral(Quad a, int round);
 Quad          mdq_quantize(Quad a, Quad b, int round);
 Quad          mdq_abs(Quad a);
+Quad          mdq_power(Quad a, Quad b);
 
 uint32_t      mdq_is_finite(decQuad a);
 uint32_t      mdq_is_integer(decQuad a);
@@ -90,6 +151,7 @@ uint32_t      mdq_is_nan(decQuad a);
 uint32_t      mdq_is_positive(decQuad a);
 uint32_t      mdq_is_zero(decQuad a);
 uint32_t      mdq_is_negative(decQuad a);
+uint32_t      mdq_is_canonical(decQuad a);
 int32_t       mdq_get_exponent(decQuad a);
 
 uint32_t      mdq_compare(Quad a, Quad b);
@@ -97,14 +159,137 @@ uint32_t      mdq_compare(Quad a, Quad b);
 Quad          mdq_from_string(char *s);
 Quad          mdq_from_int32(int32_t value);
 Quad          mdq_from_int64(int64_t value);
+Quad          mdq_from_uint64(uint64_t value);
+Quad          mdq_from_int64_via_string(int64_t value);
+Quad          mdq_from_unscaled(int64_t unscaled, int32_t scale);
 
 Ret_str       mdq_QuadToString(decQuad a);
 Ret_BCD       mdq_to_BCD(decQuad a);
 Ret_int32_t   mdq_to_int32(Quad a, int round);
 Ret_int64_t   mdq_to_int64(Quad a, int round);
+Ret_uint32_t  mdq_to_uint32(Quad a, int round);
+Ret_uint64_t  mdq_to_uint64(Quad a, int round);
+Ret_int64_t   mdq_to_int64_via_string(Quad a, int round);
+Ret_int64_t   mdq_to_unscaled(Quad a, int32_t scale, int round);
+Quad          mdq_from_packed(Packed_BCD p);
+Packed_BCD    mdq_to_packed(decQuad a);
 
 Quad          mdq_roundM(Quad a, int32_t n, int round);
 
+// mydecquad_trig.c
+
+void          mdq_trig_init(void);
+
+Quad          mdq_sin(Quad a);
+Quad          mdq_cos(Quad a);
+Quad          mdq_tan(Quad a);
+Quad          mdq_asin(Quad a);
+Quad          mdq_acos(Quad a);
+Quad          mdq_atan(Quad a);
+Quad          mdq_atan2(Quad y, Quad x);
+Quad          mdq_sinh(Quad a);
+Quad          mdq_cosh(Quad a);
+Quad          mdq_tanh(Quad a);
+
+// mydecquad_interval.c
+
+Quad          mdq_sqrt_mode(Quad a, int round);
+
+// mydecquad_float.c
+
+Ret_coefficient mdq_to_coefficient(decQuad a);
+Quad            mdq_from_coefficient(uint64_t hi, uint64_t lo, int32_t exp, uint32_t sign);
+
+// mydecdouble.c
+
+void          mdd_init(void);
+
+decDouble     mdd_zero();
+decDouble     mdd_nan();
+
+Double        mdd_minus(Double a);
+Double        mdd_add(Double a, Double b);
+Double        mdd_subtract(Double a, Double b);
+Double        mdd_multiply(Double a, Double b);
+Double        mdd_divide(Double a, Double b);
+Double        mdd_divide_integer(Double a, Double b);
+Double        mdd_remainder(Double a, Double b);
+Double        mdd_max(Double a, Double b);
+Double        mdd_min(Double a, Double b);
+Double        mdd_to_integral(Double a, int round);
+Double        mdd_quantize(Double a, Double b, int round);
+Double        mdd_abs(Double a);
+Double        mdd_power(Double a, Double b);
+Double        mdd_sqrt(Double a);
+
+uint32_t      mdd_is_finite(decDouble a);
+uint32_t      mdd_is_infinite(decDouble a);
+uint32_t      mdd_is_nan(decDouble a);
+uint32_t      mdd_is_positive(decDouble a);
+uint32_t      mdd_is_zero(decDouble a);
+uint32_t      mdd_is_negative(decDouble a);
+uint32_t      mdd_is_canonical(decDouble a);
+int32_t       mdd_get_exponent(decDouble a);
+
+uint32_t      mdd_compare(Double a, Double b);
+
+Double        mdd_from_string(char *s);
+Double        mdd_from_int32(int32_t value);
+Double        mdd_from_int64(int64_t value);
+
+Quad          mdd_to_quad(Double a);
+Double        mdd_from_quad(Quad a);
+
+Double        mdd_roundM(Double a, int32_t n, int round);
+
+// mydecsingle.c
+
+Single        mds_from_string(char *s);
+Double        mds_to_double(Single a);
+Quad          mds_to_quad(Single a);
+Single        mds_from_double(Double a);
+Single        mds_from_quad(Quad a);
+uint32_t      mds_is_canonical(decSingle a);
+
+// mydecbig.c
+
+#define MDB_PLUS          1     // operations of mdb_operation()
+#define MDB_MINUS         2
+#define MDB_ABS           3
+#define MDB_SQRT          4
+#define MDB_TO_INTEGRAL   5
+#define MDB_ADD           6
+#define MDB_SUBTRACT      7
+#define MDB_MULTIPLY      8
+#define MDB_DIVIDE        9
+#define MDB_DIVIDE_INT   10
+#define MDB_REMAINDER    11
+#define MDB_MAX          12
+#define MDB_MIN          13
+#define MDB_QUANTIZE     14
+#define MDB_POWER        15
+
+size_t        mdb_size(int32_t digits);
+
+uint32_t      mdb_operation(int op, decNumber *res, const decNumber *a, const decNumber *b, int32_t digits, int round, uint32_t status);
+uint32_t      mdb_round(decNumber *res, const decNumber *a, int32_t n, int32_t digits, int round, uint32_t status);
+uint32_t      mdb_compare(const decNumber *a, const decNumber *b);
+
+uint32_t      mdb_is_finite(const decNumber *a);
+uint32_t      mdb_is_infinite(const decNumber *a);
+uint32_t      mdb_is_nan(const decNumber *a);
+uint32_t      mdb_is_positive(const decNumber *a);
+uint32_t      mdb_is_zero(const decNumber *a);
+uint32_t      mdb_is_negative(const decNumber *a);
+int32_t       mdb_get_exponent(const decNumber *a);
+
+uint32_t      mdb_from_string(decNumber *res, char *s, int32_t digits, int round);
+uint32_t      mdb_from_quad(decNumber *res, Quad a, int32_t digits, int round);
+Quad          mdb_to_quad(const decNumber *a, int round, uint32_t status);
+Quad          mdb_divide_to_quad(const decNumber *a, const decNumber *b, int round);
+size_t        mdb_to_string(const decNumber *a, char *s);
+void          mdb_to_BCD(const decNumber *a, uint8_t *bcd);
+
 
 #endif
 
@@ -0,0 +1,364 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"unsafe"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                     16-byte binary representation                    */
+/*                                                                      */
+/************************************************************************/
+
+// The value of a Quad is an IEEE 754 decimal128 number, with the coefficient in Densely Packed Decimal (DPD) encoding.
+//
+// In memory, the 16 bytes are in the byte order of the machine.
+// The binary format of MarshalBinary and GobEncode is the same, but always in big-endian order, the first byte containing the sign bit.
+// It is the format of DB2 DECFLOAT(34), and it is independent of the machine:
+//
+//      1            is    22 08 00 00 00 00 00 00 00 00 00 00 00 00 00 01
+//      -12.345      is    a2 07 40 00 00 00 00 00 00 00 00 00 00 00 49 c5
+//
+// The status field is not stored.
+
+// g_little_endian is true if the machine is little-endian.
+//
+var g_little_endian = func() bool {
+	var n uint16 = 1
+
+	return *(*byte)(unsafe.Pointer(&n)) == 1
+}()
+
+// FromBytes returns the Quad whose internal representation is b, in the byte order of the machine, as returned by Bytes.
+//
+// If the encoding of b is not canonical, NaN and an InvalidOperation error are returned.
+// On success, the status of the result is 0.
+//
+func FromBytes(b [DecquadBytes]byte) (Quad, error) {
+	var r Quad
+
+	for i := range r.val {
+		r.val[i] = b[i]
+	}
+
+	if !r.IsCanonical() {
+		return NaN().SetStatusFlags(InvalidOperation), QuadError(InvalidOperation)
+	}
+
+	return r, nil
+}
+
+// bigEndianBytes returns the bytes of b in big-endian order, or the bytes of a big-endian b in the byte order of the machine.
+//
+func bigEndianBytes(b [DecquadBytes]byte) [DecquadBytes]byte {
+
+	if g_little_endian {
+		for i, j := 0, DecquadBytes-1; i < j; i, j = i+1, j-1 {
+			b[i], b[j] = b[j], b[i]
+		}
+	}
+
+	return b
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+//
+// It returns the 16 bytes of the value of a, in big-endian order. The status of a is not stored.
+//
+func (a Quad) MarshalBinary() ([]byte, error) {
+
+	b := bigEndianBytes(a.Bytes())
+
+	return b[:], nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+//
+// data must contain 16 bytes in big-endian order, as returned by MarshalBinary, with a canonical encoding.
+// Else, an InvalidOperation error is returned, and a is not changed.
+// On success, the status of a is 0.
+//
+func (a *Quad) UnmarshalBinary(data []byte) error {
+	var b [DecquadBytes]byte
+
+	if len(data) != DecquadBytes {
+		return QuadError(InvalidOperation)
+	}
+
+	copy(b[:], data)
+
+	r, err := FromBytes(bigEndianBytes(b))
+	if err != nil {
+		return err
+	}
+
+	*a = r
+
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface. The format is the same as MarshalBinary.
+//
+func (a Quad) GobEncode() ([]byte, error) {
+
+	return a.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface. The format is the same as UnmarshalBinary.
+//
+func (a *Quad) GobDecode(data []byte) error {
+
+	return a.UnmarshalBinary(data)
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                 BID encoding (Binary Integer Decimal)                */
+/*                                                                      */
+/************************************************************************/
+
+// IEEE 754 defines two encodings of decimal128: DPD, which is used by Quad, and BID, which is used by the Intel decimal library and MongoDB.
+// In BID, the coefficient is a 113-bit binary integer, instead of 11 declets of 3 digits.
+//
+// As a 128-bit integer, with bit 127 the most significant bit, the fields of a BID decimal128 are:
+//
+//      bit 127             sign
+//      bits 126-113        exponent + 6176, if bits 126-125 are not 11
+//      bits 112-0          coefficient
+//
+//      bits 126-122        11110 for Infinity, 11111 for NaN
+//      bit 121             1 for sNaN
+//      bits 109-0          NaN payload
+//
+// If bits 126-125 are 11, and the number is not Infinity or NaN, the coefficient is 100 followed by bits 110-0 and the exponent is in bits 124-111.
+// Such a coefficient is larger than 10^34 - 1, and is non-canonical.
+//
+// ToBID and FromBID use the big-endian byte order, as MarshalBinary, the first byte containing the sign bit.
+
+const (
+	bidExponentBias = 6176
+	bidInfinity     = 0x78 << 56 // high 64 bits of +Infinity
+	bidNaN          = 0x7C << 56 // high 64 bits of NaN
+	bidSignalingNaN = 0x7E << 56 // high 64 bits of sNaN
+	bidSignBit      = 1 << 63
+)
+
+// ToBID returns the BID encoding of a, in big-endian order.
+//
+//      1            gives   30 40 00 00 00 00 00 00 00 00 00 00 00 00 00 01
+//      -12.345      gives   b0 3a 00 00 00 00 00 00 00 00 00 00 00 00 30 39
+//
+// The conversion is always exact. The result is canonical.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToBID() (b [DecquadBytes]byte) {
+	var hi, lo uint64
+
+	c := C.mdq_to_coefficient(a.val)
+
+	hi, lo = coefficientBinary(c) // coefficient < 10^34 < 2^113, or NaN payload < 10^33 < 2^110
+
+	switch c.inf_nan {
+	case C.MDQ_INFINITE:
+		hi, lo = bidInfinity, 0
+	case C.MDQ_NAN:
+		hi |= bidNaN
+	case C.MDQ_SNAN:
+		hi |= bidSignalingNaN
+	default:
+		hi |= uint64(int64(c.exp)+bidExponentBias) << 49
+	}
+
+	if c.sign != 0 {
+		hi |= bidSignBit
+	}
+
+	binary.BigEndian.PutUint64(b[0:8], hi)
+	binary.BigEndian.PutUint64(b[8:16], lo)
+
+	return b
+}
+
+// FromBID returns a Quad from the BID encoding b, in big-endian order.
+//
+// All encodings are accepted, and the result is exact.
+// As required by IEEE 754, a non-canonical coefficient, larger than 10^34 - 1, is replaced by 0, keeping the sign and the exponent.
+// A NaN payload larger than 10^33 - 1 is replaced by 0.
+//
+func FromBID(b [DecquadBytes]byte) Quad {
+	var (
+		coeff_hi uint64
+		exp      int32
+	)
+
+	hi := binary.BigEndian.Uint64(b[0:8])
+	lo := binary.BigEndian.Uint64(b[8:16])
+
+	negative := hi&bidSignBit != 0
+
+	switch {
+	case hi&bidNaN == bidInfinity:
+		if negative {
+			return NegativeInfinity()
+		}
+		return PositiveInfinity()
+
+	case hi&bidNaN == bidNaN:
+		return bidNaNPayload(hi, lo, negative)
+
+	case hi>>61&3 == 3: // coefficient 100 followed by bits 110-0, always larger than 10^34 - 1
+		exp = int32(hi>>47&0x3FFF) - bidExponentBias
+		lo = 0
+
+	default:
+		exp = int32(hi>>49&0x3FFF) - bidExponentBias
+		coeff_hi = hi & (1<<49 - 1)
+
+		if coeff_hi > g_1e34_hi || (coeff_hi == g_1e34_hi && lo >= g_1e34_lo) { // non-canonical
+			coeff_hi, lo = 0, 0
+		}
+	}
+
+	return fromBinaryCoefficient(coeff_hi, lo, exp, negative)
+}
+
+// bidNaNPayload returns the NaN or sNaN of the BID encoding hi, lo, with its payload.
+//
+func bidNaNPayload(hi uint64, lo uint64, negative bool) Quad {
+	var s string = "NaN"
+
+	if hi&bidSignalingNaN == bidSignalingNaN {
+		s = "sNaN"
+	}
+
+	if negative {
+		s = "-" + s
+	}
+
+	payload_hi := hi & (1<<46 - 1) // payload is in bits 109-0
+
+	if payload_hi < g_1e33_hi || (payload_hi == g_1e33_hi && lo < g_1e33_lo) { // else non-canonical, replaced by 0
+		q, r := bits.Div64(payload_hi, lo, 1e19)
+
+		if q != 0 {
+			s += strconv.FormatUint(q, 10) + fmt.Sprintf("%019d", r)
+		} else if r != 0 {
+			s += strconv.FormatUint(r, 10)
+		}
+	}
+
+	r, _ := FromString(s) // always a valid NaN
+
+	return r
+}
+
+// bidFormat describes the BID encoding of decimal64 or decimal32, which is stored in an integer of width bits.
+//
+// Unlike decimal128, a coefficient which doesn't fit in coeffBits bits can be canonical, e.g. 9999999999999999 for decimal64.
+// It is then encoded with bits 62-61 (or 30-29) set to 11, followed by the exponent, and the coefficient is 100 followed by coeffBits-2 bits.
+//
+type bidFormat struct {
+	width       uint   // 64 or 32
+	coeffBits   uint   // number of bits of the coefficient, if bits 62-61 are not 11
+	bias        int32  // exponent bias
+	maxCoeff    uint64 // largest canonical coefficient + 1
+	payloadBits uint   // number of bits of the NaN payload
+	maxPayload  uint64 // largest canonical NaN payload + 1
+}
+
+var (
+	g_bid64 = bidFormat{width: 64, coeffBits: 53, bias: 398, maxCoeff: 1e16, payloadBits: 50, maxPayload: 1e15}
+	g_bid32 = bidFormat{width: 32, coeffBits: 23, bias: 101, maxCoeff: 1e7, payloadBits: 20, maxPayload: 1e6}
+)
+
+// encode returns the BID encoding of a, which must be a Double or a Single widened to a Quad.
+//
+func (f bidFormat) encode(a Quad) uint64 {
+	var w uint64
+
+	c := C.mdq_to_coefficient(a.val)
+	coeff := uint64(c.lo) // at most 16 digits
+
+	switch c.inf_nan {
+	case C.MDQ_INFINITE:
+		w = 0x78 << (f.width - 8)
+	case C.MDQ_NAN:
+		w = 0x7C<<(f.width-8) | coeff
+	case C.MDQ_SNAN:
+		w = 0x7E<<(f.width-8) | coeff
+	default:
+		exp := uint64(int32(c.exp) + f.bias)
+
+		if coeff < 1<<f.coeffBits {
+			w = exp<<f.coeffBits | coeff
+		} else {
+			w = 3<<(f.width-3) | exp<<(f.coeffBits-2) | coeff&(1<<(f.coeffBits-2)-1)
+		}
+	}
+
+	if c.sign != 0 {
+		w |= 1 << (f.width - 1)
+	}
+
+	return w
+}
+
+// decode returns the value of the BID encoding w, as a Quad.
+// Non-canonical coefficients and NaN payloads are replaced by 0.
+//
+func (f bidFormat) decode(w uint64) Quad {
+	var (
+		exp   uint64
+		coeff uint64
+	)
+
+	negative := w>>(f.width-1) != 0
+	expMask := uint64(1)<<(f.width-1-f.coeffBits) - 1
+
+	switch {
+	case w>>(f.width-6)&0x1F == 0x1E: // 11110
+		if negative {
+			return NegativeInfinity()
+		}
+		return PositiveInfinity()
+
+	case w>>(f.width-6)&0x1F == 0x1F: // 11111
+		nan := uint64(bidNaN)
+		if w>>(f.width-7)&1 != 0 {
+			nan = bidSignalingNaN
+		}
+
+		payload := w & (1<<f.payloadBits - 1)
+		if payload >= f.maxPayload {
+			payload = 0
+		}
+
+		return bidNaNPayload(nan, payload, negative)
+
+	case w>>(f.width-3)&3 == 3:
+		exp = w >> (f.coeffBits - 2) & expMask
+		coeff = 1<<f.coeffBits | w&(1<<(f.coeffBits-2)-1)
+
+	default:
+		exp = w >> f.coeffBits & expMask
+		coeff = w & (1<<f.coeffBits - 1)
+	}
+
+	if coeff >= f.maxCoeff {
+		coeff = 0
+	}
+
+	return fromBinaryCoefficient(0, coeff, int32(exp)-f.bias, negative)
+}
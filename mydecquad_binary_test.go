@@ -0,0 +1,230 @@
+package decnum
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = Quad{}
+	_ encoding.BinaryUnmarshaler = &Quad{}
+	_ gob.GobEncoder             = Quad{}
+	_ gob.GobDecoder             = &Quad{}
+)
+
+func Test_binary(t *testing.T) {
+
+	samples := []struct {
+		a               string
+		expected_binary string
+	}{
+		{"1", "22080000000000000000000000000001"},
+		{"-12.345", "a20740000000000000000000000049c5"},
+		{"0", "22080000000000000000000000000000"},
+		{"-0", "a2080000000000000000000000000000"},
+		{"9.999999999999999999999999999999999E+6144", "77ffcff3fcff3fcff3fcff3fcff3fcff"},
+		{"1E-6176", "00000000000000000000000000000001"},
+		{"Inf", "78000000000000000000000000000000"},
+		{"-NaN", "fc000000000000000000000000000000"},
+		{"sNaN123", "7e0000000000000000000000000000a3"},
+	}
+
+	for i, sp := range samples {
+		a := must_quad(sp.a)
+
+		b, err := a.MarshalBinary()
+
+		if err != nil || hex.EncodeToString(b) != sp.expected_binary {
+			t.Fatalf("sample %d, MarshalBinary %s: %x != %s (expected)", i, sp.a, b, sp.expected_binary)
+		}
+
+		var r Quad
+
+		if err := r.UnmarshalBinary(b); err != nil || r.Bytes() != a.Bytes() || r.Status() != 0 {
+			t.Fatalf("sample %d, UnmarshalBinary %x: %s %v", i, b, r, err)
+		}
+
+		if r, err := FromBytes(a.Bytes()); err != nil || r.Bytes() != a.Bytes() {
+			t.Fatalf("sample %d, FromBytes %s: %s %v", i, sp.a, r, err)
+		}
+	}
+
+	// non-canonical encodings
+
+	invalid := []string{
+		"220800000000000000000000000003ff", // declet 0x3FF is not canonical, 999 is 0x0FF
+		"780000000000000000000000000000ff", // Infinity with non zero coefficient
+		"7c003fcff3fcff3fcff3fcff3fcff3ff", // NaN payload with non canonical declet
+		"2208000000000000000000000000ff",   // 15 bytes
+	}
+
+	for i, s := range invalid {
+		b, _ := hex.DecodeString(s)
+		r := must_quad("123")
+
+		if err := r.UnmarshalBinary(b); err == nil || r.String() != "123" {
+			t.Fatalf("invalid sample %d, UnmarshalBinary %s should fail: %s", i, s, r)
+		}
+	}
+
+	if r := must_quad("5").SetStatusFlags(Inexact); r.IsCanonical() == false {
+		t.Fatalf("IsCanonical of 5")
+	}
+
+	// gob
+
+	type record struct {
+		Name   string
+		Amount Quad
+	}
+
+	var buf bytes.Buffer
+	var back record
+
+	if err := gob.NewEncoder(&buf).Encode(record{"x", must_quad("-123.4500")}); err != nil {
+		t.Fatalf("gob Encode: %v", err)
+	}
+
+	if err := gob.NewDecoder(&buf).Decode(&back); err != nil || back.Name != "x" || back.Amount.String() != "-123.4500" {
+		t.Fatalf("gob Decode: %v %s", err, back.Amount)
+	}
+}
+
+func Test_bid(t *testing.T) {
+
+	samples := []struct {
+		a            string
+		expected_bid string
+	}{
+		{"1", "30400000000000000000000000000001"},
+		{"-12.345", "b03a0000000000000000000000003039"},
+		{"0", "30400000000000000000000000000000"},
+		{"-0", "b0400000000000000000000000000000"},
+		{"1E+6144", "5ffe314dc6448d9338c15b0a00000000"},
+		{"9.999999999999999999999999999999999E+6144", "5fffed09bead87c0378d8e63ffffffff"},
+		{"1E-6176", "00000000000000000000000000000001"},
+		{"-Inf", "f8000000000000000000000000000000"},
+		{"NaN", "7c000000000000000000000000000000"},
+		{"-sNaN", "fe000000000000000000000000000000"},
+		{"NaN123", "7c00000000000000000000000000007b"},
+		{"NaN999999999999999999999999999999999", "7c00314dc6448d9338c15b09ffffffff"},
+	}
+
+	for i, sp := range samples {
+		a := must_quad(sp.a)
+
+		b := a.ToBID()
+
+		if hex.EncodeToString(b[:]) != sp.expected_bid {
+			t.Fatalf("sample %d, ToBID %s: %x != %s (expected)", i, sp.a, b, sp.expected_bid)
+		}
+
+		if r := FromBID(b); r.Bytes() != a.Bytes() {
+			t.Fatalf("sample %d, FromBID %x: %s != %s (expected)", i, b, r, sp.a)
+		}
+	}
+
+	// non-canonical encodings
+
+	non_canonical := []struct {
+		bid             string
+		expected_result string
+	}{
+		{"3041ed09bead87c0378d8e6400000000", "0"},        // coefficient 10^34
+		{"b041ffffffffffffffffffffffffffff", "-0"},       // coefficient 2^113 - 1
+		{"6c10000000000000000000000000000a", "0"},        // coefficient 100 followed by 111 bits, exponent 6176 in bits 124-111
+		{"6c1000000000000000000000000000ff", "0"},        // idem
+		{"6c13000000000000000000000000000a", "0E+6"},     // idem, exponent 6182
+		{"7fffffffffffffffffffffffffffffff", "sNaN"},     // payload larger than 10^33 - 1
+		{"7c00314dc6448d9338c15b0a00000000", "NaN"},      // payload 10^33
+		{"7c02000000000000000000000000007b", "NaN123"},   // bits 120-110 are ignored
+		{"7a000000000000000000000000000001", "Infinity"}, // trailing bits are ignored
+	}
+
+	for i, sp := range non_canonical {
+		var b [DecquadBytes]byte
+
+		hex.Decode(b[:], []byte(sp.bid))
+
+		if r := FromBID(b); r.Bytes() != must_quad(sp.expected_result).Bytes() {
+			t.Fatalf("non-canonical sample %d, FromBID %s: %s != %s (expected)", i, sp.bid, r, sp.expected_result)
+		}
+	}
+
+	// round trip of all the operands and results of the dq*.decTest files
+
+	files, err := filepath.Glob(filepath.Join("cowlishaw_test_files", "dq*.decTest"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no dq*.decTest file: %v", err)
+	}
+
+	count := 0
+
+	for _, file_path := range files {
+		f, err := os.Open(file_path)
+		if err != nil {
+			t.Fatal("Error opening input file:", err)
+		}
+
+		scanner := bufio.NewScanner(f)
+
+		for scanner.Scan() {
+			for _, operand := range decTestOperands(scanner.Text()) {
+				var a Quad
+
+				if strings.HasPrefix(operand, "#") {
+					b, _ := hex.DecodeString(operand[1:])
+					if a.UnmarshalBinary(b) != nil {
+						continue
+					}
+				} else if a, err = FromString(operand); err != nil {
+					continue
+				}
+
+				if r := FromBID(a.ToBID()); r.Bytes() != a.Bytes() {
+					t.Fatalf("%s: BID round trip of %s: %s", file_path, operand, r)
+				}
+
+				count++
+			}
+		}
+
+		f.Close()
+	}
+
+	if count < 10000 {
+		t.Fatalf("only %d operands in dq*.decTest files", count)
+	}
+}
+
+// decTestOperands returns the operands and the result of a test line of a decTest file, without quotes.
+//
+func decTestOperands(line string) []string {
+	var operands []string
+
+	fields := strings.Fields(line)
+
+	if len(fields) < 4 || strings.HasPrefix(fields[0], "--") {
+		return nil
+	}
+
+	for i, field := range fields[2:] {
+		if field == "->" {
+			if 2+i+1 < len(fields) {
+				operands = append(operands, strings.Trim(fields[2+i+1], "'\""))
+			}
+			return operands
+		}
+
+		operands = append(operands, strings.Trim(field, "'\""))
+	}
+
+	return nil // not a test line
+}
@@ -0,0 +1,133 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                BSON Decimal128 and MongoDB Extended JSON             */
+/*                                                                      */
+/************************************************************************/
+
+// MongoDB stores a decimal in a BSON element of type 0x13. Its payload is the BID encoding of decimal128, in little-endian order:
+//
+//      1            is    01 00 00 00 00 00 00 00 00 00 00 00 00 00 40 30
+//
+// In Extended JSON, the canonical and the relaxed formats are the same, the value being written with the scientific string of IEEE 754:
+//
+//      {"$numberDecimal":"1.23"}
+//      {"$numberDecimal":"1.000E+10"}
+//      {"$numberDecimal":"-0"}
+
+const BSONDecimal128Type = 0x13 // type of a BSON element containing a Decimal128
+
+// ToBSONDecimal128 returns the payload of a BSON Decimal128 element, which is the BID encoding of a in little-endian order.
+//
+// The conversion is always exact. NaN payloads are kept.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToBSONDecimal128() (b [DecquadBytes]byte) {
+
+	bid := a.ToBID()
+
+	for i := range b {
+		b[i] = bid[DecquadBytes-1-i]
+	}
+
+	return b
+}
+
+// FromBSONDecimal128 returns a Quad from the payload of a BSON Decimal128 element, which is the BID encoding in little-endian order.
+//
+// All payloads are accepted. Non-canonical coefficients are replaced by 0, as for FromBID.
+//
+func FromBSONDecimal128(b [DecquadBytes]byte) Quad {
+	var bid [DecquadBytes]byte
+
+	for i := range bid {
+		bid[i] = b[DecquadBytes-1-i]
+	}
+
+	return FromBID(bid)
+}
+
+// BSONString returns the string of a used by MongoDB, which is the scientific string of IEEE 754.
+//
+// Unlike QuadToString, the sign of -0 is kept. NaN payloads and sign are discarded, and sNaN gives "NaN", as MongoDB doesn't keep them.
+//
+//      1.23         gives   "1.23"
+//      1.000E+10    gives   "1.000E+10"
+//      -0.00        gives   "-0.00"
+//      -sNaN12      gives   "NaN"
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) BSONString() string {
+
+	if a.IsNaN() {
+		return "NaN"
+	}
+
+	if a.IsZero() && C.mdq_to_coefficient(a.val).sign != 0 {
+		return "-" + a.QuadToString() // QuadToString discards the sign of -0
+	}
+
+	return a.QuadToString()
+}
+
+// ToExtJSON returns a in MongoDB Extended JSON, as {"$numberDecimal":"..."}, the value being written with BSONString.
+//
+// The canonical and the relaxed formats are the same for Decimal128.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToExtJSON() []byte {
+
+	return []byte(`{"$numberDecimal":` + strconv.Quote(a.BSONString()) + `}`)
+}
+
+// extJSONDecimal is the Extended JSON form of a Decimal128.
+//
+type extJSONDecimal struct {
+	NumberDecimal *string `json:"$numberDecimal"`
+}
+
+// FromExtJSON returns a Quad from MongoDB Extended JSON, which must be an object with the single key "$numberDecimal", whose value is a string.
+//
+//      {"$numberDecimal": "1.23"}         gives   1.23
+//      {"$numberDecimal": "-Infinity"}    gives   -Infinity
+//
+// The value must be exact, as MongoDB rejects a string that can't be stored without rounding.
+// If data is not valid, or if the value is not a number, or if it can't be stored exactly in a Quad, NaN and an InvalidOperation error are returned.
+//
+func FromExtJSON(data []byte) (Quad, error) {
+	var v extJSONDecimal
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&v); err != nil || v.NumberDecimal == nil || dec.More() {
+		return NaN().SetStatusFlags(InvalidOperation), QuadError(InvalidOperation)
+	}
+
+	r, err := FromString(*v.NumberDecimal)
+
+	if err != nil || r.Status()&(Inexact|ErrorMask) != 0 { // Inexact, Overflow or Underflow
+		return NaN().SetStatusFlags(InvalidOperation), QuadError(InvalidOperation)
+	}
+
+	return r, nil
+}
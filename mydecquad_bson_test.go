@@ -0,0 +1,90 @@
+package decnum
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func Test_bson(t *testing.T) {
+
+	// samples similar to the BSON corpus of the MongoDB specifications
+
+	samples := []struct {
+		a                string
+		expected_payload string
+		expected_json    string
+	}{
+		{"0", "00000000000000000000000000004030", `{"$numberDecimal":"0"}`},
+		{"-0", "000000000000000000000000000040b0", `{"$numberDecimal":"-0"}`},
+		{"1", "01000000000000000000000000004030", `{"$numberDecimal":"1"}`},
+		{"-1", "010000000000000000000000000040b0", `{"$numberDecimal":"-1"}`},
+		{"0.1", "01000000000000000000000000003e30", `{"$numberDecimal":"0.1"}`},
+		{"0.1234567890123456789012345678901234", "f2af967ed05c82de3297ff6fde3cfc2f", `{"$numberDecimal":"0.1234567890123456789012345678901234"}`},
+		{"1.000E+10", "e8030000000000000000000000004e30", `{"$numberDecimal":"1.000E+10"}`},
+		{"-0.00E-8", "00000000000000000000000000002cb0", `{"$numberDecimal":"-0E-10"}`},
+		{"9.999999999999999999999999999999999E+6144", "ffffffff638e8d37c087adbe09edff5f", `{"$numberDecimal":"9.999999999999999999999999999999999E+6144"}`},
+		{"1E-6176", "01000000000000000000000000000000", `{"$numberDecimal":"1E-6176"}`},
+		{"Infinity", "00000000000000000000000000000078", `{"$numberDecimal":"Infinity"}`},
+		{"-Infinity", "000000000000000000000000000000f8", `{"$numberDecimal":"-Infinity"}`},
+		{"NaN", "0000000000000000000000000000007c", `{"$numberDecimal":"NaN"}`},
+		{"-sNaN12", "0c0000000000000000000000000000fe", `{"$numberDecimal":"NaN"}`},
+	}
+
+	for i, sp := range samples {
+		a := must_quad(sp.a)
+
+		b := a.ToBSONDecimal128()
+
+		if hex.EncodeToString(b[:]) != sp.expected_payload {
+			t.Fatalf("sample %d, ToBSONDecimal128 %s: %x != %s (expected)", i, sp.a, b, sp.expected_payload)
+		}
+
+		if r := FromBSONDecimal128(b); r.Bytes() != a.Bytes() {
+			t.Fatalf("sample %d, FromBSONDecimal128 %x: %s != %s (expected)", i, b, r, sp.a)
+		}
+
+		if s := string(a.ToExtJSON()); s != sp.expected_json {
+			t.Fatalf("sample %d, ToExtJSON %s: %s != %s (expected)", i, sp.a, s, sp.expected_json)
+		}
+
+		if r, err := FromExtJSON([]byte(sp.expected_json)); err != nil || r.BSONString() != a.BSONString() {
+			t.Fatalf("sample %d, FromExtJSON %s: %s %v", i, sp.expected_json, r, err)
+		}
+	}
+
+	// parsing
+
+	parse := []struct {
+		json            string
+		expected_result string
+	}{
+		{`{"$numberDecimal": "1.23"}`, "1.23"},
+		{` { "$numberDecimal" : "-1E+3" } `, "-1E+3"},
+		{`{"$numberDecimal": "1E+6144"}`, "1.000000000000000000000000000000000E+6144"}, // clamped, but exact
+		{`{"$numberDecimal": "-inf"}`, "-Infinity"},
+		{`{"$numberDecimal": "1.23456789012345678901234567890123456"}`, ""}, // inexact
+		{`{"$numberDecimal": "1E+6145"}`, ""},                               // too large
+		{`{"$numberDecimal": "1E-6177"}`, ""},                               // too small
+		{`{"$numberDecimal": "abc"}`, ""},
+		{`{"$numberDecimal": 1.23}`, ""},
+		{`{"$numberDouble": "1.23"}`, ""},
+		{`{"$numberDecimal": "1.23", "x": 1}`, ""},
+		{`{}`, ""},
+		{`{"$numberDecimal": "1"} {}`, ""},
+	}
+
+	for i, sp := range parse {
+		r, err := FromExtJSON([]byte(sp.json))
+
+		if sp.expected_result == "" {
+			if err == nil || !r.IsNaN() {
+				t.Fatalf("parse sample %d, FromExtJSON %s should fail: %s", i, sp.json, r)
+			}
+			continue
+		}
+
+		if err != nil || r.BSONString() != sp.expected_result {
+			t.Fatalf("parse sample %d, FromExtJSON %s: %s %v != %s (expected)", i, sp.json, r.BSONString(), err, sp.expected_result)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                       .NET System.Decimal                            */
+/*                                                                      */
+/************************************************************************/
+
+// A .NET System.Decimal is a 96-bit unsigned integer coefficient, a scale from 0 to 28, and a sign bit.
+// Its value is (-1)^sign * coefficient / 10^scale.
+//
+// The 16-byte layout is the one written by BinaryWriter.Write(decimal), which is also the order of the int[4] returned by decimal.GetBits:
+//
+//      bytes 0-3       lo, 32 least significant bits of the coefficient
+//      bytes 4-7       mid, 32 middle bits of the coefficient
+//      bytes 8-11      hi, 32 most significant bits of the coefficient
+//      bytes 12-15     flags: bits 16-23 contain the scale, bit 31 is the sign, all other bits are 0
+//
+// Each part is a little-endian 32-bit integer.
+
+const (
+	DotNetDecimalBytes    = 16 // size of a .NET System.Decimal
+	DotNetDecimalMaxScale = 28 // largest scale of a .NET System.Decimal
+)
+
+const (
+	dotnetScaleShift = 16
+	dotnetScaleMask  = 0x00FF0000
+	dotnetSignMask   = 0x80000000
+)
+
+// dotnetParse returns the coefficient, as hi*2^64 + lo, the scale and the sign of the .NET decimal b.
+// ok is false if the flags are invalid.
+//
+func dotnetParse(b [DotNetDecimalBytes]byte) (hi uint64, lo uint64, scale int32, negative bool, ok bool) {
+
+	flags := binary.LittleEndian.Uint32(b[12:16])
+
+	if flags&^(dotnetScaleMask|dotnetSignMask) != 0 {
+		return 0, 0, 0, false, false
+	}
+
+	scale = int32(flags&dotnetScaleMask) >> dotnetScaleShift
+
+	if scale > DotNetDecimalMaxScale {
+		return 0, 0, 0, false, false
+	}
+
+	hi = uint64(binary.LittleEndian.Uint32(b[8:12]))
+	lo = uint64(binary.LittleEndian.Uint32(b[4:8]))<<32 | uint64(binary.LittleEndian.Uint32(b[0:4]))
+
+	return hi, lo, scale, flags&dotnetSignMask != 0, true
+}
+
+// FromDotNetDecimal returns a Quad from the 16-byte layout of a .NET System.Decimal.
+//
+// The conversion is always exact, as the coefficient has at most 29 digits. The exponent of the result is -scale, so that trailing zeros are kept.
+//
+//      coefficient 12345, scale 2, sign 0       gives   123.45
+//      coefficient 1000, scale 3, sign 1        gives   -1.000
+//      coefficient 0, scale 0, sign 1           gives   -0
+//
+// If the scale is above 28, or if the unused bits of the flags are not 0, NaN and an InvalidOperation error are returned, as .NET rejects these values.
+//
+func FromDotNetDecimal(b [DotNetDecimalBytes]byte) (Quad, error) {
+
+	hi, lo, scale, negative, ok := dotnetParse(b)
+
+	if !ok {
+		return NaN().SetStatusFlags(InvalidOperation), QuadError(InvalidOperation)
+	}
+
+	return fromBinaryCoefficient(hi, lo, -scale, negative), nil
+}
+
+// ToDotNetDecimal returns the 16-byte layout of a .NET System.Decimal with the value of a.
+//
+// The scale of the result is the negated exponent of a, so that trailing zeros are kept, as in .NET.
+// When the value doesn't fit, the same rules as .NET are applied:
+//
+//      - a positive exponent gives scale 0, the coefficient being multiplied by 10^exponent.
+//      - more than 28 digits after the decimal point are rounded to 28 digits, with the rounding passed as argument.
+//      - if the coefficient doesn't fit in 96 bits, the scale is decreased, rounding the last digits, until it fits.
+//
+//      123.45                                        gives   coefficient 12345, scale 2
+//      1.2E+3                                        gives   coefficient 1200, scale 0
+//      0.12345678901234567890123456789012            gives   0.1234567890123456789012345679, scale 28     (with RoundHalfEven)
+//      1234567890.1234567890123456789                gives   1234567890.1234567890123456789, scale 19
+//      12345678901234567890123.456789                gives   12345678901234567890123.456789, scale 6
+//      79228162514264337593543950335.9               gives   Overflow error, as it is rounded to 2^96     (with RoundHalfEven)
+//
+// Numbers too small for scale 28 become 0 with scale 28, with the sign of a.
+// If a is Infinite or NaN, an InvalidOperation error is returned.
+// If the integral part of a doesn't fit in 96 bits, an Overflow error is returned.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToDotNetDecimal(rounding RoundingMode) (b [DotNetDecimalBytes]byte, err error) {
+	var (
+		c      C.Ret_coefficient
+		scale  int32
+		digits int32
+	)
+
+	c = C.mdq_to_coefficient(a.val)
+
+	if c.inf_nan != 0 {
+		return b, QuadError(InvalidOperation)
+	}
+
+	scale = -a.GetExponent()
+
+	if scale < 0 {
+		scale = 0
+	}
+
+	if scale > DotNetDecimalMaxScale {
+		scale = DotNetDecimalMaxScale
+	}
+
+	// a 96-bit coefficient has at most 29 digits. Digits above are removed first, by decreasing the scale.
+
+	if c.hi != 0 {
+		digits = 19 + digitsOf(uint64(c.hi))
+	} else {
+		digits = digitsOf(uint64(c.lo))
+	}
+
+	if excess := digits + a.GetExponent() + scale - 29; excess > 0 && !a.IsZero() {
+		scale -= excess
+	}
+
+	for ; scale >= 0; scale-- {
+		r := a.ClearStatus().Quantize(FromUnscaled(1, scale), rounding) // exact if scale >= -exponent
+
+		if r.Status()&InvalidOperation != 0 { // more than 34 digits
+			break
+		}
+
+		c = C.mdq_to_coefficient(r.val)
+
+		h, l := coefficientBinary(c)
+
+		if h>>32 != 0 { // doesn't fit in 96 bits, may be caused by rounding up, as in 99.99 with scale 1
+			continue
+		}
+
+		flags := uint32(scale) << dotnetScaleShift
+		if c.sign != 0 {
+			flags |= dotnetSignMask
+		}
+
+		binary.LittleEndian.PutUint32(b[0:4], uint32(l))
+		binary.LittleEndian.PutUint32(b[4:8], uint32(l>>32))
+		binary.LittleEndian.PutUint32(b[8:12], uint32(h))
+		binary.LittleEndian.PutUint32(b[12:16], flags)
+
+		return b, nil
+	}
+
+	return [DotNetDecimalBytes]byte{}, QuadError(Overflow)
+}
+
+// FormatDotNetDecimal returns the string of a .NET System.Decimal, as returned by decimal.ToString() with the invariant culture.
+//
+// All the digits of the scale are written, and exponent notation is never used.
+//
+//      coefficient 12345, scale 2, sign 1        gives   "-123.45"
+//      coefficient 1000, scale 3, sign 0         gives   "1.000"
+//      coefficient 1, scale 28, sign 0           gives   "0.0000000000000000000000000001"
+//      coefficient 0, scale 2, sign 1            gives   "0.00", as .NET never prints the sign of zero
+//
+// If the scale is above 28, or if the unused bits of the flags are not 0, an InvalidOperation error is returned.
+//
+func FormatDotNetDecimal(b [DotNetDecimalBytes]byte) (string, error) {
+	var s string
+
+	hi, lo, scale, negative, ok := dotnetParse(b)
+
+	if !ok {
+		return "", QuadError(InvalidOperation)
+	}
+
+	q, r := bits.Div64(hi, lo, 1e19)
+
+	if q != 0 {
+		s = strconv.FormatUint(q, 10) + strings.Repeat("0", 19-int(digitsOf(r))) + strconv.FormatUint(r, 10)
+	} else {
+		s = strconv.FormatUint(r, 10)
+	}
+
+	if len(s) <= int(scale) {
+		s = strings.Repeat("0", int(scale)-len(s)+1) + s
+	}
+
+	if scale > 0 {
+		s = s[:len(s)-int(scale)] + "." + s[len(s)-int(scale):]
+	}
+
+	if negative && (hi != 0 || lo != 0) {
+		s = "-" + s
+	}
+
+	return s, nil
+}
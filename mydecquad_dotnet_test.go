@@ -0,0 +1,115 @@
+package decnum
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// dotnet returns the 16-byte layout of a .NET decimal, from the int[4] returned by decimal.GetBits.
+//
+func dotnet(lo, mid, hi, flags uint32) (b [DotNetDecimalBytes]byte) {
+
+	binary.LittleEndian.PutUint32(b[0:4], lo)
+	binary.LittleEndian.PutUint32(b[4:8], mid)
+	binary.LittleEndian.PutUint32(b[8:12], hi)
+	binary.LittleEndian.PutUint32(b[12:16], flags)
+
+	return b
+}
+
+func Test_dotnet_decimal(t *testing.T) {
+
+	samples := []struct {
+		b               [DotNetDecimalBytes]byte
+		expected_quad   string
+		expected_string string
+	}{
+		{dotnet(12345, 0, 0, 2<<16), "123.45", "123.45"},
+		{dotnet(1000, 0, 0, 3<<16|1<<31), "-1.000", "-1.000"},
+		{dotnet(1, 0, 0, 28<<16), "0.0000000000000000000000000001", "0.0000000000000000000000000001"},
+		{dotnet(0xFFFFFFFF, 0xFFFFFFFF, 0xFFFFFFFF, 0), "79228162514264337593543950335", "79228162514264337593543950335"},
+		{dotnet(0xFFFFFFFF, 0xFFFFFFFF, 0xFFFFFFFF, 28<<16|1<<31), "-7.9228162514264337593543950335", "-7.9228162514264337593543950335"},
+		{dotnet(0, 0, 1, 5<<16), "184467440737095.51616", "184467440737095.51616"},
+		{dotnet(0, 0, 0, 2<<16), "0.00", "0.00"},
+		{dotnet(0, 0, 0, 2<<16|1<<31), "0.00", "0.00"},
+	}
+
+	for i, sp := range samples {
+		r, err := FromDotNetDecimal(sp.b)
+
+		if err != nil || r.String() != sp.expected_quad || r.Status() != 0 {
+			t.Fatalf("sample %d, FromDotNetDecimal: %s %v != %s (expected)", i, r, err, sp.expected_quad)
+		}
+
+		if s, err := FormatDotNetDecimal(sp.b); err != nil || s != sp.expected_string {
+			t.Fatalf("sample %d, FormatDotNetDecimal: %s %v != %s (expected)", i, s, err, sp.expected_string)
+		}
+
+		if b, err := r.ToDotNetDecimal(RoundHalfEven); err != nil || b != sp.b {
+			t.Fatalf("sample %d, ToDotNetDecimal: %x %v != %x (expected)", i, b, err, sp.b)
+		}
+	}
+
+	// -0 keeps its sign, but is printed without sign
+
+	if r, _ := FromDotNetDecimal(dotnet(0, 0, 0, 1<<31)); r.Bytes() != must_quad("-0").Bytes() {
+		t.Fatalf("FromDotNetDecimal -0: %s", r)
+	}
+
+	// invalid flags
+
+	for _, flags := range []uint32{29 << 16, 1, 1 << 30, 1 << 24} {
+		if r, err := FromDotNetDecimal(dotnet(1, 0, 0, flags)); err == nil || !r.IsNaN() || r.Status() != InvalidOperation {
+			t.Fatalf("FromDotNetDecimal flags %x should fail: %s %v", flags, r, err)
+		}
+
+		if _, err := FormatDotNetDecimal(dotnet(1, 0, 0, flags)); err == nil {
+			t.Fatalf("FormatDotNetDecimal flags %x should fail", flags)
+		}
+	}
+
+	// rounding and overflow rules
+
+	samples_to := []struct {
+		a               string
+		rounding        RoundingMode
+		expected_string string
+		expected_error  Status
+	}{
+		{"1.2E+3", RoundHalfEven, "1200", 0},
+		{"0E+100", RoundHalfEven, "0", 0},
+		{"-0E-6176", RoundHalfEven, "0.0000000000000000000000000000", 0},
+		{"1E-29", RoundHalfEven, "0.0000000000000000000000000000", 0},
+		{"1E-29", RoundUp, "0.0000000000000000000000000001", 0},
+		{"0.12345678901234567890123456789012", RoundHalfEven, "0.1234567890123456789012345679", 0},
+		{"0.12345678901234567890123456789012", RoundDown, "0.1234567890123456789012345678", 0},
+		{"1234567890.1234567890123456789", RoundHalfEven, "1234567890.1234567890123456789", 0},
+		{"1234567890.12345678901234567891", RoundHalfEven, "1234567890.1234567890123456789", 0},
+		{"99999999999999999999999999999.5", RoundHalfEven, "", Overflow},
+		{"9999999999999999999999999999.95", RoundHalfEven, "10000000000000000000000000000", 0},
+		{"79228162514264337593543950335.4", RoundHalfEven, "79228162514264337593543950335", 0},
+		{"79228162514264337593543950335.9", RoundHalfEven, "", Overflow},
+		{"79228162514264337593543950335.9", RoundDown, "79228162514264337593543950335", 0},
+		{"8E+28", RoundHalfEven, "", Overflow},
+		{"1E+30", RoundHalfEven, "", Overflow},
+		{"Inf", RoundHalfEven, "", InvalidOperation},
+		{"NaN", RoundHalfEven, "", InvalidOperation},
+	}
+
+	for i, sp := range samples_to {
+		b, err := must_quad(sp.a).ToDotNetDecimal(sp.rounding)
+
+		if sp.expected_error != 0 {
+			if err == nil || err.(QuadError) != QuadError(sp.expected_error) {
+				t.Fatalf("sample %d, ToDotNetDecimal %s: error %v != %s (expected)", i, sp.a, err, sp.expected_error)
+			}
+			continue
+		}
+
+		s, _ := FormatDotNetDecimal(b)
+
+		if err != nil || s != sp.expected_string {
+			t.Fatalf("sample %d, ToDotNetDecimal %s: %s %v != %s (expected)", i, sp.a, s, err, sp.expected_string)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+#include "mydecquad.h"
+
+
+/************************************************************************/
+/*                   coefficient as two integers                        */
+/*                                                                      */
+/*     Used by the conversions to and from binary formats.              */
+/************************************************************************/
+
+/* The coefficient of a decQuad has 34 digits, which don't fit in a uint64_t.
+   It is returned as hi*10^19 + lo, hi having at most 15 digits and lo at most 19 digits.
+
+   The returned fields are:
+      hi, lo:    the coefficient. For NaN and sNaN, they contain the payload.
+      exp:       if a is not Inf or Nan, will contain the exponent.
+      sign:      the sign bit of a. Unlike mdq_to_BCD, it is also set for -0, and for -Inf and negative NaN.
+      inf_nan:   0 for finite numbers, else MDQ_INFINITE, MDQ_NAN or MDQ_SNAN.
+*/
+Ret_coefficient mdq_to_coefficient(decQuad a) {
+
+  uint8_t          bcd[DECQUAD_Pmax];
+  int              i;
+  Ret_coefficient  res = {.hi = 0, .lo = 0, .exp = 0, .sign = 0, .inf_nan = 0};
+
+  res.sign = decQuadGetCoefficient(&a, bcd) ? 1 : 0;
+
+  for ( i = 0; i < DECQUAD_Pmax-19; i++ ) {
+      res.hi = res.hi*10 + bcd[i];
+  }
+
+  for ( ; i < DECQUAD_Pmax; i++ ) {
+      res.lo = res.lo*10 + bcd[i];
+  }
+
+  if ( decQuadIsInfinite(&a) ) {
+      res.inf_nan = MDQ_INFINITE;
+  } else if ( decQuadIsSignaling(&a) ) {
+      res.inf_nan = MDQ_SNAN;
+  } else if ( decQuadIsNaN(&a) ) {
+      res.inf_nan = MDQ_NAN;
+  } else {
+      res.exp = decQuadGetExponent(&a);
+  }
+
+  return res;
+}
+
+
+/* inverse of mdq_to_coefficient: returns (hi*10^19 + lo) * 10^exp, with the sign bit sign.
+
+   hi must have at most 15 digits, and lo at most 19 digits.
+   The result is exact, except if exp is out of the range of decQuad, which may set Overflow, Underflow or Inexact.
+*/
+Quad mdq_from_coefficient(uint64_t hi, uint64_t lo, int32_t exp, uint32_t sign) {
+  uint8_t      bcd[DECQUAD_Pmax];
+  int          i;
+  decContext   set;
+  decQuad      n;
+  Quad         res;
+
+  for ( i = DECQUAD_Pmax-1; i >= DECQUAD_Pmax-19; i-- ) {
+      bcd[i] = lo % 10;
+      lo /= 10;
+  }
+
+  for ( ; i >= 0; i-- ) {
+      bcd[i] = hi % 10;
+      hi /= 10;
+  }
+
+  decQuadFromBCD(&res.val, 0, bcd, sign ? DECFLOAT_Sign : 0);
+  res.status = 0;
+
+  if ( exp != 0 ) {
+      decContextDefault(&set, DEC_INIT_DECQUAD);
+
+      decQuadFromInt32(&n, exp);
+      decQuadScaleB(&res.val, &res.val, &n, &set);  // exponent of res becomes exp
+      res.status = decContextGetStatus(&set);
+  }
+
+  return res;
+}
+
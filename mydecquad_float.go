@@ -0,0 +1,592 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                  conversion from binary floating point               */
+/*                                                                      */
+/************************************************************************/
+
+// FloatMode specifies how FromFloat64 and FromFloat32 convert a binary floating point number to Quad.
+//
+type FloatMode int
+
+const (
+	FloatShortest FloatMode = iota // The shortest decimal number that converts back to the same float, e.g. 0.1 gives 0.1
+	FloatExact                     // The exact binary value, rounded to 34 digits, e.g. 0.1 gives 0.1000000000000000055511151231257827 with Inexact
+)
+
+func (mode FloatMode) String() string {
+
+	switch mode {
+	case FloatShortest:
+		return "FloatShortest"
+	case FloatExact:
+		return "FloatExact"
+	default:
+		return "Unknown float mode"
+	}
+}
+
+// floatExactDigits is the number of digits after the decimal point in 'e' format, which is enough to write any float64 exactly.
+//
+// The smallest subnormal float64, 2^-1074, has 751 significant digits, and no float64 has more than 767.
+//
+const floatExactDigits = 767
+
+// FromFloat64 returns a Quad from a float64 value.
+//
+//      FloatShortest gives the shortest decimal number that converts back to f, as strconv.FormatFloat(f, 'g', -1, 64).
+//                    As float64 has at most 17 significant digits, the result is always exact, and status is 0.
+//
+//      FloatExact    gives the exact value of the binary number f, rounded to 34 digits with RoundHalfEven.
+//                    If rounding occurred, the Inexact flag is set. E.g. 0.1 gives 0.1000000000000000055511151231257827.
+//
+// NaN gives NaN, +Inf and -Inf give Infinity and -Infinity, and -0 gives -0.
+//
+// An invalid mode gives NaN, and the InvalidOperation flag is set.
+//
+func FromFloat64(f float64, mode FloatMode) Quad {
+
+	return fromFloat(f, 64, mode)
+}
+
+// FromFloat32 returns a Quad from a float32 value.
+//
+// It is the same as FromFloat64, but FloatShortest gives the shortest decimal number that converts back to the float32 f.
+// E.g. float32(0.1) gives 0.1 with FloatShortest, and 0.100000001490116119384765625 with FloatExact.
+//
+func FromFloat32(f float32, mode FloatMode) Quad {
+
+	return fromFloat(float64(f), 32, mode) // float32 to float64 conversion is exact
+}
+
+// fromFloat converts f, which is a float of bitSize 32 or 64, to Quad.
+//
+func fromFloat(f float64, bitSize int, mode FloatMode) Quad {
+	var (
+		s      string
+		result Quad
+	)
+
+	switch mode {
+	case FloatShortest:
+		s = strconv.FormatFloat(f, 'e', -1, bitSize)
+
+	case FloatExact:
+		s = strconv.FormatFloat(f, 'e', floatExactDigits, 64) // all digits are written, which are exact, followed by trailing zeros
+
+		if pos := strings.IndexByte(s, 'e'); pos >= 0 { // remove trailing zeros, so that the exponent of e.g. 0.5 is -1, as for FloatShortest
+			mantissa := strings.TrimSuffix(strings.TrimRight(s[:pos], "0"), ".")
+			s = mantissa + s[pos:]
+		}
+
+	default:
+		return NaN().SetStatusFlags(InvalidOperation)
+	}
+
+	result, _ = FromString(s) // NaN, +Inf and -Inf are also accepted by FromString
+
+	return result
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                   conversion to binary floating point                */
+/*                                                                      */
+/************************************************************************/
+
+var (
+	g_float64_pow10 = [...]float64{1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10, 1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19, 1e20, 1e21, 1e22} // all exact
+	g_float32_pow10 = [...]float32{1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10}                                                                         // all exact
+
+	g_uint64_pow10 = [...]uint64{1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10, 1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19}
+
+	g_big_1e19 = new(big.Int).SetUint64(1e19)
+)
+
+// floatFormat describes float64 or float32, for the conversion from Quad.
+//
+type floatFormat struct {
+	mantissa    uint64  // largest integer m such that all integers in [0, m] are exact
+	maxPow10    int32   // largest exact power of 10
+	overflow    int32   // numbers >= 10^overflow are larger than the largest float
+	underflow   int32   // numbers < 10^underflow are smaller than half the smallest subnormal, so round to 0
+	minNormal   float64 // smallest positive normal number
+	mantBits    uint    // number of bits of the mantissa field
+	bias        uint64  // exponent bias
+	maxExp2     uint64  // biased exponent of Inf and NaN
+	float32Type bool
+}
+
+var (
+	g_float64_format = floatFormat{1 << 53, 22, 309, -324, 0x1p-1022, 52, 1023, 0x7FF, false}
+	g_float32_format = floatFormat{1 << 24, 10, 39, -45, 0x1p-126, 23, 127, 0xFF, true}
+)
+
+const (
+	powersOfTenMinExp10 = -348
+	powersOfTenMaxExp10 = 347
+)
+
+// g_powers_of_ten contains the 128 bit mantissa of the powers of 10 from 1E-348 to 1E+347, rounded down.
+// g_powers_of_ten[i][0] contains the low 64 bits, and g_powers_of_ten[i][1] the high 64 bits. The high bit is always set.
+//
+// It is used by eiselLemire.
+//
+var g_powers_of_ten [powersOfTenMaxExp10 - powersOfTenMinExp10 + 1][2]uint64
+
+func init() {
+	var (
+		m    *big.Int
+		p    *big.Int
+		mask *big.Int
+	)
+
+	mask = new(big.Int).SetUint64(math.MaxUint64)
+
+	for q := powersOfTenMinExp10; q <= powersOfTenMaxExp10; q++ {
+		p = new(big.Int).Exp(g_big_ten, big.NewInt(int64(abs32(int32(q)))), nil)
+
+		if q >= 0 { // 10^q, shifted to 128 bits
+			if shift := 128 - p.BitLen(); shift >= 0 {
+				m = p.Lsh(p, uint(shift))
+			} else {
+				m = p.Rsh(p, uint(-shift))
+			}
+		} else { // 2^k / 10^-q, with k such that the quotient has 128 bits
+			m = new(big.Int).Lsh(big.NewInt(1), uint(127+p.BitLen()))
+			m.Quo(m, p)
+		}
+
+		g_powers_of_ten[q-powersOfTenMinExp10][0] = new(big.Int).And(m, mask).Uint64()
+		g_powers_of_ten[q-powersOfTenMinExp10][1] = new(big.Int).Rsh(m, 64).Uint64()
+	}
+}
+
+// ToFloat64Status returns the float64 value of a, correctly rounded with round-half-even, and the status of the conversion.
+//
+// The conversion is done directly from the coefficient and the exponent of a, without string conversion.
+//
+//      If a is not exactly representable as float64, Inexact is set. E.g. 0.1 gives 0.1 (the nearest float64) and Inexact.
+//      If a is too large, ±Inf is returned, and Overflow and Inexact are set.
+//      If a is too small and is rounded to a subnormal or zero, Underflow and Inexact are set.
+//
+// -0 gives -0, ±Infinity gives ±Inf, NaN gives a quiet NaN, and sNaN gives a signaling NaN. The sign of NaN is kept.
+//
+// The status field of a is not checked, and is not included in the returned status.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToFloat64Status() (float64, Status) {
+	var (
+		c      C.Ret_coefficient
+		f      float64
+		status Status
+	)
+
+	c = C.mdq_to_coefficient(a.val)
+
+	switch c.inf_nan {
+	case C.MDQ_INFINITE:
+		f = math.Inf(1)
+
+	case C.MDQ_NAN:
+		f = math.Float64frombits(0x7FF8000000000000)
+
+	case C.MDQ_SNAN:
+		f = math.Float64frombits(0x7FF4000000000000) // quiet bit is not set
+
+	default:
+		f, status = coefficientToFloat(uint64(c.hi), uint64(c.lo), int32(c.exp), &g_float64_format)
+	}
+
+	if c.sign != 0 {
+		f = math.Copysign(f, -1) // also for -0 and NaN
+	}
+
+	return f, status
+}
+
+// ToFloat32Status returns the float32 value of a, correctly rounded with round-half-even, and the status of the conversion.
+//
+// It is the same as ToFloat64Status, but for float32. E.g. 3.5E+38 gives +Inf, and Overflow and Inexact are set.
+//
+// The status field of a is not checked, and is not included in the returned status.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToFloat32Status() (float32, Status) {
+	var (
+		c      C.Ret_coefficient
+		f      float64
+		f32    float32
+		status Status
+	)
+
+	c = C.mdq_to_coefficient(a.val)
+
+	switch c.inf_nan {
+	case C.MDQ_INFINITE:
+		f32 = float32(math.Inf(1))
+
+	case C.MDQ_NAN:
+		f32 = math.Float32frombits(0x7FC00000) // NaN are not converted from float64, which could change a signaling NaN into a quiet one
+
+	case C.MDQ_SNAN:
+		f32 = math.Float32frombits(0x7FA00000) // quiet bit is not set
+
+	default:
+		f, status = coefficientToFloat(uint64(c.hi), uint64(c.lo), int32(c.exp), &g_float32_format)
+		f32 = float32(f) // exact, as f has already been rounded to float32
+	}
+
+	if c.sign != 0 {
+		f32 = math.Float32frombits(math.Float32bits(f32) | 1<<31) // also for -0 and NaN
+	}
+
+	return f32, status
+}
+
+// ToFloat32 returns the float32 value of a, correctly rounded.
+//
+// If a is too large for float32, NaN and an InvalidOperation error are returned, as for ToFloat64.
+// See also ToFloat32Status, which returns the status of the conversion.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToFloat32() (float32, error) {
+
+	f, status := a.ToFloat32Status()
+
+	if status&Overflow != 0 {
+		return float32(math.NaN()), QuadError(InvalidOperation)
+	}
+
+	return f, nil
+}
+
+// coefficientToFloat returns the positive number (hi*10^19 + lo) * 10^exp, rounded to the float format ff, and the status of the conversion.
+//
+// For float32, the returned float64 contains the float32 value.
+//
+// Small coefficients with small exponents are converted with a single floating point operation on exact operands, which is correctly rounded.
+// The other numbers are converted with the Eisel-Lemire algorithm, which is also used by strconv.ParseFloat.
+// In the rare cases where it cannot decide the rounding, and for subnormal numbers, the number is converted exactly with math/big.
+//
+func coefficientToFloat(hi uint64, lo uint64, exp int32, ff *floatFormat) (float64, Status) {
+	var (
+		f         float64
+		fbits     uint64
+		ok        bool
+		exact     bool
+		ch, cl    uint64 // coefficient, on 128 bits
+		carry     uint64
+		man       uint64 // 19 most significant digits of the coefficient
+		exp10     int32
+		truncated bool
+	)
+
+	if hi == 0 && lo == 0 {
+		return 0, 0
+	}
+
+	// fast path, for coefficient not larger than the float mantissa
+
+	if hi == 0 && lo <= ff.mantissa {
+		if exp > ff.maxPow10 && exp <= ff.maxPow10+19 && lo <= ff.mantissa/g_uint64_pow10[exp-ff.maxPow10] { // e.g. 1E+25 is 1000E+22
+			lo *= g_uint64_pow10[exp-ff.maxPow10]
+			exp = ff.maxPow10
+		}
+
+		if exp >= -ff.maxPow10 && exp <= ff.maxPow10 {
+			return fastFloat(lo, exp, ff)
+		}
+	}
+
+	// numbers too large or too small
+
+	digits := digitsOf(lo) // digits of the coefficient
+	if hi != 0 {
+		digits = digitsOf(hi) + 19
+	}
+
+	if digits-1+exp >= ff.overflow { // a >= 10^(digits-1+exp)
+		return math.Inf(1), Overflow | Inexact
+	}
+
+	if digits+exp < ff.underflow { // a < 10^(digits+exp)
+		return 0, Underflow | Inexact
+	}
+
+	// Eisel-Lemire. If the coefficient has more than 19 digits, it is truncated, and the result is correct if man and man+1 give the same float.
+
+	ch, cl = bits.Mul64(hi, 1e19)
+	cl, carry = bits.Add64(cl, lo, 0)
+	ch += carry
+
+	man, exp10 = lo, exp
+
+	if hi != 0 {
+		var rem uint64
+
+		man, rem = bits.Div64(ch, cl, g_uint64_pow10[digits-19]) // quotient has 19 digits, so ch is less than the divisor
+		exp10 = exp + digits - 19
+		truncated = rem != 0
+	}
+
+	if fbits, ok = eiselLemire(man, exp10, ff); ok && truncated {
+		fbits2, ok2 := eiselLemire(man+1, exp10, ff)
+		ok = ok2 && fbits2 == fbits
+	}
+
+	if ok {
+		if ff.float32Type {
+			f = float64(math.Float32frombits(uint32(fbits)))
+		} else {
+			f = math.Float64frombits(fbits)
+		}
+
+		return f, floatStatus(f, isExactFloat(ch, cl, exp, ff), ff)
+	}
+
+	// exact conversion. exp is now in the range of about [-360, 310]
+
+	num := new(big.Int).SetUint64(hi)
+	num.Mul(num, g_big_1e19)
+	num.Add(num, new(big.Int).SetUint64(lo))
+
+	r := new(big.Rat)
+
+	if exp >= 0 {
+		r.SetInt(num.Mul(num, new(big.Int).Exp(g_big_ten, big.NewInt(int64(exp)), nil)))
+	} else {
+		r.SetFrac(num, new(big.Int).Exp(g_big_ten, big.NewInt(int64(-exp)), nil))
+	}
+
+	if ff.float32Type {
+		var f32 float32
+		f32, exact = r.Float32()
+		f = float64(f32)
+	} else {
+		f, exact = r.Float64()
+	}
+
+	return f, floatStatus(f, exact, ff)
+}
+
+// eiselLemire returns the bits of man * 10^exp10, rounded to the float format ff, with the algorithm of Daniel Lemire,
+// "Number Parsing at a Gigabyte per Second", and Nigel Tao's implementation in strconv.
+//
+// ok is false if the algorithm cannot decide the rounding, or if the result is subnormal, infinite or zero. man must not be zero.
+//
+func eiselLemire(man uint64, exp10 int32, ff *floatFormat) (fbits uint64, ok bool) {
+
+	if exp10 < powersOfTenMinExp10 || exp10 > powersOfTenMaxExp10 {
+		return 0, false
+	}
+
+	shift := 64 - 2 - (ff.mantBits + 1) // result is first computed with 2 more bits than the mantissa
+	mask := uint64(1)<<shift - 1
+
+	// normalization
+
+	clz := bits.LeadingZeros64(man)
+	man <<= uint(clz)
+	retExp2 := uint64(217706*int64(exp10)>>16+64) + ff.bias - uint64(clz) // 217706/65536 is log2(10)
+
+	// multiplication
+
+	pow := &g_powers_of_ten[exp10-powersOfTenMinExp10]
+	xHi, xLo := bits.Mul64(man, pow[1])
+
+	// wider approximation
+
+	if xHi&mask == mask && xLo+man < man {
+		yHi, yLo := bits.Mul64(man, pow[0])
+		mergedHi, mergedLo := xHi, xLo+yHi
+		if mergedLo < xLo {
+			mergedHi++
+		}
+		if mergedHi&mask == mask && mergedLo+1 == 0 && yLo+man < man {
+			return 0, false
+		}
+		xHi, xLo = mergedHi, mergedLo
+	}
+
+	// shifting to mantissa + 2 bits
+
+	msb := xHi >> 63
+	retMantissa := xHi >> (msb + uint64(shift))
+	retExp2 -= 1 ^ msb
+
+	// half-way ambiguity
+
+	if xLo == 0 && xHi&mask == 0 && retMantissa&3 == 1 {
+		return 0, false
+	}
+
+	// from mantissa + 2 bits to mantissa + 1 bits, rounding half to even
+
+	retMantissa += retMantissa & 1
+	retMantissa >>= 1
+	if retMantissa>>(ff.mantBits+1) > 0 {
+		retMantissa >>= 1
+		retExp2 += 1
+	}
+
+	if retExp2-1 >= ff.maxExp2-1 { // retExp2 is 0 or negative (subnormal), or too large (infinite)
+		return 0, false
+	}
+
+	return retExp2<<ff.mantBits | retMantissa&(1<<ff.mantBits-1), true
+}
+
+// isExactFloat returns true if the coefficient (ch*2^64 + cl) * 10^exp is exactly representable in the float format ff.
+// The number must be non-zero, and must not overflow nor underflow ff.
+//
+// The number is exact if it is odd * 2^n, with odd having no more bits than the mantissa.
+//
+func isExactFloat(ch uint64, cl uint64, exp int32, ff *floatFormat) bool {
+	var (
+		odd uint64
+		tz  int
+	)
+
+	if exp < 0 { // the coefficient must be divisible by 5^-exp. As the coefficient is less than 2^113, which is less than 5^49, it is not possible if -exp > 48
+		if exp < -48 {
+			return false
+		}
+
+		for ; exp < 0; exp++ {
+			var rem uint64
+
+			qh := ch / 5
+			cl, rem = bits.Div64(ch%5, cl, 5)
+			ch = qh
+
+			if rem != 0 {
+				return false
+			}
+		}
+	}
+
+	// odd part of the coefficient
+
+	if cl != 0 {
+		tz = bits.TrailingZeros64(cl)
+	} else {
+		tz = 64 + bits.TrailingZeros64(ch)
+	}
+
+	if tz >= 64 {
+		ch, cl = 0, ch>>uint(tz-64)
+	} else if tz > 0 {
+		ch, cl = ch>>uint(tz), cl>>uint(tz)|ch<<uint(64-tz)
+	}
+
+	if ch != 0 || cl >= ff.mantissa {
+		return false
+	}
+
+	odd = cl
+
+	for ; exp > 0; exp-- { // multiply by 5^exp
+		odd *= 5
+
+		if odd >= ff.mantissa {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fastFloat returns coeff * 10^exp, rounded to the float format ff.
+// coeff must not be larger than ff.mantissa, and exp must be in [-ff.maxPow10, ff.maxPow10], so that both operands are exact.
+//
+func fastFloat(coeff uint64, exp int32, ff *floatFormat) (float64, Status) {
+	var (
+		f     float64
+		exact bool
+	)
+
+	if ff.float32Type {
+		c := float32(coeff)
+		p := g_float32_pow10[abs32(exp)]
+
+		if exp >= 0 {
+			f = float64(c) * float64(p) // exact in float64, as c and p have at most 24 significant bits
+			exact = float64(float32(f)) == f
+			f = float64(float32(f))
+		} else {
+			f32 := c / p
+			f = float64(f32)
+			exact = f*float64(p) == float64(c) // exact in float64
+		}
+	} else {
+		c := float64(coeff)
+		p := g_float64_pow10[abs32(exp)]
+
+		if exp >= 0 {
+			f = c * p
+			exact = math.FMA(c, p, -f) == 0 // the rounding error of a product is computed exactly by FMA
+		} else {
+			f = c / p
+			exact = math.FMA(f, p, -c) == 0 // the remainder of a division is computed exactly by FMA
+		}
+	}
+
+	return f, floatStatus(f, exact, ff)
+}
+
+// floatStatus returns the status of a conversion to float, giving the result f.
+//
+func floatStatus(f float64, exact bool, ff *floatFormat) Status {
+
+	switch {
+	case exact:
+		return 0
+	case math.IsInf(f, 0):
+		return Overflow | Inexact
+	case f < ff.minNormal:
+		return Underflow | Inexact
+	default:
+		return Inexact
+	}
+}
+
+// digitsOf returns the number of digits of n. It is 1 for 0.
+//
+func digitsOf(n uint64) int32 {
+	var digits int32 = 1
+
+	for digits < int32(len(g_uint64_pow10)) && n >= g_uint64_pow10[digits] {
+		digits++
+	}
+
+	return digits
+}
+
+// abs32 returns the absolute value of n.
+//
+func abs32(n int32) int32 {
+
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
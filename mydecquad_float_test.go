@@ -0,0 +1,219 @@
+package decnum
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func Test_from_float(t *testing.T) {
+
+	samples := []struct {
+		f               float64
+		mode            FloatMode
+		expected_result string
+		expected_status Status
+	}{
+		{0.1, FloatShortest, "0.1", 0},
+		{0.1, FloatExact, "0.1000000000000000055511151231257827", Inexact},
+		{0.5, FloatExact, "0.5", 0},
+		{-1234.5, FloatShortest, "-1234.5", 0},
+		{1e23, FloatShortest, "1E+23", 0},
+		{1e23, FloatExact, "99999999999999991611392", 0},
+		{math.MaxFloat64, FloatShortest, "1.7976931348623157E+308", 0},
+		{math.SmallestNonzeroFloat64, FloatShortest, "5E-324", 0},
+		{math.SmallestNonzeroFloat64, FloatExact, "4.940656458412465441765687928682214E-324", Inexact},
+		{0, FloatExact, "0", 0},
+		{math.Copysign(0, -1), FloatShortest, "0", 0},
+		{math.Copysign(0, -1), FloatExact, "0", 0},
+		{math.Inf(1), FloatShortest, "Infinity", 0},
+		{math.Inf(-1), FloatExact, "-Infinity", 0},
+		{math.NaN(), FloatExact, "NaN", 0},
+		{1, FloatMode(99), "NaN", InvalidOperation},
+	}
+
+	for i, sp := range samples {
+		r := FromFloat64(sp.f, sp.mode)
+
+		if r.String() != sp.expected_result || r.Status() != sp.expected_status {
+			t.Fatalf("sample %d, FromFloat64(%g, %s): %s %s != %s %s (expected)", i, sp.f, sp.mode, r.String(), r.Status(), sp.expected_result, sp.expected_status)
+		}
+
+		if sp.mode == FloatShortest && r.IsFinite() {
+			if f, err := r.ToFloat64(); err != nil || f != sp.f {
+				t.Fatalf("sample %d, round trip of %g: %g %v", i, sp.f, f, err)
+			}
+		}
+	}
+
+	if r := FromFloat32(0.1, FloatShortest); r.String() != "0.1" || r.Status() != 0 {
+		t.Fatalf("FromFloat32 shortest: %s %s", r, r.Status())
+	}
+
+	if r := FromFloat32(0.1, FloatExact); r.String() != "0.100000001490116119384765625" || r.Status() != 0 {
+		t.Fatalf("FromFloat32 exact: %s %s", r, r.Status())
+	}
+
+	// -0 is printed as 0, so the sign is checked in the bytes
+
+	negzero := must_quad("-0").Bytes()
+
+	if FromFloat64(math.Copysign(0, -1), FloatShortest).Bytes() != negzero || FromFloat64(math.Copysign(0, -1), FloatExact).Bytes() != negzero || FromFloat32(float32(math.Copysign(0, -1)), FloatShortest).Bytes() != negzero {
+		t.Fatalf("-0 should keep its sign")
+	}
+
+	if FromFloat64(0, FloatShortest).Bytes() == negzero {
+		t.Fatalf("0 should be positive")
+	}
+}
+
+func Test_to_float(t *testing.T) {
+
+	samples := []struct {
+		a               string
+		expected_result float64
+		expected_status Status
+	}{
+		{"12345.250", 12345.25, 0},
+		{"-12345.250", -12345.25, 0},
+		{"0.1", 0.1, Inexact},
+		{"1E+23", 1e23, Inexact},
+		{"1E+22", 1e22, 0},
+		{"1267650600228229401496703205376", 0x1p100, 0},
+		{"3.814697265625E-6", 0x1p-18, 0},
+		{"1E+25", 1e25, Inexact},
+		{"123E-30", 123e-30, Inexact},
+		{"9007199254740993", 9007199254740992, Inexact}, // 2^53 + 1, equidistant, round to even
+		{"9007199254740995", 9007199254740996, Inexact},
+		{"1234567890123456789012345678901234", 1234567890123456789012345678901234, Inexact},
+		{"1.7976931348623157E+308", math.MaxFloat64, Inexact},
+		{"1.7976931348623159E+308", math.Inf(1), Overflow | Inexact},
+		{"-1E+6144", math.Inf(-1), Overflow | Inexact},
+		{"2.2250738585072014E-308", 0x1p-1022, Inexact},
+		{"1E-310", 1e-310, Underflow | Inexact},
+		{"5E-324", math.SmallestNonzeroFloat64, Underflow | Inexact},
+		{"2.5E-324", math.SmallestNonzeroFloat64, Underflow | Inexact},
+		{"2E-324", 0, Underflow | Inexact},
+		{"1E-6176", 0, Underflow | Inexact},
+		{"0E-6000", 0, 0},
+		{"-Inf", math.Inf(-1), 0},
+	}
+
+	for i, sp := range samples {
+		f, status := must_quad(sp.a).ToFloat64Status()
+
+		if f != sp.expected_result || status != sp.expected_status {
+			t.Fatalf("sample %d, ToFloat64Status %s: %g %s != %g %s (expected)", i, sp.a, f, status, sp.expected_result, sp.expected_status)
+		}
+	}
+
+	samples32 := []struct {
+		a               string
+		expected_result float32
+		expected_status Status
+	}{
+		{"1.5", 1.5, 0},
+		{"0.1", 0.1, Inexact},
+		{"16777217", 16777216, Inexact},
+		{"1E+10", 1e10, 0},
+		{"1E+12", 1e12, Inexact},
+		{"3.4028235E+38", math.MaxFloat32, Inexact},
+		{"3.5E+38", float32(math.Inf(1)), Overflow | Inexact},
+		{"1E-40", 1e-40, Underflow | Inexact},
+		{"1E-46", 0, Underflow | Inexact},
+	}
+
+	for i, sp := range samples32 {
+		f, status := must_quad(sp.a).ToFloat32Status()
+
+		if f != sp.expected_result || status != sp.expected_status {
+			t.Fatalf("sample %d, ToFloat32Status %s: %g %s != %g %s (expected)", i, sp.a, f, status, sp.expected_result, sp.expected_status)
+		}
+	}
+
+	// -0, NaN and sNaN
+
+	if f, _ := must_quad("-0").ToFloat64Status(); f != 0 || !math.Signbit(f) {
+		t.Fatalf("ToFloat64Status -0: %g", f)
+	}
+
+	if f, _ := must_quad("-0").ToFloat32Status(); f != 0 || math.Float32bits(f) != 1<<31 {
+		t.Fatalf("ToFloat32Status -0: %g", f)
+	}
+
+	if f, status := must_quad("NaN").ToFloat64Status(); !math.IsNaN(f) || math.Float64bits(f)&(1<<51) == 0 || status != 0 {
+		t.Fatalf("ToFloat64Status NaN: %x %s", math.Float64bits(f), status)
+	}
+
+	if f, status := must_quad("sNaN").ToFloat64Status(); !math.IsNaN(f) || math.Float64bits(f)&(1<<51) != 0 || status != 0 {
+		t.Fatalf("ToFloat64Status sNaN should be signaling: %x %s", math.Float64bits(f), status)
+	}
+
+	if f, _ := must_quad("-sNaN").ToFloat32Status(); math.Float32bits(f) != 0xFFA00000 {
+		t.Fatalf("ToFloat32Status -sNaN: %x", math.Float32bits(f))
+	}
+
+	if f, err := must_quad("3.5E+38").ToFloat32(); err == nil || !math.IsNaN(float64(f)) {
+		t.Fatalf("ToFloat32 overflow: %g %v", f, err)
+	}
+
+	// comparison with strconv.ParseFloat, which is correctly rounded
+
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		coeff := strconv.FormatUint(rnd.Uint64()>>uint(rnd.Intn(64)), 10)
+		if rnd.Intn(2) == 0 {
+			coeff += strconv.FormatUint(rnd.Uint64(), 10)[:rnd.Intn(15)+1]
+		}
+
+		s := coeff + "E" + strconv.Itoa(rnd.Intn(760)-380)
+		a := must_quad(s)
+
+		expected64, _ := strconv.ParseFloat(s, 64)
+		expected32, _ := strconv.ParseFloat(s, 32)
+
+		f64, status := a.ToFloat64Status()
+		f32, _ := a.ToFloat32Status()
+
+		if f64 != expected64 || f32 != float32(expected32) {
+			t.Fatalf("%s: %g %g != %g %g (expected)", s, f64, f32, expected64, expected32)
+		}
+
+		if back := FromFloat64(f64, FloatExact); !math.IsInf(f64, 0) && (status&Inexact == 0) != (back.Status() == 0 && back.Equal(a)) {
+			t.Fatalf("%s: status %s, but exact value of %g is %s", s, status, f64, back)
+		}
+	}
+}
+
+// toFloat64String is the former implementation of ToFloat64, used by the benchmark.
+//
+func toFloat64String(a Quad) (float64, error) {
+
+	if a.IsNaN() {
+		return math.NaN(), nil
+	}
+
+	return strconv.ParseFloat(a.String(), 64)
+}
+
+var benchFloatSamples = []Quad{must_quad("12345.25"), must_quad("-0.1"), must_quad("1234567.891"), must_quad("1E+100"), must_quad("1.234567890123456789012345678901234E-20")}
+
+func Benchmark_ToFloat64(b *testing.B) {
+
+	for i := 0; i < b.N; i++ {
+		for _, a := range benchFloatSamples {
+			a.ToFloat64()
+		}
+	}
+}
+
+func Benchmark_ToFloat64_string(b *testing.B) {
+
+	for i := 0; i < b.N; i++ {
+		for _, a := range benchFloatSamples {
+			toFloat64String(a)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+import (
+	"math/big"
+	"strings"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                              fractions                               */
+/*                                                                      */
+/************************************************************************/
+
+var (
+	g_big_one = big.NewInt(1)
+	g_big_ten = big.NewInt(10)
+)
+
+// toBigFraction returns the exact value of a as num/den, den being a power of 10.
+// a must be finite.
+//
+//      -12.5      gives   -125, 10
+//      12E+3      gives   12000, 1
+//
+func toBigFraction(a Quad) (num *big.Int, den *big.Int) {
+	var (
+		bcd      [DecquadPmax]byte
+		exp      int32
+		negative bool
+		digits   [DecquadPmax]byte
+	)
+
+	bcd, exp, negative, _ = a.ToBCD()
+
+	for i, d := range bcd {
+		digits[i] = '0' + d
+	}
+
+	num, _ = new(big.Int).SetString(string(digits[:]), 10)
+	den = big.NewInt(1)
+
+	if exp >= 0 {
+		num.Mul(num, new(big.Int).Exp(g_big_ten, big.NewInt(int64(exp)), nil))
+	} else {
+		den.Exp(g_big_ten, big.NewInt(int64(-exp)), nil)
+	}
+
+	if negative {
+		num.Neg(num)
+	}
+
+	return num, den
+}
+
+// toBigInteger returns the value of a as a big.Int, and true if a is an integral value.
+// Trailing zeros after the decimal point are accepted, as in 10.00.
+//
+func toBigInteger(a Quad) (n *big.Int, ok bool) {
+
+	num, den := toBigFraction(a)
+
+	if den.Cmp(g_big_one) != 0 {
+		r := new(big.Int)
+		if num.QuoRem(num, den, r); r.Sign() != 0 {
+			return nil, false
+		}
+	}
+
+	return num, true
+}
+
+// fromBigInt returns the Quad value of n.
+// If n has more than 34 digits, it is rounded with RoundHalfEven mode, and the Inexact flag is set.
+//
+func fromBigInt(n *big.Int) Quad {
+
+	r, _ := FromString(n.String()) // a big.Int string is always a valid number
+
+	return r
+}
+
+// ToFraction returns the best rational approximation num/den of a, with 1 <= den <= maxDenominator.
+//
+// It is computed with the continued fraction expansion of the exact value of a.
+// If a has a denominator not larger than maxDenominator, the exact fraction is returned, reduced to lowest terms.
+// Else, the result is the fraction closest to a among all fractions with den <= maxDenominator.
+//
+// The sign of the fraction is the sign of num. den is always positive.
+//
+//      a 0.75,                                   maxDenominator 100      gives   3, 4
+//      a 3.14159265358979,                       maxDenominator 1000     gives   355, 113
+//      a 0.3333333333333333333333333333333333,   maxDenominator 100      gives   1, 3
+//
+// maxDenominator must be an integral value >= 1.
+// If a is Infinite or NaN, or if maxDenominator is not valid, an error is returned.
+//
+// If num has more than 34 digits, which is possible for very large a, it is rounded and the Inexact flag is set in its status.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToFraction(maxDenominator Quad) (num Quad, den Quad, err error) {
+	var (
+		n, d   *big.Int
+		maxDen *big.Int
+		one    = big.NewInt(1)
+	)
+
+	if !a.IsFinite() || !maxDenominator.IsFinite() {
+		return g_nan, g_nan, newError(InvalidOperation)
+	}
+
+	maxDen, ok := toBigInteger(maxDenominator)
+	if !ok || maxDen.Cmp(one) < 0 { // maxDenominator must be an integer >= 1
+		return g_nan, g_nan, newError(InvalidOperation)
+	}
+
+	n, d = toBigFraction(a)
+
+	negative := n.Sign() < 0
+	n.Abs(n)
+
+	g := new(big.Int).GCD(nil, nil, n, d)
+	if g.Sign() != 0 {
+		n.Quo(n, g)
+		d.Quo(d, g)
+	}
+
+	if d.Cmp(maxDen) > 0 { // exact fraction has a too large denominator, compute the best approximation
+		var (
+			p0, q0 = big.NewInt(0), big.NewInt(1)
+			p1, q1 = big.NewInt(1), big.NewInt(0)
+			nn, dd = new(big.Int).Set(n), new(big.Int).Set(d)
+			q, r   = new(big.Int), new(big.Int)
+			q2     = new(big.Int)
+			tmp    = new(big.Int)
+		)
+
+		for {
+			q.QuoRem(nn, dd, r)
+
+			q2.Mul(q, q1).Add(q2, q0) // q2 = q0 + q*q1
+			if q2.Cmp(maxDen) > 0 {
+				break
+			}
+
+			tmp.Mul(q, p1).Add(tmp, p0) // p2 = p0 + q*p1
+			p0, p1 = p1, new(big.Int).Set(tmp)
+			q0, q1 = q1, new(big.Int).Set(q2)
+			nn, dd = dd, new(big.Int).Set(r)
+		}
+
+		// semiconvergent (p0+k*p1)/(q0+k*q1), with the largest k such that q0+k*q1 <= maxDen.
+
+		k := new(big.Int).Sub(maxDen, q0)
+		k.Quo(k, q1)
+
+		sq := new(big.Int).Mul(k, q1)
+		sq.Add(sq, q0)
+
+		// the convergent p1/q1 is better if |n/d - p1/q1| <= |n/d - semiconvergent|, that is if 2*dd*sq <= d
+
+		tmp.Mul(dd, sq).Lsh(tmp, 1)
+		if tmp.Cmp(d) <= 0 {
+			n, d = p1, q1
+		} else {
+			sp := new(big.Int).Mul(k, p1)
+			sp.Add(sp, p0)
+			n, d = sp, sq
+		}
+	}
+
+	if negative {
+		n.Neg(n)
+	}
+
+	return fromBigInt(n), fromBigInt(d), nil
+}
+
+// FromFraction returns num/den, rounded to 34 digits with the specified rounding mode.
+//
+// The Inexact flag is set in the status of the result if rounding occurred.
+// If den is 0, the DivisionByZero flag is set, as for num.Div(den).
+//
+// The status of the result contains the status of num and den.
+//
+//      FromFraction(1, 3, RoundHalfEven)     gives   0.3333333333333333333333333333333333
+//      FromFraction(2, 3, RoundDown)         gives   0.6666666666666666666666666666666666
+//      FromFraction(2, 3, RoundUp)           gives   0.6666666666666666666666666666666667
+//
+func FromFraction(num Quad, den Quad, rounding RoundingMode) Quad {
+
+	return Quad(C.mdq_divide_mode(C.struct_Quad(num), C.struct_Quad(den), C.int(rounding)))
+}
+
+// RepeatingDecimal returns the exact decimal representation of num/den, num and den being integral values.
+// The repeating part of the fractional digits, if any, is written in parentheses.
+//
+//      RepeatingDecimal(1, 7, 100)       gives   "0.(142857)"
+//      RepeatingDecimal(1, 6, 100)       gives   "0.1(6)"
+//      RepeatingDecimal(-22, 7, 100)     gives   "-3.(142857)"
+//      RepeatingDecimal(3, 8, 100)       gives   "0.375"
+//      RepeatingDecimal(1, 97, 20)       gives   "0.01030927835051546391..."
+//
+// The number of fractional digits, including the repeating part, is limited to maxDigits.
+// If the representation needs more digits, the first maxDigits fractional digits are returned, followed by "...".
+//
+// If num or den is not an integral value, or if den is 0, an error is returned.
+//
+// To find the repeating decimal representation of a Quad which is the rounded result of a division, first call ToFraction to recover num and den.
+//
+// The status fields of num and den are not checked.
+//
+func RepeatingDecimal(num Quad, den Quad, maxDigits int) (string, error) {
+	var (
+		n, d     *big.Int
+		one      = big.NewInt(1)
+		two      = big.NewInt(2)
+		five     = big.NewInt(5)
+		r        = new(big.Int)
+		tmp      = new(big.Int)
+		pre      int  // number of non-repeating fractional digits
+		period   int  // number of repeating fractional digits
+		complete bool // true if pre+period <= maxDigits
+		buff     strings.Builder
+	)
+
+	if !num.IsFinite() || !den.IsFinite() || den.IsZero() {
+		return "", newError(InvalidOperation)
+	}
+
+	if maxDigits < 0 {
+		maxDigits = 0
+	}
+
+	n, okNum := toBigInteger(num)
+	d, okDen := toBigInteger(den)
+	if !okNum || !okDen {
+		return "", newError(InvalidOperation)
+	}
+
+	if d.Sign() < 0 {
+		n.Neg(n)
+		d.Neg(d)
+	}
+
+	if n.Sign() < 0 {
+		buff.WriteByte('-')
+		n.Abs(n)
+	}
+
+	g := new(big.Int).GCD(nil, nil, n, d)
+	if g.Sign() != 0 {
+		n.Quo(n, g)
+		d.Quo(d, g)
+	}
+
+	// the length of the non-repeating part is the max of the exponents of 2 and 5 in d.
+	// the length of the repeating part is the order of 10 modulo d without its factors 2 and 5.
+
+	rest := new(big.Int).Set(d)
+	count2, count5 := 0, 0
+	for tmp.Rem(rest, two).Sign() == 0 {
+		rest.Quo(rest, two)
+		count2++
+	}
+	for tmp.Rem(rest, five).Sign() == 0 {
+		rest.Quo(rest, five)
+		count5++
+	}
+
+	pre = count2
+	if count5 > pre {
+		pre = count5
+	}
+
+	complete = pre <= maxDigits
+
+	if complete && rest.Cmp(one) != 0 {
+		r.Rem(g_big_ten, rest)
+		period = 1
+		for r.Cmp(one) != 0 && pre+period <= maxDigits {
+			r.Mul(r, g_big_ten).Rem(r, rest)
+			period++
+		}
+		complete = pre+period <= maxDigits
+	}
+
+	// long division
+
+	q := new(big.Int)
+	q.QuoRem(n, d, r)
+	buff.WriteString(q.String())
+
+	fracDigits := pre + period
+	if !complete {
+		fracDigits = maxDigits
+	}
+
+	if fracDigits == 0 {
+		if !complete {
+			buff.WriteString("...")
+		}
+		return buff.String(), nil
+	}
+
+	buff.WriteByte('.')
+
+	for i := 0; i < fracDigits; i++ {
+		if complete && period != 0 && i == pre {
+			buff.WriteByte('(')
+		}
+		r.Mul(r, g_big_ten)
+		q.QuoRem(r, d, r)
+		buff.WriteByte(byte('0' + q.Int64()))
+	}
+
+	if !complete {
+		buff.WriteString("...")
+	} else if period != 0 {
+		buff.WriteByte(')')
+	}
+
+	return buff.String(), nil
+}
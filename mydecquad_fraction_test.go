@@ -0,0 +1,107 @@
+package decnum
+
+import (
+	"testing"
+)
+
+func Test_fraction(t *testing.T) {
+
+	samples := []struct {
+		a            string
+		maxDen       string
+		expected_num string
+		expected_den string
+	}{
+		{"0.75", "100", "3", "4"},
+		{"3.1416", "1000", "2862", "911"},
+		{"3.14159265358979", "1000", "355", "113"},
+		{"0.3333333333333333333333333333333333", "100", "1", "3"},
+		{"0.1428571428571428571428571428571429", "10000000000", "1", "7"},
+		{"-2.718281828459045235360287471352662", "1000", "-1457", "536"},
+		{"1.414213562373095048801688724209698", "1000000", "941664", "665857"},
+		{"1E-40", "1000000", "0", "1"},
+		{"0.5", "1", "0", "1"},
+		{"0.51", "1", "1", "1"},
+		{"-12.50", "100", "-25", "2"},
+		{"12E+3", "5", "12000", "1"},
+		{"2.5", "10.0", "5", "2"},
+		{"0.75", "1.00E+2", "3", "4"},
+	}
+
+	for i, sp := range samples {
+		num, den, err := must_quad(sp.a).ToFraction(must_quad(sp.maxDen))
+		if err != nil {
+			t.Fatalf("sample %d, ToFraction <%s>: %s", i, sp.a, err)
+		}
+
+		if num.String() != sp.expected_num || den.String() != sp.expected_den {
+			t.Fatalf("sample %d, ToFraction <%s>: %s/%s != %s/%s (expected)", i, sp.a, num, den, sp.expected_num, sp.expected_den)
+		}
+	}
+
+	for _, sp := range [][2]string{{"Inf", "10"}, {"NaN", "10"}, {"1.5", "0"}, {"1.5", "2.5"}, {"1.5", "Inf"}, {"1.5", "2.50"}} {
+		if _, _, err := must_quad(sp[0]).ToFraction(must_quad(sp[1])); err == nil {
+			t.Fatalf("ToFraction <%s> <%s>: error expected", sp[0], sp[1])
+		}
+	}
+
+	frac_samples := []struct {
+		num             string
+		den             string
+		rounding        RoundingMode
+		expected_result string
+		expected_status Status
+	}{
+		{"1", "4", RoundHalfEven, "0.25", 0},
+		{"1", "3", RoundHalfEven, "0.3333333333333333333333333333333333", Inexact},
+		{"2", "3", RoundDown, "0.6666666666666666666666666666666666", Inexact},
+		{"2", "3", RoundUp, "0.6666666666666666666666666666666667", Inexact},
+		{"-2", "3", RoundFloor, "-0.6666666666666666666666666666666667", Inexact},
+		{"-2", "3", RoundCeiling, "-0.6666666666666666666666666666666666", Inexact},
+		{"1", "0", RoundHalfEven, "Infinity", DivisionByZero},
+	}
+
+	for i, sp := range frac_samples {
+		r := FromFraction(must_quad(sp.num), must_quad(sp.den), sp.rounding)
+
+		if r.String() != sp.expected_result || r.Status()&(ErrorMask|Inexact) != sp.expected_status {
+			t.Fatalf("sample %d, FromFraction %s/%s: %s %s != %s %s (expected)", i, sp.num, sp.den, r, r.Status(), sp.expected_result, sp.expected_status)
+		}
+	}
+
+	rep_samples := []struct {
+		num       string
+		den       string
+		maxDigits int
+		expected  string
+	}{
+		{"1", "7", 100, "0.(142857)"},
+		{"1", "6", 100, "0.1(6)"},
+		{"-22", "7", 100, "-3.(142857)"},
+		{"22", "-7", 100, "-3.(142857)"},
+		{"3", "8", 100, "0.375"},
+		{"10", "5", 100, "2"},
+		{"0", "3", 100, "0"},
+		{"1", "3", 1, "0.(3)"},
+		{"1", "3", 0, "0..."},
+		{"1", "12", 2, "0.08..."},
+		{"1", "12", 3, "0.08(3)"},
+		{"1", "97", 20, "0.01030927835051546391..."},
+		{"5", "1", 0, "5"},
+		{"1.0", "7", 20, "0.(142857)"},
+		{"10.00", "4.0", 100, "2.5"},
+	}
+
+	for i, sp := range rep_samples {
+		s, err := RepeatingDecimal(must_quad(sp.num), must_quad(sp.den), sp.maxDigits)
+		if err != nil || s != sp.expected {
+			t.Fatalf("sample %d, RepeatingDecimal %s/%s: %s %v != %s (expected)", i, sp.num, sp.den, s, err, sp.expected)
+		}
+	}
+
+	for _, sp := range [][2]string{{"1", "0"}, {"1.5", "3"}, {"1.50", "3"}, {"0.0", "0.00"}, {"1", "Inf"}, {"NaN", "3"}} {
+		if _, err := RepeatingDecimal(must_quad(sp[0]), must_quad(sp[1]), 100); err == nil {
+			t.Fatalf("RepeatingDecimal <%s> <%s>: error expected", sp[0], sp[1])
+		}
+	}
+}
@@ -0,0 +1,83 @@
+#include "mydecquad.h"
+
+
+/************************************************************************/
+/*                   square root with rounding mode                     */
+/************************************************************************/
+
+/* decNumberSquareRoot() always rounds with DEC_ROUND_HALF_EVEN, whatever the rounding mode of the context.
+
+   To get a square root rounded with any rounding mode, it is computed with MDQ_SQRT_DIGITS digits, that is 2 digits more than decQuad.
+   If this result s is inexact, the exact root t is in (s - u/2, s + u/2), u being the unit in the last place of s.
+   s is moved by u/2 towards t, by comparing s*s with the argument. The moved value is never a rounding boundary at 34 digits,
+   and is between the same rounding boundaries as t, so rounding it to 34 digits gives the correctly rounded t.
+*/
+#define MDQ_SQRT_DIGITS  (DECQUAD_Pmax + 2)
+
+
+/* decNumber large enough for the square of a MDQ_SQRT_DIGITS digits number.
+*/
+typedef struct {
+  int32_t         digits;
+  int32_t         exponent;
+  uint8_t         bits;
+  decNumberUnit   lsu[(2*MDQ_SQRT_DIGITS+DECDPUN-1)/DECDPUN];
+} Sqrt_number;
+
+
+#define SQRTDN(x)  ((decNumber *)&(x))   // Sqrt_number is used as decNumber
+
+
+/* square root, with rounding mode.
+*/
+Quad mdq_sqrt_mode(Quad a, int round) {
+  decContext   set;
+  decContext   work;
+  Quad         res;
+  Sqrt_number  x;
+  Sqrt_number  s;
+  Sqrt_number  square;
+  Sqrt_number  half_unit;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+  decContextSetRounding(&set, round);      // change rounding mode
+  set.status = a.status;
+
+  decContextDefault(&work, DEC_INIT_BASE);
+  work.traps  = 0;                         // DEC_INIT_BASE sets traps, that would raise SIGFPE
+  work.round  = DEC_ROUND_HALF_EVEN;
+  work.digits = MDQ_SQRT_DIGITS;
+  work.emax   = DEC_MAX_EMAX;
+  work.emin   = DEC_MIN_EMIN;
+
+  decQuadToNumber(&a.val, SQRTDN(x));
+
+  decNumberSquareRoot(SQRTDN(s), SQRTDN(x), &work);   // NaN, Infinite and negative arguments are processed by decNumber
+
+  if ( (work.status & DEC_Inexact) && decNumberIsFinite(SQRTDN(s)) ) {
+      work.digits = 2*MDQ_SQRT_DIGITS;                  // exact square
+      decNumberMultiply(SQRTDN(square), SQRTDN(s), SQRTDN(s), &work);
+
+      decNumberFromInt32(SQRTDN(half_unit), 5);
+      SQRTDN(half_unit)->exponent = SQRTDN(s)->exponent + SQRTDN(s)->digits - MDQ_SQRT_DIGITS - 1;
+
+      decNumberCompare(SQRTDN(square), SQRTDN(square), SQRTDN(x), &work);  // -1 if s*s < x, 1 if s*s > x. It cannot be 0, as s is inexact.
+
+      if ( decNumberIsNegative(SQRTDN(square)) ) {
+          decNumberAdd(SQRTDN(s), SQRTDN(s), SQRTDN(half_unit), &work);        // s*s < x, so t > s
+      } else {
+          decNumberSubtract(SQRTDN(s), SQRTDN(s), SQRTDN(half_unit), &work);   // s*s > x, so t < s
+      }
+  }
+
+  decQuadFromNumber(&res.val, SQRTDN(s), &set);
+
+  set.status |= work.status & DEC_Errors;
+  if ( work.status & DEC_Inexact ) {
+      set.status |= DEC_Inexact;
+  }
+
+  res.status = decContextGetStatus(&set) & ~(DEC_Rounded | DEC_Subnormal | DEC_Clamped);   // decQuadFromNumber sets these flags, which are not used by this package
+
+  return res;
+}
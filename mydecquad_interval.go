@@ -0,0 +1,358 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+/************************************************************************/
+/*                                                                      */
+/*                          interval arithmetic                         */
+/*                                                                      */
+/************************************************************************/
+
+// QuadInterval is a closed interval [Lo, Hi] of Quad values.
+//
+// The operations on intervals round Lo with RoundFloor and Hi with RoundCeiling, so that the exact result of the operation
+// on any values taken in the operand intervals is always inside the result interval.
+//
+// An interval with Lo > Hi is empty. The result of an operation on an empty interval is empty.
+// An interval with a NaN bound is a NaN interval. The result of an operation on a NaN interval is a NaN interval.
+// NaN takes precedence over empty.
+//
+// As for Quad operations, the status fields of the operands propagate into the bounds of the result.
+//
+type QuadInterval struct {
+	Lo Quad
+	Hi Quad
+}
+
+var (
+	g_interval_zero = MustParse("0")   // 0 with exponent 0, displayed as "0". g_zero is displayed as "0E-6176".
+	g_interval_half = MustParse("0.5") // used by Midpoint
+)
+
+// NewInterval returns the interval [lo, hi].
+//
+func NewInterval(lo Quad, hi Quad) QuadInterval {
+
+	return QuadInterval{Lo: lo, Hi: hi}
+}
+
+// PointInterval returns the interval [a, a].
+//
+func PointInterval(a Quad) QuadInterval {
+
+	return QuadInterval{Lo: a, Hi: a}
+}
+
+// EmptyInterval returns an empty interval, [+Inf, -Inf].
+//
+func EmptyInterval() QuadInterval {
+
+	return QuadInterval{Lo: g_pos_inf, Hi: g_neg_inf}
+}
+
+// nanInterval returns [NaN, NaN], with the status flags of the arguments.
+//
+func nanInterval(status Status) QuadInterval {
+
+	r := g_nan.SetStatusFlags(status)
+
+	return QuadInterval{Lo: r, Hi: r}
+}
+
+// IsNaN returns true if Lo or Hi is NaN.
+//
+func (x QuadInterval) IsNaN() bool {
+
+	return x.Lo.IsNaN() || x.Hi.IsNaN()
+}
+
+// IsEmpty returns true if the interval contains no value, that is if Lo > Hi.
+// A NaN interval is not empty.
+//
+func (x QuadInterval) IsEmpty() bool {
+
+	return x.Lo.Greater(x.Hi)
+}
+
+// Error returns an error if an error flag bit has been set in the status field of Lo or Hi.
+//
+func (x QuadInterval) Error() error {
+
+	return x.Lo.SetStatusFlags(x.Hi.Status()).Error()
+}
+
+// String returns the string representation of the interval, e.g. "[1.41, 1.42]".
+//
+func (x QuadInterval) String() string {
+
+	return "[" + x.Lo.String() + ", " + x.Hi.String() + "]"
+}
+
+// special returns the result for NaN or empty operands, and true if one operand is NaN or empty.
+//
+func special(x QuadInterval, y QuadInterval) (QuadInterval, bool) {
+
+	if x.IsNaN() || y.IsNaN() {
+		return nanInterval(x.Lo.Status() | x.Hi.Status() | y.Lo.Status() | y.Hi.Status()), true
+	}
+
+	if x.IsEmpty() || y.IsEmpty() {
+		return EmptyInterval(), true
+	}
+
+	return QuadInterval{}, false
+}
+
+// mulBound returns a*b rounded with the specified rounding mode.
+// 0 * ±Inf is 0, as an infinite bound means that the interval is unbounded.
+//
+func mulBound(a Quad, b Quad, rounding RoundingMode) Quad {
+
+	if a.IsZero() || b.IsZero() {
+		return g_interval_zero.SetStatusFlags(a.Status() | b.Status())
+	}
+
+	return Quad(C.mdq_multiply_mode(C.struct_Quad(a), C.struct_Quad(b), C.int(rounding)))
+}
+
+// divBound returns a/b rounded with the specified rounding mode.
+// ±Inf / ±Inf can be any value of the sign of the quotient, so it gives the widest bound: 0 or ±Inf.
+//
+func divBound(a Quad, b Quad, rounding RoundingMode) Quad {
+
+	if a.IsInfinite() && b.IsInfinite() {
+		status := a.Status() | b.Status()
+		negative := a.IsNegative() != b.IsNegative()
+
+		switch {
+		case rounding == RoundFloor && negative:
+			return g_neg_inf.SetStatusFlags(status)
+		case rounding == RoundCeiling && !negative:
+			return g_pos_inf.SetStatusFlags(status)
+		default:
+			return g_interval_zero.SetStatusFlags(status)
+		}
+	}
+
+	return Quad(C.mdq_divide_mode(C.struct_Quad(a), C.struct_Quad(b), C.int(rounding)))
+}
+
+// bounds returns the interval [min(lo...), max(hi...)].
+//
+func bounds(lo [4]Quad, hi [4]Quad) QuadInterval {
+
+	r := QuadInterval{Lo: lo[0], Hi: hi[0]}
+
+	for i := 1; i < 4; i++ {
+		r.Lo = Min(r.Lo, lo[i]).SetStatusFlags(r.Lo.Status() | lo[i].Status())
+		r.Hi = Max(r.Hi, hi[i]).SetStatusFlags(r.Hi.Status() | hi[i].Status())
+	}
+
+	return r
+}
+
+// Neg returns -x, that is [-x.Hi, -x.Lo].
+//
+func (x QuadInterval) Neg() QuadInterval {
+
+	if x.IsNaN() || x.IsEmpty() {
+		r, _ := special(x, x)
+		return r
+	}
+
+	return QuadInterval{Lo: x.Hi.Neg(), Hi: x.Lo.Neg()}
+}
+
+// Add returns x + y, that is [x.Lo + y.Lo, x.Hi + y.Hi].
+//
+func (x QuadInterval) Add(y QuadInterval) QuadInterval {
+
+	if r, ok := special(x, y); ok {
+		return r
+	}
+
+	return QuadInterval{
+		Lo: Quad(C.mdq_add_mode(C.struct_Quad(x.Lo), C.struct_Quad(y.Lo), C.int(RoundFloor))),
+		Hi: Quad(C.mdq_add_mode(C.struct_Quad(x.Hi), C.struct_Quad(y.Hi), C.int(RoundCeiling))),
+	}
+}
+
+// Sub returns x - y, that is [x.Lo - y.Hi, x.Hi - y.Lo].
+//
+func (x QuadInterval) Sub(y QuadInterval) QuadInterval {
+
+	if r, ok := special(x, y); ok {
+		return r
+	}
+
+	return QuadInterval{
+		Lo: Quad(C.mdq_subtract_mode(C.struct_Quad(x.Lo), C.struct_Quad(y.Hi), C.int(RoundFloor))),
+		Hi: Quad(C.mdq_subtract_mode(C.struct_Quad(x.Hi), C.struct_Quad(y.Lo), C.int(RoundCeiling))),
+	}
+}
+
+// Mul returns x * y.
+//
+func (x QuadInterval) Mul(y QuadInterval) QuadInterval {
+
+	if r, ok := special(x, y); ok {
+		return r
+	}
+
+	return bounds(
+		[4]Quad{mulBound(x.Lo, y.Lo, RoundFloor), mulBound(x.Lo, y.Hi, RoundFloor), mulBound(x.Hi, y.Lo, RoundFloor), mulBound(x.Hi, y.Hi, RoundFloor)},
+		[4]Quad{mulBound(x.Lo, y.Lo, RoundCeiling), mulBound(x.Lo, y.Hi, RoundCeiling), mulBound(x.Hi, y.Lo, RoundCeiling), mulBound(x.Hi, y.Hi, RoundCeiling)},
+	)
+}
+
+// Div returns x / y.
+//
+// If y contains 0, the result is a NaN interval, and the DivisionByZero flag is set.
+//
+func (x QuadInterval) Div(y QuadInterval) QuadInterval {
+
+	if r, ok := special(x, y); ok {
+		return r
+	}
+
+	if y.Contains(g_zero) {
+		return nanInterval(x.Lo.Status() | x.Hi.Status() | y.Lo.Status() | y.Hi.Status() | DivisionByZero)
+	}
+
+	return bounds(
+		[4]Quad{divBound(x.Lo, y.Lo, RoundFloor), divBound(x.Lo, y.Hi, RoundFloor), divBound(x.Hi, y.Lo, RoundFloor), divBound(x.Hi, y.Hi, RoundFloor)},
+		[4]Quad{divBound(x.Lo, y.Lo, RoundCeiling), divBound(x.Lo, y.Hi, RoundCeiling), divBound(x.Hi, y.Lo, RoundCeiling), divBound(x.Hi, y.Hi, RoundCeiling)},
+	)
+}
+
+// Sqrt returns the square root of x.
+//
+// The negative part of x is ignored, e.g. Sqrt([-4, 9]) is [0, 3].
+// If x is entirely negative, the result is a NaN interval, and the InvalidOperation flag is set.
+//
+func (x QuadInterval) Sqrt() QuadInterval {
+
+	if x.IsNaN() || x.IsEmpty() {
+		r, _ := special(x, x)
+		return r
+	}
+
+	if x.Hi.IsNegative() {
+		return nanInterval(x.Lo.Status() | x.Hi.Status() | InvalidOperation)
+	}
+
+	lo := x.Lo
+	if lo.IsNegative() {
+		lo = g_interval_zero.SetStatusFlags(lo.Status())
+	}
+
+	return QuadInterval{
+		Lo: Quad(C.mdq_sqrt_mode(C.struct_Quad(lo), C.int(RoundFloor))),
+		Hi: Quad(C.mdq_sqrt_mode(C.struct_Quad(x.Hi), C.int(RoundCeiling))),
+	}
+}
+
+// Width returns Hi - Lo, rounded with RoundCeiling.
+//
+// The width of an empty interval is 0. The width of a NaN interval is NaN.
+//
+func (x QuadInterval) Width() Quad {
+
+	if x.IsNaN() {
+		return g_nan.SetStatusFlags(x.Lo.Status() | x.Hi.Status())
+	}
+
+	if x.IsEmpty() {
+		return g_interval_zero
+	}
+
+	return Quad(C.mdq_subtract_mode(C.struct_Quad(x.Hi), C.struct_Quad(x.Lo), C.int(RoundCeiling)))
+}
+
+// Midpoint returns (Lo + Hi)/2, rounded with RoundHalfEven.
+//
+// The result is always inside the interval. The midpoint of an empty or NaN interval is NaN.
+//
+func (x QuadInterval) Midpoint() Quad {
+	if x.IsNaN() || x.IsEmpty() {
+		return g_nan.SetStatusFlags(x.Lo.Status() | x.Hi.Status())
+	}
+
+	if x.Lo.IsInfinite() || x.Hi.IsInfinite() {
+		return x.Lo.Add(x.Hi) // -Inf + Inf is NaN
+	}
+
+	m := x.Lo.Mul(g_interval_half).Add(x.Hi.Mul(g_interval_half)) // no overflow, even for MaxQuad
+
+	return Min(Max(m, x.Lo), x.Hi) // Lo/2 may be inexact for subnormal numbers
+}
+
+// Contains returns true if Lo <= a <= Hi.
+//
+// It returns false if the interval is empty or NaN, or if a is NaN.
+//
+func (x QuadInterval) Contains(a Quad) bool {
+
+	return x.Lo.LessEqual(a) && a.LessEqual(x.Hi)
+}
+
+// Less returns true if all values of x are less than all values of y, that is if x.Hi < y.Lo.
+//
+// It returns false if x or y is empty or NaN.
+//
+func (x QuadInterval) Less(y QuadInterval) bool {
+
+	if x.IsEmpty() || y.IsEmpty() {
+		return false
+	}
+
+	return x.Hi.Less(y.Lo)
+}
+
+// LessEqual returns true if all values of x are less than or equal to all values of y, that is if x.Hi <= y.Lo.
+//
+// It returns false if x or y is empty or NaN.
+//
+func (x QuadInterval) LessEqual(y QuadInterval) bool {
+
+	if x.IsEmpty() || y.IsEmpty() {
+		return false
+	}
+
+	return x.Hi.LessEqual(y.Lo)
+}
+
+// Greater returns true if all values of x are greater than all values of y, that is if x.Lo > y.Hi.
+//
+// It returns false if x or y is empty or NaN.
+//
+func (x QuadInterval) Greater(y QuadInterval) bool {
+
+	return y.Less(x)
+}
+
+// GreaterEqual returns true if all values of x are greater than or equal to all values of y, that is if x.Lo >= y.Hi.
+//
+// It returns false if x or y is empty or NaN.
+//
+func (x QuadInterval) GreaterEqual(y QuadInterval) bool {
+
+	return y.LessEqual(x)
+}
+
+// Overlaps returns true if x and y have at least one value in common.
+//
+// It returns false if x or y is empty or NaN.
+//
+func (x QuadInterval) Overlaps(y QuadInterval) bool {
+
+	if x.IsEmpty() || y.IsEmpty() {
+		return false
+	}
+
+	return x.Lo.LessEqual(y.Hi) && y.Lo.LessEqual(x.Hi)
+}
@@ -0,0 +1,168 @@
+package decnum
+
+import (
+	"testing"
+)
+
+// converts two strings to QuadInterval or aborts.
+//
+func must_interval(lo string, hi string) QuadInterval {
+
+	return NewInterval(must_quad(lo), must_quad(hi))
+}
+
+func Test_interval_operations(t *testing.T) {
+
+	samples := []struct {
+		operation   string
+		a           QuadInterval
+		b           QuadInterval
+		expected_lo string
+		expected_hi string
+	}{
+		{"add", must_interval("1", "2"), must_interval("3", "4"), "4", "6"},
+		{"add", must_interval("1E+40", "1E+40"), must_interval("0.1", "0.1"), "1.000000000000000000000000000000000E+40", "1.000000000000000000000000000000001E+40"},
+		{"add", must_interval("-1E+40", "-1E+40"), must_interval("-0.1", "-0.1"), "-1.000000000000000000000000000000001E+40", "-1.000000000000000000000000000000000E+40"},
+		{"sub", must_interval("1", "2"), must_interval("3", "5"), "-4", "-1"},
+		{"sub", must_interval("1E+40", "1E+40"), must_interval("0.1", "0.1"), "9.999999999999999999999999999999999E+39", "1.000000000000000000000000000000000E+40"},
+		{"mul", must_interval("1", "2"), must_interval("3", "4"), "3", "8"},
+		{"mul", must_interval("-1", "2"), must_interval("-3", "4"), "-6", "8"},
+		{"mul", must_interval("-2", "-1"), must_interval("3", "4"), "-8", "-3"},
+		{"mul", must_interval("0", "1"), must_interval("1", "Inf"), "0", "Infinity"},
+		{"mul", must_interval("1.000000000000000000000000000000001", "1.000000000000000000000000000000001"), must_interval("1.000000000000000000000000000000001", "1.000000000000000000000000000000001"), "1.000000000000000000000000000000002", "1.000000000000000000000000000000003"},
+		{"div", must_interval("1", "1"), must_interval("3", "3"), "0.3333333333333333333333333333333333", "0.3333333333333333333333333333333334"},
+		{"div", must_interval("-1", "-1"), must_interval("3", "3"), "-0.3333333333333333333333333333333334", "-0.3333333333333333333333333333333333"},
+		{"div", must_interval("1", "2"), must_interval("-4", "-1"), "-2", "-0.25"},
+		{"div", must_interval("1", "Inf"), must_interval("1", "Inf"), "0E-6176", "Infinity"}, // 1/Inf is 0E-6176
+		{"div", must_interval("1", "2"), must_interval("-1", "1"), "NaN", "NaN"},
+		{"sqrt", must_interval("2", "2"), QuadInterval{}, "1.414213562373095048801688724209698", "1.414213562373095048801688724209699"},
+		{"sqrt", must_interval("0.3", "4"), QuadInterval{}, "0.5477225575051661134569697828008021", "2"},
+		{"sqrt", must_interval("-4", "9"), QuadInterval{}, "0", "3"},
+		{"sqrt", must_interval("-9", "-4"), QuadInterval{}, "NaN", "NaN"},
+		{"sqrt", must_interval("0", "Inf"), QuadInterval{}, "0", "Infinity"},
+		{"neg", must_interval("-1", "2"), QuadInterval{}, "-2", "1"},
+		{"add", must_interval("NaN", "2"), must_interval("1", "2"), "NaN", "NaN"},
+		{"mul", must_interval("3", "2"), must_interval("1", "2"), "Infinity", "-Infinity"},
+		{"add", must_interval("3", "2"), must_interval("NaN", "2"), "NaN", "NaN"},
+	}
+
+	for i, sp := range samples {
+		var r QuadInterval
+
+		switch sp.operation {
+		case "add":
+			r = sp.a.Add(sp.b)
+		case "sub":
+			r = sp.a.Sub(sp.b)
+		case "mul":
+			r = sp.a.Mul(sp.b)
+		case "div":
+			r = sp.a.Div(sp.b)
+		case "sqrt":
+			r = sp.a.Sqrt()
+		case "neg":
+			r = sp.a.Neg()
+		default:
+			panic("impossible")
+		}
+
+		if r.Lo.String() != sp.expected_lo || r.Hi.String() != sp.expected_hi {
+			t.Fatalf("sample %d, %s %s %s: %s != [%s, %s] (expected)", i, sp.operation, sp.a, sp.b, r, sp.expected_lo, sp.expected_hi)
+		}
+	}
+}
+
+func Test_interval_status(t *testing.T) {
+
+	if r := must_interval("1", "2").Div(must_interval("0", "1")); !r.IsNaN() || r.Error() == nil || r.Lo.Status()&DivisionByZero == 0 {
+		t.Fatalf("division by interval containing 0: %s %v", r, r.Error())
+	}
+
+	if r := must_interval("-2", "-1").Sqrt(); !r.IsNaN() || r.Lo.Status()&InvalidOperation == 0 {
+		t.Fatalf("sqrt of negative interval: %s %v", r, r.Error())
+	}
+
+	if r := must_interval("1", "1").Div(must_interval("3", "3")); r.Error() != nil || r.Lo.Status()&Inexact == 0 || r.Hi.Status()&Inexact == 0 {
+		t.Fatalf("1/3: %s %s %s", r, r.Lo.Status(), r.Hi.Status())
+	}
+
+	if r := must_interval("2", "2").Sqrt(); r.Lo.Status() != Inexact || r.Hi.Status() != Inexact { // Rounded, set by decNumber, is not reported
+		t.Fatalf("sqrt 2: %s %s %s", r, r.Lo.Status(), r.Hi.Status())
+	}
+
+	if r := must_interval("1", "2").Add(NewInterval(One().SetStatusFlags(Overflow), One())); r.Error() == nil {
+		t.Fatalf("status propagation: %s %v", r, r.Error())
+	}
+
+	if r := must_interval("1", "2").Mul(EmptyInterval()); !r.IsEmpty() || r.IsNaN() {
+		t.Fatalf("empty propagation: %s", r)
+	}
+}
+
+func Test_interval_helpers(t *testing.T) {
+
+	x := must_interval("1", "1").Div(must_interval("3", "3"))
+
+	if w := x.Width(); w.String() != "0.0000000000000000000000000000000001" {
+		t.Fatalf("Width %s: %s", x, w)
+	}
+
+	if m := x.Midpoint(); !x.Contains(m) {
+		t.Fatalf("Midpoint %s: %s", x, m)
+	}
+
+	if m := must_interval("1", "2").Midpoint(); m.String() != "1.5" {
+		t.Fatalf("Midpoint [1, 2]: %s", m)
+	}
+
+	if m := NewInterval(MaxQuad().Neg(), MaxQuad()).Midpoint(); !m.IsZero() || m.Error() != nil {
+		t.Fatalf("Midpoint [-MaxQuad, MaxQuad]: %s %v", m, m.Error())
+	}
+
+	if w := EmptyInterval().Width(); !w.IsZero() {
+		t.Fatalf("Width of empty interval: %s", w)
+	}
+
+	if m := EmptyInterval().Midpoint(); !m.IsNaN() {
+		t.Fatalf("Midpoint of empty interval: %s", m)
+	}
+
+	// a computed limit never crosses the threshold
+
+	threshold := PointInterval(must_quad("0.3333333333333333333333333333333334"))
+
+	if x.Less(threshold) || !x.LessEqual(threshold) || x.Greater(threshold) || !x.Overlaps(threshold) {
+		t.Fatalf("comparisons %s %s", x, threshold)
+	}
+
+	if !x.Less(PointInterval(must_quad("0.34"))) || !x.Greater(PointInterval(must_quad("0.33"))) || !x.GreaterEqual(PointInterval(x.Lo)) {
+		t.Fatalf("comparisons %s", x)
+	}
+
+	samples := []struct {
+		x        QuadInterval
+		a        string
+		expected bool
+	}{
+		{must_interval("1", "2"), "1", true},
+		{must_interval("1", "2"), "1.5", true},
+		{must_interval("1", "2"), "2", true},
+		{must_interval("1", "2"), "2.000000000000000000000000000000001", false},
+		{must_interval("1", "2"), "NaN", false},
+		{must_interval("-Inf", "Inf"), "1E+6000", true},
+		{EmptyInterval(), "0", false},
+		{must_interval("NaN", "2"), "1", false},
+	}
+
+	for i, sp := range samples {
+		if sp.x.Contains(must_quad(sp.a)) != sp.expected {
+			t.Fatalf("sample %d, %s Contains %s: %t expected", i, sp.x, sp.a, sp.expected)
+		}
+	}
+
+	for _, y := range []QuadInterval{EmptyInterval(), must_interval("NaN", "NaN")} {
+		if y.Less(x) || x.Less(y) || y.LessEqual(x) || x.GreaterEqual(y) || x.Overlaps(y) {
+			t.Fatalf("comparisons with %s should be false", y)
+		}
+	}
+}
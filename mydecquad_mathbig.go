@@ -0,0 +1,213 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+import (
+	"math/big"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                 conversion to and from math/big types                */
+/*                                                                      */
+/************************************************************************/
+
+// Numbers whose magnitude is above 2^bigHugeExp2 overflow Quad, for all rounding modes.
+// Numbers whose magnitude is below 2^bigTinyExp2 are smaller than the smallest subnormal Quad, 1E-6176, for all rounding modes.
+// These numbers are replaced by a number with a reasonable size, 1E+7000 or 1E-7000, before conversion, as math/big can represent numbers with a huge exponent.
+//
+const (
+	bigHugeExp2 = 20420  // 2^20420 is about 1.1E+6147
+	bigTinyExp2 = -20540 // 2^-20540 is about 6.9E-6184
+)
+
+// ToBigInt returns a, rounded to an integral value with the specified rounding mode, as a *big.Int.
+//
+//      12.5 with RoundHalfEven     gives   12
+//      1E+40                       gives   10000000000000000000000000000000000000000
+//
+// If a is Infinite or NaN, which can't be represented by big.Int, an InvalidOperation error is returned.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToBigInt(rounding RoundingMode) (*big.Int, error) {
+
+	if !a.IsFinite() {
+		return nil, QuadError(InvalidOperation)
+	}
+
+	num, _ := toBigFraction(a.ToIntegral(rounding)) // den is 1, as the exponent of an integral value is not negative
+
+	return num, nil
+}
+
+// FromBigInt returns a Quad from a *big.Int.
+//
+// If n has more than 34 digits, it is rounded with RoundHalfEven mode, and the Inexact flag is set.
+// If n is too large, the result is Infinity, and the Overflow flag is set.
+//
+func FromBigInt(n *big.Int) Quad {
+
+	return fromBigInt(n)
+}
+
+// ToBigRat returns the exact value of a, as a *big.Rat.
+//
+//      -12.5     gives   -25/2
+//
+// If a is Infinite or NaN, which can't be represented by big.Rat, an InvalidOperation error is returned.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToBigRat() (*big.Rat, error) {
+
+	if !a.IsFinite() {
+		return nil, QuadError(InvalidOperation)
+	}
+
+	num, den := toBigFraction(a)
+
+	return new(big.Rat).SetFrac(num, den), nil
+}
+
+// FromBigRat returns a Quad from a *big.Rat, rounded to 34 digits with the specified rounding mode.
+//
+// The numerator and the denominator can have any number of digits. The division is done exactly, and the result is rounded only once.
+//
+//      1/3       gives   0.3333333333333333333333333333333333, with Inexact flag
+//      -25/2     gives   -12.5
+//
+// If r is too large, Overflow is set, and if r is too small, Underflow is set, as for the other operations.
+//
+func FromBigRat(r *big.Rat, rounding RoundingMode) Quad {
+
+	return fromBigFraction(r.Num(), r.Denom(), rounding)
+}
+
+// fromBigFraction returns num/den, rounded to 34 digits with the specified rounding mode. den must be positive.
+//
+func fromBigFraction(num *big.Int, den *big.Int, rounding RoundingMode) Quad {
+
+	if num.Sign() != 0 {
+		if exp2 := num.BitLen() - den.BitLen(); exp2 > bigHugeExp2 || exp2 < bigTinyExp2 { // the magnitude of num/den is between 2^(exp2-1) and 2^(exp2+1)
+			return outOfRange(num.Sign() < 0, exp2 > 0, rounding)
+		}
+	}
+
+	return Quad(C.mdb_divide_to_quad(exactBig(num).ptr(), exactBig(den).ptr(), C.int(rounding)))
+}
+
+// exactBig returns n as a BigDecimal, with a context large enough to contain all its digits.
+//
+func exactBig(n *big.Int) BigDecimal {
+
+	s := n.String()
+
+	r, _ := NewBigContext(int32(len(s)), RoundHalfEven).FromString(s) // a big.Int string is always a valid number
+
+	return r
+}
+
+// outOfRange returns the result of the conversion of a number too large or too small for Quad, with the specified sign.
+//
+// The number is replaced by 1E+7000 or 1E-7000, which gives Infinity or 0 with the Overflow or Underflow flag, or the largest or smallest Quad, depending on rounding.
+//
+func outOfRange(negative bool, huge bool, rounding RoundingMode) Quad {
+	var s string = "1E-7000"
+
+	if huge {
+		s = "1E+7000"
+	}
+
+	if negative {
+		s = "-" + s
+	}
+
+	r, _ := BigContext{}.FromString(s)
+
+	return r.ToQuad(rounding)
+}
+
+// ToBigFloat returns the value of a as a *big.Float with precision prec, rounded with big.ToNearestEven.
+//
+// If prec is 0, the precision is 64 bits, as for big.Float.
+//
+// +Infinity and -Infinity give +Inf and -Inf, and -0 gives -0, as big.Float supports them.
+// If a is NaN, which can't be represented by big.Float, an InvalidOperation error is returned.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToBigFloat(prec uint) (*big.Float, error) {
+	var (
+		f *big.Float
+		c C.Ret_coefficient
+	)
+
+	if prec == 0 {
+		prec = 64
+	}
+
+	f = new(big.Float).SetPrec(prec)
+
+	c = C.mdq_to_coefficient(a.val)
+
+	switch c.inf_nan {
+	case C.MDQ_NAN, C.MDQ_SNAN:
+		return nil, QuadError(InvalidOperation)
+
+	case C.MDQ_INFINITE:
+		f.SetInf(c.sign != 0)
+
+	default:
+		num, den := toBigFraction(a)
+
+		f.Quo(new(big.Float).SetInt(num), new(big.Float).SetInt(den)) // operands are exact, so the quotient is rounded only once
+
+		if c.sign != 0 && num.Sign() == 0 {
+			f.Neg(f) // -0
+		}
+	}
+
+	return f, nil
+}
+
+// FromBigFloat returns a Quad from a *big.Float, rounded to 34 digits with the specified rounding mode.
+//
+// The exact value of f is converted, which can have many digits. E.g. big.Float 0.1 with precision 53 is 0.1000000000000000055511151231257827 with Inexact flag.
+//
+// +Inf and -Inf give +Infinity and -Infinity, and -0 gives -0.
+// If f is too large, Overflow is set, and if f is too small, Underflow is set, as for the other operations.
+//
+func FromBigFloat(f *big.Float, rounding RoundingMode) Quad {
+	var r Quad
+
+	switch {
+	case f.IsInf():
+		if f.Signbit() {
+			return NegativeInfinity()
+		}
+		return PositiveInfinity()
+
+	case f.Sign() == 0:
+		if f.Signbit() {
+			r, _ = FromString("-0")
+			return r
+		}
+		return FromInt32(0)
+	}
+
+	if exp2 := f.MantExp(nil); exp2 > bigHugeExp2 || exp2 < bigTinyExp2 { // the magnitude of f is in [2^(exp2-1), 2^exp2)
+		return outOfRange(f.Signbit(), exp2 > 0, rounding)
+	}
+
+	rat, _ := f.Rat(nil) // exact for finite f
+
+	return FromBigRat(rat, rounding)
+}
@@ -0,0 +1,156 @@
+package decnum
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func Test_math_big(t *testing.T) {
+
+	// big.Int
+
+	ints := []struct {
+		a               string
+		rounding        RoundingMode
+		expected_result string
+	}{
+		{"12.5", RoundHalfEven, "12"},
+		{"12.5", RoundHalfUp, "13"},
+		{"-12.5", RoundFloor, "-13"},
+		{"1E+40", RoundHalfEven, "10000000000000000000000000000000000000000"},
+		{"-0", RoundHalfEven, "0"},
+	}
+
+	for i, sp := range ints {
+		n, err := must_quad(sp.a).ToBigInt(sp.rounding)
+
+		if err != nil || n.String() != sp.expected_result {
+			t.Fatalf("sample %d, ToBigInt %s %s: %s %v != %s (expected)", i, sp.a, sp.rounding, n, err, sp.expected_result)
+		}
+	}
+
+	n, _ := new(big.Int).SetString("123456789012345678901234567890123456789", 10)
+
+	if r := FromBigInt(n); r.String() != "1.234567890123456789012345678901235E+38" || r.Status() != Inexact {
+		t.Fatalf("FromBigInt: %s %s", r, r.Status())
+	}
+
+	if r := FromBigInt(big.NewInt(-42)); r.String() != "-42" || r.Status() != 0 {
+		t.Fatalf("FromBigInt: %s %s", r, r.Status())
+	}
+
+	// big.Rat
+
+	if r, err := must_quad("-12.5").ToBigRat(); err != nil || r.String() != "-25/2" {
+		t.Fatalf("ToBigRat: %s %v", r, err)
+	}
+
+	if r, err := must_quad("1E+3").ToBigRat(); err != nil || r.String() != "1000/1" {
+		t.Fatalf("ToBigRat: %s %v", r, err)
+	}
+
+	huge := new(big.Int).Exp(big.NewInt(10), big.NewInt(50000), nil)
+
+	rats := []struct {
+		r               *big.Rat
+		rounding        RoundingMode
+		expected_result string
+		expected_status Status
+	}{
+		{big.NewRat(1, 3), RoundHalfEven, "0.3333333333333333333333333333333333", Inexact},
+		{big.NewRat(2, 3), RoundDown, "0.6666666666666666666666666666666666", Inexact},
+		{big.NewRat(-2, 3), RoundFloor, "-0.6666666666666666666666666666666667", Inexact},
+		{big.NewRat(-25, 2), RoundHalfEven, "-12.5", 0},
+		{big.NewRat(0, 1), RoundHalfEven, "0", 0},
+		{new(big.Rat).SetFrac(huge, big.NewInt(3)), RoundHalfEven, "Infinity", Overflow | Inexact},
+		{new(big.Rat).SetFrac(huge, big.NewInt(3)), RoundDown, "9.999999999999999999999999999999999E+6144", Overflow | Inexact},
+		{new(big.Rat).SetFrac(big.NewInt(-1), huge), RoundHalfEven, "0E-6176", Underflow | Inexact},
+		{new(big.Rat).SetFrac(big.NewInt(1), huge), RoundUp, "1E-6176", Underflow | Inexact},
+		{new(big.Rat).SetFrac(new(big.Int).Add(huge, big.NewInt(1)), huge), RoundHalfEven, "1.000000000000000000000000000000000", Inexact},
+	}
+
+	for i, sp := range rats {
+		r := FromBigRat(sp.r, sp.rounding)
+
+		if r.String() != sp.expected_result || r.Status() != sp.expected_status {
+			t.Fatalf("sample %d, FromBigRat %s %s: %s %s != %s %s (expected)", i, sp.r, sp.rounding, r, r.Status(), sp.expected_result, sp.expected_status)
+		}
+	}
+
+	// big.Float
+
+	if f, err := must_quad("0.1").ToBigFloat(53); err != nil || f.Text('g', -1) != "0.1" || f.Prec() != 53 {
+		t.Fatalf("ToBigFloat 0.1: %s %v", f.Text('g', -1), err)
+	}
+
+	if f, err := must_quad("1.234567890123456789012345678901234").ToBigFloat(200); err != nil || f.Text('g', 34) != "1.234567890123456789012345678901234" {
+		t.Fatalf("ToBigFloat prec 200: %s %v", f.Text('g', 34), err)
+	}
+
+	if f, err := must_quad("-Inf").ToBigFloat(53); err != nil || !f.IsInf() || !f.Signbit() {
+		t.Fatalf("ToBigFloat -Inf: %s %v", f, err)
+	}
+
+	if f, err := must_quad("-0").ToBigFloat(53); err != nil || f.Sign() != 0 || !f.Signbit() {
+		t.Fatalf("ToBigFloat -0: %s %v", f, err)
+	}
+
+	if r := FromBigFloat(big.NewFloat(0.1), RoundHalfEven); r.String() != "0.1000000000000000055511151231257827" || r.Status() != Inexact {
+		t.Fatalf("FromBigFloat 0.1: %s %s", r, r.Status())
+	}
+
+	if r := FromBigFloat(big.NewFloat(-12.5), RoundHalfEven); r.String() != "-12.5" || r.Status() != 0 {
+		t.Fatalf("FromBigFloat -12.5: %s %s", r, r.Status())
+	}
+
+	if r := FromBigFloat(big.NewFloat(math.Inf(-1)), RoundHalfEven); !r.IsInfinite() || !r.IsNegative() {
+		t.Fatalf("FromBigFloat -Inf: %s", r)
+	}
+
+	if r := FromBigFloat(new(big.Float).Neg(new(big.Float)), RoundHalfEven); r.Bytes() != must_quad("-0").Bytes() {
+		t.Fatalf("FromBigFloat -0: %s", r.QuadToString())
+	}
+
+	if r := FromBigFloat(new(big.Float).SetMantExp(big.NewFloat(1), 1<<30), RoundHalfEven); !r.IsInfinite() || r.Status() != Overflow|Inexact {
+		t.Fatalf("FromBigFloat huge: %s %s", r, r.Status())
+	}
+
+	if r := FromBigFloat(new(big.Float).SetMantExp(big.NewFloat(-1), -1<<30), RoundHalfEven); !r.IsZero() || r.Status() != Underflow|Inexact {
+		t.Fatalf("FromBigFloat tiny: %s %s", r, r.Status())
+	}
+
+	// NaN and Infinity
+
+	nan := must_quad("NaN")
+	inf := must_quad("Inf")
+
+	if _, err := nan.ToBigInt(RoundHalfEven); err == nil {
+		t.Fatalf("ToBigInt of NaN should fail")
+	}
+
+	if _, err := inf.ToBigRat(); err == nil {
+		t.Fatalf("ToBigRat of Infinity should fail")
+	}
+
+	if _, err := must_quad("sNaN").ToBigFloat(53); err == nil {
+		t.Fatalf("ToBigFloat of sNaN should fail")
+	}
+
+	// round trips
+
+	for _, s := range []string{"1.234567890123456789012345678901234E-6000", "-9.999999999999999999999999999999999E+6144", "1E-6176", "0.000123"} {
+		a := must_quad(s)
+		r, _ := a.ToBigRat()
+
+		if b := FromBigRat(r, RoundHalfEven); !b.Equal(a) || b.Status() != 0 {
+			t.Fatalf("round trip of %s with big.Rat: %s %s", s, b, b.Status())
+		}
+
+		f, _ := a.ToBigFloat(200)
+
+		if b := FromBigFloat(f, RoundHalfEven); !b.Equal(a) || b.Status()&^Underflow != Inexact { // Underflow for subnormal a
+			t.Fatalf("round trip of %s with big.Float: %s %s", s, b, b.Status())
+		}
+	}
+}
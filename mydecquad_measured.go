@@ -0,0 +1,293 @@
+package decnum
+
+import (
+	"strconv"
+	"strings"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                     value with uncertainty                           */
+/*                                                                      */
+/************************************************************************/
+
+// Measured is a value with its absolute standard uncertainty, e.g. 12.34 ± 0.05.
+//
+// The operations propagate the uncertainty with the first-order rules, the operands being independent:
+//
+//      a + b, a - b     σ = sqrt(σa² + σb²)
+//      a * b            σ = sqrt((b σa)² + (a σb)²)            relative uncertainties add in quadrature
+//      a / b            σ = sqrt((σa/b)² + (a σb/b²)²)         relative uncertainties add in quadrature
+//      a ** n           σ = |n a**(n-1)| σa                    relative uncertainty is multiplied by |n|
+//      sqrt(a)          σ = σa / (2 sqrt(a))                   relative uncertainty is divided by 2
+//
+// Values and uncertainties are computed with 34 digits. They are only rounded when formatted.
+//
+// As for Quad operations, the status fields of the operands propagate into the result.
+//
+type Measured struct {
+	Value       Quad
+	Uncertainty Quad // always >= 0
+}
+
+var (
+	g_measured_two = MustParse("2")
+)
+
+// NewMeasured returns a Measured value. The sign of uncertainty is ignored.
+//
+func NewMeasured(value Quad, uncertainty Quad) Measured {
+
+	return Measured{Value: value, Uncertainty: uncertainty.Abs()}
+}
+
+// Error returns an error if an error flag bit has been set in the status field of Value or Uncertainty.
+//
+func (m Measured) Error() error {
+
+	return m.Value.SetStatusFlags(m.Uncertainty.Status()).Error()
+}
+
+// RelativeUncertainty returns Uncertainty/|Value|.
+//
+// If Value is 0, the result is Infinity, and the DivisionByZero flag is set.
+//
+func (m Measured) RelativeUncertainty() Quad {
+
+	return m.Uncertainty.Div(m.Value.Abs())
+}
+
+// quadrature returns sqrt(a² + b²).
+//
+func quadrature(a Quad, b Quad) Quad {
+
+	return a.Mul(a).Add(b.Mul(b)).Sqrt()
+}
+
+// Add returns a + b.
+//
+func (a Measured) Add(b Measured) Measured {
+
+	return Measured{Value: a.Value.Add(b.Value), Uncertainty: quadrature(a.Uncertainty, b.Uncertainty)}
+}
+
+// Sub returns a - b.
+//
+func (a Measured) Sub(b Measured) Measured {
+
+	return Measured{Value: a.Value.Sub(b.Value), Uncertainty: quadrature(a.Uncertainty, b.Uncertainty)}
+}
+
+// Mul returns a * b.
+//
+func (a Measured) Mul(b Measured) Measured {
+
+	return Measured{Value: a.Value.Mul(b.Value), Uncertainty: quadrature(b.Value.Mul(a.Uncertainty), a.Value.Mul(b.Uncertainty))}
+}
+
+// Div returns a / b.
+//
+// If b.Value is 0, the DivisionByZero flag is set.
+//
+func (a Measured) Div(b Measured) Measured {
+	var value Quad
+
+	value = a.Value.Div(b.Value)
+
+	return Measured{Value: value, Uncertainty: quadrature(a.Uncertainty.Div(b.Value), value.Mul(b.Uncertainty).Div(b.Value))}
+}
+
+// Pow returns a raised to the power of n, n being exact.
+//
+func (a Measured) Pow(n Quad) Measured {
+	var derivative Quad
+
+	derivative = n.Mul(a.Value.Pow(n.Sub(g_one))).Abs() // |n a**(n-1)|
+
+	return Measured{Value: a.Value.Pow(n), Uncertainty: derivative.Mul(a.Uncertainty)}
+}
+
+// Sqrt returns the square root of a.
+//
+// If a.Value is negative, the InvalidOperation flag is set.
+//
+func (a Measured) Sqrt() Measured {
+	var value Quad
+
+	value = a.Value.Sqrt()
+
+	return Measured{Value: value, Uncertainty: a.Uncertainty.Div(value.Mul(g_measured_two))}
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                      formatting and parsing                          */
+/*                                                                      */
+/************************************************************************/
+
+// rounded returns the value and the uncertainty rounded for display.
+//
+// The uncertainty is rounded with RoundHalfUp to one significant digit, or two if its first digit is 1.
+// The value is rounded with RoundHalfEven to the same decimal position.
+// If this position is in the integral part, both are written as integers with trailing zeros, e.g. 1230 and 50.
+//
+// If Value or Uncertainty is not finite, or if Uncertainty is 0, they are returned unchanged.
+//
+func (m Measured) rounded() (value Quad, uncertainty Quad) {
+	var (
+		bcd       [DecquadPmax]byte
+		exp       int32
+		adj       int32 // exponent of the first significant digit of the uncertainty
+		sig       int32 // number of significant digits kept
+		quantizer Quad
+	)
+
+	value, uncertainty = m.Value, m.Uncertainty.Abs()
+
+	if !value.IsFinite() || !uncertainty.IsFinite() || uncertainty.IsZero() {
+		return value, uncertainty
+	}
+
+	bcd, exp, _, _ = uncertainty.ToBCD()
+
+	sig = 1
+	for i, d := range bcd {
+		if d != 0 {
+			adj = exp + int32(len(bcd)-1-i)
+			if d == 1 {
+				sig = 2
+			}
+			break
+		}
+	}
+
+	quantizer = MustParse("1E" + strconv.Itoa(int(adj-sig+1)))
+
+	uncertainty = uncertainty.Quantize(quantizer, RoundHalfUp)
+	value = value.Quantize(quantizer, RoundHalfEven)
+
+	if value.Error() != nil { // value would need more than 34 digits, so it is written unchanged
+		return m.Value, uncertainty
+	}
+
+	if quantizer.GetExponent() > 0 { // write as integers
+		uncertainty = uncertainty.Quantize(g_one, RoundHalfEven)
+		value = value.Quantize(g_one, RoundHalfEven)
+	}
+
+	return value, uncertainty
+}
+
+// String returns the concise notation of m, where the uncertainty is written in parentheses, in units of the last digit of the value.
+//
+//      12.34 ± 0.05         gives   "12.34(5)"
+//      12.3456 ± 0.0123     gives   "12.346(12)"
+//      1234 ± 47            gives   "1230(50)"
+//
+// See PlusMinus for the rounding rules.
+// If the value cannot be rounded to the position of the uncertainty, because it would need more than 34 digits, the ± notation is returned.
+//
+func (m Measured) String() string {
+	var (
+		value       Quad
+		uncertainty Quad
+		digits      Quad
+	)
+
+	value, uncertainty = m.rounded()
+
+	if !value.IsFinite() || !uncertainty.IsFinite() {
+		return value.String() + "(" + uncertainty.String() + ")"
+	}
+
+	if uncertainty.IsZero() {
+		return value.String() + "(0)"
+	}
+
+	digits = uncertainty.Div(MustParse("1E" + strconv.Itoa(int(value.GetExponent())))) // uncertainty in units of the last digit of value
+
+	if digits.GetExponent() != 0 { // value has not been rounded, because it would need more than 34 digits
+		return m.PlusMinus()
+	}
+
+	return value.String() + "(" + digits.String() + ")"
+}
+
+// PlusMinus returns the notation of m with the ± sign.
+//
+//      12.34 ± 0.05         gives   "12.34 ± 0.05"
+//      12.3456 ± 0.0123     gives   "12.346 ± 0.012"
+//      1234 ± 47            gives   "1230 ± 50"
+//
+// The uncertainty is rounded with RoundHalfUp to one significant digit, or two if its first digit is 1.
+// The value is rounded with RoundHalfEven to the same decimal position.
+//
+func (m Measured) PlusMinus() string {
+
+	value, uncertainty := m.rounded()
+
+	return value.String() + " ± " + uncertainty.String()
+}
+
+// ParseMeasured parses a string written in the concise or the ± notation.
+//
+//      "12.34(5)"           gives   12.34 ± 0.05
+//      "12.34 ± 0.05"       gives   12.34 ± 0.05
+//      "12.34 +/- 0.05"     gives   12.34 ± 0.05
+//
+// In the concise notation, the digits in parentheses are in units of the last digit of the value.
+// The value and the uncertainty are not rounded.
+//
+// If the string is invalid, an error is returned, with ConversionSyntax flag set.
+//
+func ParseMeasured(s string) (Measured, error) {
+	var (
+		value       Quad
+		uncertainty Quad
+		err         error
+	)
+
+	s = strings.TrimSpace(s)
+
+	if strings.HasSuffix(s, ")") { // concise notation
+		i := strings.IndexByte(s, '(')
+		if i < 0 {
+			return Measured{}, newError(ConversionSyntax)
+		}
+
+		if value, err = FromString(strings.TrimSpace(s[:i])); err != nil {
+			return Measured{}, err
+		}
+
+		digits := s[i+1 : len(s)-1]
+		if digits == "" || strings.Trim(digits, "0123456789") != "" {
+			return Measured{}, newError(ConversionSyntax)
+		}
+
+		if uncertainty, err = FromString(digits + "E" + strconv.Itoa(int(value.GetExponent()))); err != nil {
+			return Measured{}, err
+		}
+
+		return Measured{Value: value, Uncertainty: uncertainty}, nil
+	}
+
+	for _, sep := range []string{"±", "+/-", "+-"} {
+		if i := strings.Index(s, sep); i >= 0 {
+			if value, err = FromString(strings.TrimSpace(s[:i])); err != nil {
+				return Measured{}, err
+			}
+
+			if uncertainty, err = FromString(strings.TrimSpace(s[i+len(sep):])); err != nil {
+				return Measured{}, err
+			}
+
+			if uncertainty.IsNegative() {
+				return Measured{}, newError(ConversionSyntax)
+			}
+
+			return Measured{Value: value, Uncertainty: uncertainty}, nil
+		}
+	}
+
+	return Measured{}, newError(ConversionSyntax)
+}
@@ -0,0 +1,189 @@
+package decnum
+
+import (
+	"testing"
+)
+
+// converts a string to Measured or aborts.
+//
+func must_measured(s string) Measured {
+
+	m, err := ParseMeasured(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+func Test_Pow_Sqrt(t *testing.T) {
+
+	samples := []struct {
+		operation       string
+		a               string
+		b               string
+		expected_result string
+		expected_status Status
+	}{
+		{"pow", "2", "10", "1024", 0},
+		{"pow", "1.5", "3", "3.375", 0},
+		{"pow", "2", "-2", "0.25", 0},
+		{"pow", "2", "0.5", "1.414213562373095048801688724209698", Inexact},
+		{"pow", "10", "0.3", "1.995262314968879601352455396739536", Inexact},
+		{"pow", "-2", "0.5", "NaN", InvalidOperation},
+		{"pow", "0", "0", "NaN", InvalidOperation},
+		{"pow", "1E-6000", "2", "0E-6176", Inexact | Underflow},
+		{"sqrt", "16", "", "4", 0},
+		{"sqrt", "2", "", "1.414213562373095048801688724209698", Inexact},
+		{"sqrt", "0.3", "", "0.5477225575051661134569697828008021", Inexact},
+		{"sqrt", "-4", "", "NaN", InvalidOperation},
+		{"sqrt", "Inf", "", "Infinity", 0},
+	}
+
+	for i, sp := range samples {
+		var r Quad
+
+		switch sp.operation {
+		case "pow":
+			r = must_quad(sp.a).Pow(must_quad(sp.b))
+		case "sqrt":
+			r = must_quad(sp.a).Sqrt()
+		default:
+			panic("impossible")
+		}
+
+		if r.String() != sp.expected_result || r.Status() != sp.expected_status {
+			t.Fatalf("sample %d, %s %s %s: %s %s != %s %s (expected)", i, sp.operation, sp.a, sp.b, r, r.Status(), sp.expected_result, sp.expected_status)
+		}
+	}
+}
+
+func Test_measured_operations(t *testing.T) {
+
+	samples := []struct {
+		operation            string
+		a                    string
+		b                    string
+		expected_value       string
+		expected_uncertainty string
+		expected_string      string
+	}{
+		{"add", "12.34(5)", "3.10(2)", "15.44", "0.05385164807134504031250710491540330", "15.44(5)"},
+		{"sub", "12.34(5)", "3.10(2)", "9.24", "0.05385164807134504031250710491540330", "9.24(5)"},
+		{"mul", "12.34(5)", "3.10(2)", "38.2540", "0.2914365110963278053221014472030708", "38.3(3)"},
+		{"div", "12.34(5)", "3.10(2)", "3.980645161290322580645161290322581", "0.03032637992677708692217496849147459", "3.98(3)"},
+		{"pow", "12.34(5)", "2", "152.2756", "1.2340", "152.3(12)"},
+		{"sqrt", "16.0(4)", "", "4.0", "0.05", "4.00(5)"},
+	}
+
+	for i, sp := range samples {
+		var r Measured
+
+		a := must_measured(sp.a)
+
+		switch sp.operation {
+		case "add":
+			r = a.Add(must_measured(sp.b))
+		case "sub":
+			r = a.Sub(must_measured(sp.b))
+		case "mul":
+			r = a.Mul(must_measured(sp.b))
+		case "div":
+			r = a.Div(must_measured(sp.b))
+		case "pow":
+			r = a.Pow(must_quad(sp.b))
+		case "sqrt":
+			r = a.Sqrt()
+		default:
+			panic("impossible")
+		}
+
+		if r.Error() != nil || r.Value.String() != sp.expected_value || r.Uncertainty.String() != sp.expected_uncertainty || r.String() != sp.expected_string {
+			t.Fatalf("sample %d, %s %s %s: %s ± %s, %s != %s ± %s, %s (expected)", i, sp.operation, sp.a, sp.b, r.Value, r.Uncertainty, r, sp.expected_value, sp.expected_uncertainty, sp.expected_string)
+		}
+	}
+
+	if r := must_measured("1(1)").Div(must_measured("0(1)")); r.Error() == nil {
+		t.Fatalf("division by 0 should fail")
+	}
+
+	if r := must_measured("12.34(5)").RelativeUncertainty(); r.String() != "0.004051863857374392220421393841166937" {
+		t.Fatalf("RelativeUncertainty: %s", r)
+	}
+}
+
+func Test_measured_format(t *testing.T) {
+
+	samples := []struct {
+		value               string
+		uncertainty         string
+		expected_concise    string
+		expected_plus_minus string
+	}{
+		{"12.34", "0.05", "12.34(5)", "12.34 ± 0.05"},
+		{"12.3456", "0.0123", "12.346(12)", "12.346 ± 0.012"},
+		{"12.3456", "0.0456", "12.35(5)", "12.35 ± 0.05"},
+		{"1234", "47", "1230(50)", "1230 ± 50"},
+		{"1234", "150", "1230(150)", "1230 ± 150"},
+		{"1.23456", "0.096", "1.23(10)", "1.23 ± 0.10"},
+		{"-0.000123456", "0.0000021", "-0.000123(2)", "-0.000123 ± 0.000002"},
+		{"-0.000123456", "0.0000014", "-0.0001235(14)", "-0.0001235 ± 0.0000014"},
+		{"12.34", "0", "12.34(0)", "12.34 ± 0"},
+		{"1E+40", "1E-5", "1E+40 ± 0.000010", "1E+40 ± 0.000010"},
+	}
+
+	for i, sp := range samples {
+		m := NewMeasured(must_quad(sp.value), must_quad(sp.uncertainty))
+
+		if s := m.String(); s != sp.expected_concise {
+			t.Fatalf("sample %d, String %s %s: %s != %s (expected)", i, sp.value, sp.uncertainty, s, sp.expected_concise)
+		}
+
+		if s := m.PlusMinus(); s != sp.expected_plus_minus {
+			t.Fatalf("sample %d, PlusMinus %s %s: %s != %s (expected)", i, sp.value, sp.uncertainty, s, sp.expected_plus_minus)
+		}
+	}
+}
+
+func Test_measured_parse(t *testing.T) {
+
+	samples := []struct {
+		s                    string
+		expected_value       string
+		expected_uncertainty string
+	}{
+		{"12.34(5)", "12.34", "0.05"},
+		{" 12.346(12) ", "12.346", "0.012"},
+		{"1230(50)", "1230", "50"},
+		{"-1.5E-7(3)", "-1.5E-7", "3E-8"},
+		{"12.34 ± 0.05", "12.34", "0.05"},
+		{"12.34±0.05", "12.34", "0.05"},
+		{"12.34 +/- 0.05", "12.34", "0.05"},
+		{"12.34 +- 0.05", "12.34", "0.05"},
+	}
+
+	for i, sp := range samples {
+		m, err := ParseMeasured(sp.s)
+		if err != nil {
+			t.Fatalf("sample %d, ParseMeasured %q: %s", i, sp.s, err)
+		}
+
+		if m.Value.QuadToString() != sp.expected_value || m.Uncertainty.QuadToString() != sp.expected_uncertainty {
+			t.Fatalf("sample %d, ParseMeasured %q: %s %s != %s %s (expected)", i, sp.s, m.Value.QuadToString(), m.Uncertainty.QuadToString(), sp.expected_value, sp.expected_uncertainty)
+		}
+	}
+
+	for _, s := range []string{"", "12.34", "12.34(", "12.34)", "12.34()", "12.34(-5)", "12.34(0.5)", "abc(5)", "12.34 ± -0.05", "12.34 ± abc", "± 0.05"} {
+		if _, err := ParseMeasured(s); err == nil {
+			t.Fatalf("ParseMeasured %q: error expected", s)
+		}
+	}
+
+	// round trip
+
+	for _, s := range []string{"12.34(5)", "1230(50)", "-0.0001235(14)", "152.3(12)", "12.346(12)"} {
+		if r := must_measured(s).String(); r != s {
+			t.Fatalf("round trip %q: %q", s, r)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+import (
+	"fmt"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                    packed decimal (COBOL COMP-3)                     */
+/*                                                                      */
+/************************************************************************/
+
+// A packed decimal field, as COBOL PIC S9(n)V9(m) COMP-3, stores one digit per nibble, followed by a sign nibble.
+// The field has n+m digits, and n+m+1 nibbles rounded up to a whole number of bytes, i.e. (n+m)/2 + 1 bytes.
+// If n+m is even, the first nibble is a pad nibble, which is 0.
+// The scale m is not stored in the field, it is given by the PIC clause of the record layout.
+//
+//      PIC S9(3)V99 COMP-3     123.45       is    12 34 5c
+//      PIC S9(3)V99 COMP-3     -1.2         is    00 12 0d
+//      PIC S9(4) COMP-3        1234         is    01 23 4c
+//      PIC 9(4) COMP-3         1234         is    01 23 4f
+//
+// The sign nibbles are 0xC for positive, 0xD for negative, and 0xF for unsigned fields, which are written by ToPackedUnsigned.
+// The alternate nibbles 0xA and 0xE (positive) and 0xB (negative) are also accepted when reading, as by IBM mainframes.
+
+const PackedMaxBytes = C.MDQ_PACKED_BYTES // largest packed decimal field, 18 bytes, which contains 35 digits, the first one being 0
+
+const (
+	quadMaxExponent = C.DECQUAD_Emax - C.DECQUAD_Pmax + 1 // largest exponent of a Quad, 6111
+	quadMinExponent = C.DECQUAD_Emin - C.DECQUAD_Pmax + 1 // smallest exponent of a Quad, -6176
+)
+
+// PackedError is the error returned by FromPacked if the field is not valid.
+//
+// It describes the invalid byte, so that a bad record can be found in a file:
+//
+//      decnum: invalid packed decimal: byte 1 (0x2a): low nibble 0xa is not a digit
+//
+// errors.Is(err, QuadError(InvalidOperation)) is true for a PackedError.
+//
+type PackedError struct {
+	Offset int    // index of the invalid byte in the field, or -1 if the error is about the whole field or the scale
+	Reason string // description of the error
+}
+
+// Error returns a string describing the invalid byte or nibble.
+//
+func (e *PackedError) Error() string {
+
+	return "decnum: invalid packed decimal: " + e.Reason
+}
+
+// Unwrap returns QuadError(InvalidOperation).
+//
+func (e *PackedError) Unwrap() error {
+
+	return QuadError(InvalidOperation)
+}
+
+// packedCheck returns nil if b is a valid packed decimal field and -scale is in the exponent range, else a PackedError.
+//
+func packedCheck(b []byte, scale int32) *PackedError {
+
+	switch {
+	case len(b) == 0:
+		return &PackedError{Offset: -1, Reason: "empty field"}
+	case len(b) > PackedMaxBytes:
+		return &PackedError{Offset: -1, Reason: fmt.Sprintf("field of %d bytes, longer than %d bytes", len(b), PackedMaxBytes)}
+	case len(b) == PackedMaxBytes && b[0]>>4 != 0:
+		return &PackedError{Offset: 0, Reason: fmt.Sprintf("byte 0 (0x%02x): high nibble %#x must be 0 in a field of %d bytes, as a Quad has %d digits", b[0], b[0]>>4, PackedMaxBytes, DecquadPmax)}
+	}
+
+	for i, c := range b {
+		if c>>4 > 9 {
+			return &PackedError{Offset: i, Reason: fmt.Sprintf("byte %d (0x%02x): high nibble %#x is not a digit", i, c, c>>4)}
+		}
+
+		switch {
+		case i == len(b)-1 && c&0x0F <= 9:
+			return &PackedError{Offset: i, Reason: fmt.Sprintf("byte %d (0x%02x): sign nibble %#x is not 0xa to 0xf", i, c, c&0x0F)}
+		case i < len(b)-1 && c&0x0F > 9:
+			return &PackedError{Offset: i, Reason: fmt.Sprintf("byte %d (0x%02x): low nibble %#x is not a digit", i, c, c&0x0F)}
+		}
+	}
+
+	if -int64(scale) > quadMaxExponent || -int64(scale) < quadMinExponent {
+		return &PackedError{Offset: -1, Reason: fmt.Sprintf("scale %d is out of the range %d to %d", scale, -quadMaxExponent, -quadMinExponent)}
+	}
+
+	return nil
+}
+
+// FromPacked returns a Quad from the packed decimal field b, with scale digits after the decimal point.
+//
+// The conversion is always exact. The exponent of the result is -scale, so that trailing zeros are kept.
+//
+//      12 34 5c    scale 2     gives   123.45
+//      00 12 0d    scale 2     gives   -1.20
+//      01 23 4f    scale 0     gives   1234
+//      00 0d       scale 1     gives   -0.0
+//
+// If b is empty or longer than 18 bytes, or if a digit nibble is not 0 to 9, or if the sign nibble is not 0xA to 0xF, NaN and a *PackedError are returned.
+// For an 18-byte field, the first nibble must be 0, as a Quad has only 34 digits.
+// A *PackedError is also returned if -scale is out of the exponent range of Quad.
+// The status of the returned NaN is InvalidOperation.
+//
+func FromPacked(b []byte, scale int32) (Quad, error) {
+	var p C.Packed_BCD
+
+	if err := packedCheck(b, scale); err != nil {
+		return NaN().SetStatusFlags(InvalidOperation), err
+	}
+
+	offset := PackedMaxBytes - len(b) // the field is right-aligned, with leading zero digits
+
+	for i := range b {
+		p.packed[offset+i] = C.uint8_t(b[i])
+	}
+
+	p.exp = C.int32_t(-scale)
+
+	r := Quad(C.mdq_from_packed(p)) // always valid, as b has been checked
+
+	if r.Status()&InvalidOperation != 0 {
+		return r, QuadError(InvalidOperation)
+	}
+
+	return r, nil
+}
+
+// ToPacked returns a as a packed decimal field with digits digits, scale of them being after the decimal point, as COBOL PIC S9(digits-scale)V9(scale) COMP-3.
+// The result has digits/2 + 1 bytes.
+//
+// The conversion is exact: a is not rounded, so that no digit can be lost silently.
+// If a has more than scale digits after the decimal point, it must be rounded before, e.g. with a.Quantize(FromUnscaled(1, int32(scale)), rounding).
+// The sign nibble is 0xC, or 0xD if a is negative, including -0.
+//
+//      123.45      digits 5, scale 2       gives   12 34 5c
+//      -1.2        digits 5, scale 2       gives   00 12 0d
+//      1.005       digits 3, scale 2       gives   InvalidOperation error
+//      1234        digits 3, scale 0       gives   Overflow error
+//
+// If digits is not in the range 1 to 34, or if a is Infinite or NaN, an InvalidOperation error is returned.
+// An InvalidOperation error is also returned if a would be rounded, because it has non-zero digits beyond scale digits after the decimal point, as 1.005 with scale 2.
+// If the integral part of a doesn't fit in digits-scale digits, an Overflow error is returned.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToPacked(digits int, scale int) ([]byte, error) {
+
+	if digits < 1 || digits > DecquadPmax || int(int32(scale)) != scale || !a.IsFinite() {
+		return nil, QuadError(InvalidOperation)
+	}
+
+	r := a.ClearStatus().Quantize(FromUnscaled(1, int32(scale)), RoundHalfEven)
+
+	if r.Status()&InvalidOperation != 0 || r.GetExponent() != -int32(scale) { // more than 34 digits, or scale out of range
+		return nil, QuadError(Overflow)
+	}
+
+	if r.Status()&Inexact != 0 { // a would be rounded
+		return nil, QuadError(InvalidOperation)
+	}
+
+	p := C.mdq_to_packed(r.val)
+
+	n := digits/2 + 1
+	offset := PackedMaxBytes - n
+
+	for i := 0; i < offset; i++ {
+		if p.packed[i] != 0 {
+			return nil, QuadError(Overflow)
+		}
+	}
+
+	if digits%2 == 0 && p.packed[offset]>>4 != 0 { // pad nibble
+		return nil, QuadError(Overflow)
+	}
+
+	b := make([]byte, n)
+
+	for i := range b {
+		b[i] = byte(p.packed[offset+i])
+	}
+
+	return b, nil
+}
+
+// ToPackedUnsigned returns a as an unsigned packed decimal field, as COBOL PIC 9(digits-scale)V9(scale) COMP-3.
+// It is the same as ToPacked, except that the sign nibble is always 0xF.
+//
+//      123.45      digits 5, scale 2       gives   12 34 5f
+//      -0.00       digits 3, scale 2       gives   00 0f
+//
+// If a is negative, except -0, an InvalidOperation error is returned.
+// The other errors are the same as for ToPacked.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToPackedUnsigned(digits int, scale int) ([]byte, error) {
+
+	b, err := a.ToPacked(digits, scale)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(b) - 1
+
+	if b[n]&0x0F == 0x0D { // negative, accepted only for -0
+		for i, c := range b {
+			if (i < n && c != 0) || (i == n && c>>4 != 0) {
+				return nil, QuadError(InvalidOperation)
+			}
+		}
+	}
+
+	b[n] |= 0x0F
+
+	return b, nil
+}
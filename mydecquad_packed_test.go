@@ -0,0 +1,186 @@
+package decnum
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func Test_packed(t *testing.T) {
+
+	samples := []struct {
+		packed          string
+		scale           int32
+		expected_result string
+	}{
+		{"12345c", 2, "123.45"},
+		{"00120d", 2, "-1.20"},
+		{"01234f", 0, "1234"},
+		{"01234c", -2, "1.234E+5"},
+		{"000d", 1, "0.0"}, // -0.0, but String doesn't show the sign of zero
+		{"1c", 0, "1"},
+		{"9b", 0, "-9"},
+		{"9a", 0, "9"},
+		{"9e", 0, "9"},
+		{"09999999999999999999999999999999999f", 0, "9999999999999999999999999999999999"},
+		{"01234567890123456789012345678901234d", 34, "-0.1234567890123456789012345678901234"},
+		{"1c", 6176, "1E-6176"},
+		{"1c", -6111, "1E+6111"},
+	}
+
+	for i, sp := range samples {
+		b, _ := hex.DecodeString(sp.packed)
+
+		r, err := FromPacked(b, sp.scale)
+
+		if err != nil || r.String() != sp.expected_result {
+			t.Fatalf("sample %d, FromPacked(%s, %d): %s %v != %s (expected)", i, sp.packed, sp.scale, r, err, sp.expected_result)
+		}
+	}
+
+	// -0 keeps its sign, which QuadToString doesn't show
+
+	if r, _ := FromPacked([]byte{0x0d}, 0); r.Bytes() != must_quad("-0").Bytes() {
+		t.Fatalf("FromPacked of 0d: %s is not -0", r)
+	}
+
+	invalid := []struct {
+		packed         string
+		scale          int32
+		expected_error string
+	}{
+		{"", 0, "empty field"},
+		{"12", 0, "byte 0 (0x12): sign nibble 0x2 is not 0xa to 0xf"},
+		{"001a2c", 0, "byte 1 (0x1a): low nibble 0xa is not a digit"},
+		{"a12c", 0, "byte 0 (0xa1): high nibble 0xa is not a digit"},
+		{"05c0", 0, "byte 1 (0xc0): high nibble 0xc is not a digit"},
+		{"12345678901234567890123456789012345c", 0, "byte 0 (0x12): high nibble 0x1 must be 0 in a field of 18 bytes, as a Quad has 34 digits"},
+		{"00001234567890123456789012345678901234567c", 0, "field of 21 bytes, longer than 18 bytes"},
+		{"1c", 6177, "scale 6177 is out of the range -6111 to 6176"},
+		{"1c", -6112, "scale -6112 is out of the range -6111 to 6176"},
+		{"1c", -2147483648, "scale -2147483648 is out of the range -6111 to 6176"},
+	}
+
+	for i, sp := range invalid {
+		b, _ := hex.DecodeString(sp.packed)
+
+		r, err := FromPacked(b, sp.scale)
+
+		if err == nil || !r.IsNaN() || r.Status() != InvalidOperation {
+			t.Fatalf("invalid sample %d, FromPacked(%s, %d) should fail: %s %s", i, sp.packed, sp.scale, r, r.Status())
+		}
+
+		if err.Error() != "decnum: invalid packed decimal: "+sp.expected_error || !errors.Is(err, QuadError(InvalidOperation)) {
+			t.Fatalf("invalid sample %d, FromPacked(%s, %d): %q != %q (expected)", i, sp.packed, sp.scale, err, sp.expected_error)
+		}
+	}
+
+	if _, err := FromPacked([]byte{0x00, 0x1a, 0x2c}, 0); err.(*PackedError).Offset != 1 {
+		t.Fatalf("offset of invalid byte: %d != 1 (expected)", err.(*PackedError).Offset)
+	}
+
+	to_packed := []struct {
+		a               string
+		digits          int
+		scale           int
+		expected_result string
+		expected_error  error
+	}{
+		{"123.45", 5, 2, "12345c", nil},
+		{"-1.2", 5, 2, "00120d", nil},
+		{"1234", 4, 0, "01234c", nil},
+		{"1.005", 3, 2, "", QuadError(InvalidOperation)}, // would be rounded
+		{"123.456", 5, 2, "", QuadError(InvalidOperation)},
+		{"1.01000", 3, 2, "101c", nil}, // trailing zeros are not lost digits
+		{"-0.00", 3, 2, "000d", nil},
+		{"-0.001", 3, 2, "", QuadError(InvalidOperation)},
+		{"0", 1, 0, "0c", nil},
+		{"1.2E+3", 4, 0, "01200c", nil},
+		{"120000", 2, -4, "012c", nil},
+		{"123456", 2, -4, "", QuadError(InvalidOperation)},
+		{"100.00", 4, 2, "", QuadError(Overflow)},
+		{"1234", 3, 0, "", QuadError(Overflow)},
+		{"1234", 4, 1, "", QuadError(Overflow)},
+		{"1", 34, 34, "", QuadError(Overflow)},
+		{"-9999999999999999999999999999999999", 34, 0, "09999999999999999999999999999999999d", nil},
+		{"0.1234567890123456789012345678901234", 34, 34, "01234567890123456789012345678901234c", nil},
+		{"1", 35, 0, "", QuadError(InvalidOperation)},
+		{"1", 0, 0, "", QuadError(InvalidOperation)},
+		{"Infinity", 5, 2, "", QuadError(InvalidOperation)},
+		{"NaN", 5, 2, "", QuadError(InvalidOperation)},
+	}
+
+	for i, sp := range to_packed {
+		b, err := must_quad(sp.a).ToPacked(sp.digits, sp.scale)
+
+		if hex.EncodeToString(b) != sp.expected_result || err != sp.expected_error {
+			t.Fatalf("sample %d, ToPacked(%s, %d, %d): %x %v != %s %v (expected)", i, sp.a, sp.digits, sp.scale, b, err, sp.expected_result, sp.expected_error)
+		}
+	}
+
+	to_packed_unsigned := []struct {
+		a               string
+		digits          int
+		scale           int
+		expected_result string
+		expected_error  error
+	}{
+		{"123.45", 5, 2, "12345f", nil},
+		{"1234", 4, 0, "01234f", nil},
+		{"-0", 3, 0, "000f", nil},
+		{"-0.00", 3, 2, "000f", nil},
+		{"-1", 3, 0, "", QuadError(InvalidOperation)},
+		{"-0.001", 3, 2, "", QuadError(InvalidOperation)},
+		{"0.005", 3, 2, "", QuadError(InvalidOperation)},
+		{"1234", 3, 0, "", QuadError(Overflow)},
+		{"NaN", 3, 0, "", QuadError(InvalidOperation)},
+	}
+
+	for i, sp := range to_packed_unsigned {
+		b, err := must_quad(sp.a).ToPackedUnsigned(sp.digits, sp.scale)
+
+		if hex.EncodeToString(b) != sp.expected_result || err != sp.expected_error {
+			t.Fatalf("sample %d, ToPackedUnsigned(%s, %d, %d): %x %v != %s %v (expected)", i, sp.a, sp.digits, sp.scale, b, err, sp.expected_result, sp.expected_error)
+		}
+
+		if r, err := FromPacked(b, int32(sp.scale)); err == nil && r.Bytes() != r.Abs().Bytes() { // 0xF is positive, also for 0
+			t.Fatalf("sample %d, FromPacked of %x: %s", i, b, r)
+		}
+	}
+
+	// round trip of random values
+
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		digits := 1 + rng.Intn(DecquadPmax)
+		scale := rng.Intn(digits + 1)
+
+		s := ""
+		for j := 0; j < digits; j++ {
+			s += strconv.Itoa(rng.Intn(10))
+		}
+		if rng.Intn(2) == 0 {
+			s = "-" + s
+		}
+
+		a := must_quad(s + "E-" + strconv.Itoa(scale))
+
+		b, err := a.ToPacked(digits, scale)
+		if err != nil || len(b) != digits/2+1 {
+			t.Fatalf("ToPacked(%s, %d, %d): %x %v", a, digits, scale, b, err)
+		}
+
+		r, err := FromPacked(b, int32(scale))
+		if err != nil || r.Bytes() != a.Bytes() {
+			t.Fatalf("round trip of %s, digits %d: %x gives %s %v", a, digits, b, r, err)
+		}
+
+		if b2, _ := r.ToPacked(digits, scale); !bytes.Equal(b, b2) {
+			t.Fatalf("round trip of %x: %x", b, b2)
+		}
+	}
+}
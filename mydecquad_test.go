@@ -1403,3 +1403,224 @@ func Test_operations(t *testing.T) {
 		}
 	}
 }
+
+func Test_ToBCD(t *testing.T) {
+
+	var samples = []struct {
+		a                 string
+		expected_bcd      string // coefficient without leading zeros
+		expected_exp      int32
+		expected_negative bool
+		expected_error    bool
+	}{
+		{"0", "", 0, false, false},
+		{"-0.00", "", -2, false, false},
+		{"1", "1", 0, false, false},
+		{"-12.345", "12345", -3, true, false},
+		{"123E+5", "123", 5, false, false},
+		{"1.000", "1000", -3, false, false},
+		{maxquad, "9999999999999999999999999999999999", 6111, false, false},
+		{"Inf", "", 0, false, true},
+		{"NaN", "", 0, false, true},
+	}
+
+	for i, sp := range samples {
+		bcd, exp, negative, err := must_quad(sp.a).ToBCD()
+
+		if (err != nil) != sp.expected_error {
+			t.Fatalf("sample %d, ToBCD <%s>: unexpected error %v", i, sp.a, err)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		s := ""
+		for _, d := range bcd {
+			if d > 9 {
+				t.Fatalf("sample %d, ToBCD <%s>: invalid digit %d", i, sp.a, d)
+			}
+			if s != "" || d != 0 {
+				s += string('0' + d)
+			}
+		}
+
+		if s != sp.expected_bcd || exp != sp.expected_exp || negative != sp.expected_negative {
+			t.Fatalf("sample %d, ToBCD <%s>: %s %d %t != %s %d %t (expected)", i, sp.a, s, exp, negative, sp.expected_bcd, sp.expected_exp, sp.expected_negative)
+		}
+	}
+}
+
+func Test_constants(t *testing.T) {
+
+	samples := []struct {
+		name     string
+		a        Quad
+		expected string
+	}{
+		{"Pi", Pi(), "3.141592653589793238462643383279503"},
+		{"E", E(), "2.718281828459045235360287471352662"},
+		{"Ln2", Ln2(), "0.6931471805599453094172321214581766"},
+		{"Ln10", Ln10(), "2.302585092994045684017991454684364"},
+		{"Sqrt2", Sqrt2(), "1.414213562373095048801688724209698"},
+		{"MaxQuad", MaxQuad(), maxquad},
+		{"SmallestNormal", SmallestNormal(), "1E-6143"},
+		{"SmallestSubnormal", SmallestSubnormal(), "1E-6176"},
+		{"Epsilon", Epsilon(), "0.000000000000000000000000000000001"},
+		{"PositiveInfinity", PositiveInfinity(), "Infinity"},
+		{"NegativeInfinity", NegativeInfinity(), "-Infinity"},
+	}
+
+	for _, sp := range samples {
+		if sp.a.String() != sp.expected || sp.a.Status() != 0 {
+			t.Fatalf("%s: %s, status %s != %s (expected)", sp.name, sp.a, sp.a.Status(), sp.expected)
+		}
+	}
+
+	if r := One().Add(Epsilon()); r.String() != "1.000000000000000000000000000000001" {
+		t.Fatalf("1 + Epsilon: %s", r)
+	}
+
+	if r := Pi().Sin(); r.IsZero() || r.Abs().Greater(Epsilon()) {
+		t.Fatalf("Sin(Pi): %s", r)
+	}
+
+	if r := Sqrt2().Mul(Sqrt2()); r.Sub(MustParse("2")).Abs().Greater(Epsilon()) {
+		t.Fatalf("Sqrt2*Sqrt2: %s", r)
+	}
+}
+
+func Test_MustParse(t *testing.T) {
+
+	if r := MustParse("-12.50"); r.String() != "-12.50" {
+		t.Fatalf("MustParse: %s", r)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("MustParse(\"abc\") should panic")
+		}
+	}()
+
+	MustParse("abc")
+}
+
+func Test_int64_uint64(t *testing.T) {
+
+	samples := []struct {
+		a               string
+		rounding        RoundingMode
+		expected_uint64 string
+		expected_uint32 string
+	}{
+		{"18446744073709551615", RoundHalfEven, "18446744073709551615", "error"},
+		{"18446744073709551615.4", RoundHalfEven, "18446744073709551615", "error"},
+		{"18446744073709551615.5", RoundHalfEven, "error", "error"},
+		{"18446744073709551616", RoundHalfEven, "error", "error"},
+		{"1.8E+19", RoundHalfEven, "18000000000000000000", "error"},
+		{"1E+20", RoundHalfEven, "error", "error"},
+		{"0E+100", RoundHalfEven, "0", "0"},
+		{"4294967295.5", RoundDown, "4294967295", "4294967295"},
+		{"4294967295.5", RoundHalfUp, "4294967296", "error"},
+		{"-0.2", RoundHalfEven, "0", "0"},
+		{"-0.6", RoundHalfEven, "error", "error"},
+		{"-1", RoundHalfEven, "error", "error"},
+		{"Inf", RoundHalfEven, "error", "error"},
+		{"NaN", RoundHalfEven, "error", "error"},
+	}
+
+	for i, sp := range samples {
+		a := must_quad(sp.a)
+
+		r64 := "error"
+		if n, err := a.ToUint64(sp.rounding); err == nil {
+			r64 = strconv.FormatUint(n, 10)
+		} else if err.(QuadError) != QuadError(InvalidOperation) {
+			t.Fatalf("sample %d, ToUint64 %s: %v", i, sp.a, err)
+		}
+
+		r32 := "error"
+		if n, err := a.ToUint32(sp.rounding); err == nil {
+			r32 = strconv.FormatUint(uint64(n), 10)
+		}
+
+		if r64 != sp.expected_uint64 || r32 != sp.expected_uint32 {
+			t.Fatalf("sample %d, ToUint64 and ToUint32 %s %s: %s %s != %s %s (expected)", i, sp.a, sp.rounding, r64, r32, sp.expected_uint64, sp.expected_uint32)
+		}
+	}
+
+	for _, n := range []uint64{0, 1, 9, 10, 4294967296, 9999999999999999999, 18446744073709551615} {
+		r := FromUint64(n)
+
+		if r.String() != strconv.FormatUint(n, 10) || r.Status() != 0 {
+			t.Fatalf("FromUint64 %d: %s %s", n, r, r.Status())
+		}
+
+		if back, err := r.ToUint64(RoundHalfEven); err != nil || back != n {
+			t.Fatalf("ToUint64 %d: %d %v", n, back, err)
+		}
+	}
+
+	// direct conversions must give the same results as the former conversions, through strings
+
+	values := []int64{0, 1, -1, 42, -2147483648, 2147483648, 999999999999999999, -9223372036854775808, 9223372036854775807}
+
+	for _, n := range values {
+		r := FromInt64(n)
+
+		if r.Bytes() != fromInt64ViaString(n).Bytes() || r.Status() != 0 || r.String() != strconv.FormatInt(n, 10) {
+			t.Fatalf("FromInt64 %d: %s %s", n, r, r.Status())
+		}
+	}
+
+	for _, s := range []string{"9223372036854775807.5", "-9223372036854775808.5", "-9223372036854775809", "12E+17", "12E+18", "-0.5", "-1.5", "1E-6176", "9.99E+6144", "-Inf", "sNaN"} {
+		for _, rounding := range []RoundingMode{RoundHalfEven, RoundHalfUp, RoundHalfDown, RoundDown, RoundFloor, RoundCeiling, RoundUp, Round05Up} {
+			n, err := must_quad(s).ToInt64(rounding)
+			expected, expected_err := must_quad(s).toInt64ViaString(rounding)
+
+			if n != expected || (err == nil) != (expected_err == nil) {
+				t.Fatalf("ToInt64 %s %s: %d %v != %d %v (expected)", s, rounding, n, err, expected, expected_err)
+			}
+		}
+	}
+}
+
+var benchInt64Samples = []int64{0, 42, -123456, 9223372036854775807, -1234567890123}
+
+func Benchmark_FromInt64(b *testing.B) {
+
+	for i := 0; i < b.N; i++ {
+		for _, n := range benchInt64Samples {
+			FromInt64(n)
+		}
+	}
+}
+
+func Benchmark_FromInt64_via_string(b *testing.B) {
+
+	for i := 0; i < b.N; i++ {
+		for _, n := range benchInt64Samples {
+			fromInt64ViaString(n)
+		}
+	}
+}
+
+var benchQuadIntSamples = []Quad{must_quad("0"), must_quad("42"), must_quad("-123456.5"), must_quad("9223372036854775807"), must_quad("-1.234567890123E+12")}
+
+func Benchmark_ToInt64(b *testing.B) {
+
+	for i := 0; i < b.N; i++ {
+		for _, a := range benchQuadIntSamples {
+			a.ToInt64(RoundHalfEven)
+		}
+	}
+}
+
+func Benchmark_ToInt64_via_string(b *testing.B) {
+
+	for i := 0; i < b.N; i++ {
+		for _, a := range benchQuadIntSamples {
+			a.toInt64ViaString(RoundHalfEven)
+		}
+	}
+}
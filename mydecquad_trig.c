@@ -0,0 +1,955 @@
+#include "mydecquad.h"
+
+
+/************************************************************************/
+/*                        working numbers and Pi                        */
+/************************************************************************/
+
+/* The trigonometric and hyperbolic functions are computed with decNumber, with a working precision larger than the 34 digits of decQuad.
+   The result is then rounded to decQuad.
+
+   MDQ_TRIG_DIGITS is the working precision of the series.
+   MDQ_TRIG_GUARD  is the number of extra digits kept by the argument reduction, to protect against cancellation when the argument is near a multiple of Pi/2.
+   MDQ_PI_DIGITS   is the number of digits of Pi after the decimal point, in MDQ_PI.
+
+   The argument reduction of the largest decQuad, 9.99...E+6144, needs DECQUAD_Emax + 1 + DECQUAD_Pmax + MDQ_TRIG_GUARD digits of Pi, which is less than MDQ_PI_DIGITS.
+*/
+#define MDQ_TRIG_DIGITS     60
+#define MDQ_TRIG_GUARD      40
+#define MDQ_PI_DIGITS     6300
+#define MDQ_REDUCE_DIGITS  (DECQUAD_Emax + 1 + DECQUAD_Pmax + MDQ_TRIG_GUARD)   // max precision used by the argument reduction
+
+
+/* decNumber with MDQ_TRIG_DIGITS digits.
+*/
+typedef struct {
+  int32_t         digits;
+  int32_t         exponent;
+  uint8_t         bits;
+  decNumberUnit   lsu[(MDQ_TRIG_DIGITS+DECDPUN-1)/DECDPUN];
+} Trig_number;
+
+
+/* decNumber large enough for the argument reduction.
+   The product k*Pi/2 is computed exactly, so twice MDQ_REDUCE_DIGITS digits are needed.
+*/
+typedef struct {
+  int32_t         digits;
+  int32_t         exponent;
+  uint8_t         bits;
+  decNumberUnit   lsu[(2*MDQ_REDUCE_DIGITS+DECDPUN-1)/DECDPUN];
+} Reduce_number;
+
+
+/* decNumber large enough to hold MDQ_PI exactly, with its integral digit.
+*/
+typedef struct {
+  int32_t         digits;
+  int32_t         exponent;
+  uint8_t         bits;
+  decNumberUnit   lsu[(MDQ_PI_DIGITS+1+DECDPUN-1)/DECDPUN];
+} Pi_number;
+
+
+/* Pi, with MDQ_PI_DIGITS digits after the decimal point.
+*/
+const char MDQ_PI[] =
+  "3."
+  "1415926535897932384626433832795028841971693993751058209749445923078164062862089986280348253421170679"
+  "8214808651328230664709384460955058223172535940812848111745028410270193852110555964462294895493038196"
+  "4428810975665933446128475648233786783165271201909145648566923460348610454326648213393607260249141273"
+  "7245870066063155881748815209209628292540917153643678925903600113305305488204665213841469519415116094"
+  "3305727036575959195309218611738193261179310511854807446237996274956735188575272489122793818301194912"
+  "9833673362440656643086021394946395224737190702179860943702770539217176293176752384674818467669405132"
+  "0005681271452635608277857713427577896091736371787214684409012249534301465495853710507922796892589235"
+  "4201995611212902196086403441815981362977477130996051870721134999999837297804995105973173281609631859"
+  "5024459455346908302642522308253344685035261931188171010003137838752886587533208381420617177669147303"
+  "5982534904287554687311595628638823537875937519577818577805321712268066130019278766111959092164201989"
+  "3809525720106548586327886593615338182796823030195203530185296899577362259941389124972177528347913151"
+  "5574857242454150695950829533116861727855889075098381754637464939319255060400927701671139009848824012"
+  "8583616035637076601047101819429555961989467678374494482553797747268471040475346462080466842590694912"
+  "9331367702898915210475216205696602405803815019351125338243003558764024749647326391419927260426992279"
+  "6782354781636009341721641219924586315030286182974555706749838505494588586926995690927210797509302955"
+  "3211653449872027559602364806654991198818347977535663698074265425278625518184175746728909777727938000"
+  "8164706001614524919217321721477235014144197356854816136115735255213347574184946843852332390739414333"
+  "4547762416862518983569485562099219222184272550254256887671790494601653466804988627232791786085784383"
+  "8279679766814541009538837863609506800642251252051173929848960841284886269456042419652850222106611863"
+  "0674427862203919494504712371378696095636437191728746776465757396241389086583264599581339047802759009"
+  "9465764078951269468398352595709825822620522489407726719478268482601476990902640136394437455305068203"
+  "4962524517493996514314298091906592509372216964615157098583874105978859597729754989301617539284681382"
+  "6868386894277415599185592524595395943104997252468084598727364469584865383673622262609912460805124388"
+  "4390451244136549762780797715691435997700129616089441694868555848406353422072225828488648158456028506"
+  "0168427394522674676788952521385225499546667278239864565961163548862305774564980355936345681743241125"
+  "1507606947945109659609402522887971089314566913686722874894056010150330861792868092087476091782493858"
+  "9009714909675985261365549781893129784821682998948722658804857564014270477555132379641451523746234364"
+  "5428584447952658678210511413547357395231134271661021359695362314429524849371871101457654035902799344"
+  "0374200731057853906219838744780847848968332144571386875194350643021845319104848100537061468067491927"
+  "8191197939952061419663428754440643745123718192179998391015919561814675142691239748940907186494231961"
+  "5679452080951465502252316038819301420937621378559566389377870830390697920773467221825625996615014215"
+  "0306803844773454920260541466592520149744285073251866600213243408819071048633173464965145390579626856"
+  "1005508106658796998163574736384052571459102897064140110971206280439039759515677157700420337869936007"
+  "2305587631763594218731251471205329281918261861258673215791984148488291644706095752706957220917567116"
+  "7229109816909152801735067127485832228718352093539657251210835791513698820914442100675103346711031412"
+  "6711136990865851639831501970165151168517143765761835155650884909989859982387345528331635507647918535"
+  "8932261854896321329330898570642046752590709154814165498594616371802709819943099244889575712828905923"
+  "2332609729971208443357326548938239119325974636673058360414281388303203824903758985243744170291327656"
+  "1809377344403070746921120191302033038019762110110044929321516084244485963766983895228684783123552658"
+  "2131449576857262433441893039686426243410773226978028073189154411010446823252716201052652272111660396"
+  "6655730925471105578537634668206531098965269186205647693125705863566201855810072936065987648611791045"
+  "3348850346113657686753249441668039626579787718556084552965412665408530614344431858676975145661406800"
+  "7002378776591344017127494704205622305389945613140711270004078547332699390814546646458807972708266830"
+  "6343285878569830523580893306575740679545716377525420211495576158140025012622859413021647155097925923"
+  "0990796547376125517656751357517829666454779174501129961489030463994713296210734043751895735961458901"
+  "9389713111790429782856475032031986915140287080859904801094121472213179476477726224142548545403321571"
+  "8530614228813758504306332175182979866223717215916077166925474873898665494945011465406284336639379003"
+  "9769265672146385306736096571209180763832716641627488880078692560290228472104031721186082041900042296"
+  "6171196377921337575114959501566049631862947265473642523081770367515906735023507283540567040386743513"
+  "6222247715891504953098444893330963408780769325993978054193414473774418426312986080998886874132604721"
+  "5695162396586457302163159819319516735381297416772947867242292465436680098067692823828068996400482435"
+  "4037014163149658979409243237896907069779422362508221688957383798623001593776471651228935786015881617"
+  "5578297352334460428151262720373431465319777741603199066554187639792933441952154134189948544473456738"
+  "3162499341913181480927777103863877343177207545654532207770921201905166096280490926360197598828161332"
+  "3166636528619326686336062735676303544776280350450777235547105859548702790814356240145171806246436267"
+  "9456127531813407833033625423278394497538243720583531147711992606381334677687969597030983391307710987"
+  "0408591337464144282277263465947047458784778720192771528073176790770715721344473060570073349243693113"
+  "8350493163128404251219256517980694113528013147013047816437885185290928545201165839341965621349143415"
+  "9562586586557055269049652098580338507224264829397285847831630577775606888764462482468579260395352773"
+  "4803048029005876075825104747091643961362676044925627420420832085661190625454337213153595845068772460"
+  "2901618766795240616342522577195429162991930645537799140373404328752628889639958794757291746426357455"
+  "2540790914513571113694109119393251910760208252026187985318877058429725916778131496990090192116971737"
+  "2784768472686084900337702424291651300500516832336435038951702989392233451722013812806965011784408745";
+
+
+#define DN(x)  ((decNumber *)&(x))   // Trig_number, Reduce_number and Pi_number are used as decNumber
+
+
+/* MDQ_PI, converted once by mdq_trig_init.
+*/
+static Pi_number  static_pi;
+
+
+/* convert MDQ_PI into static_pi, so that the string is not parsed by each function call.
+
+   It is called by mdq_init.
+
+   Exit(1) if an error occurs.
+*/
+void mdq_trig_init(void) {
+  decContext  set;
+
+  decContextDefault(&set, DEC_INIT_BASE);
+
+  set.traps  = 0;
+  set.digits = MDQ_PI_DIGITS + 1;
+
+  decNumberFromString(DN(static_pi), MDQ_PI, &set);
+
+  if ( set.status ) {
+      fprintf(stderr, "INITIALIZATION mydecquad_trig.c:mdq_trig_init() FAILED: decNumber conversion of Pi failed. %s\n", decContextStatusToString(&set));
+      exit(1);
+  }
+}
+
+
+/************************************************************************/
+/*                          working precision                           */
+/************************************************************************/
+
+
+/* initialize a decContext for decNumber calculation, with the precision passed as argument.
+
+   The exponent range is the largest allowed by decNumber mathematical functions, so that no intermediate result overflows.
+*/
+static void mdq_work_context(decContext *set, int32_t digits) {
+
+  decContextDefault(set, DEC_INIT_BASE);
+
+  set->traps  = 0;                     // DEC_INIT_BASE sets traps, that would raise SIGFPE
+  set->round  = DEC_ROUND_HALF_EVEN;
+  set->digits = digits;
+  set->emax   = DEC_MAX_MATH;
+  set->emin   = -DEC_MAX_MATH;
+}
+
+
+/* exponent of the most significant digit of a finite decNumber.
+*/
+static int32_t mdq_adjusted_exponent(const decNumber *dn) {
+
+  return dn->exponent + dn->digits - 1;
+}
+
+
+/* returns 1 if term is too small to change sum, at the precision of set.
+*/
+static int mdq_negligible(const decNumber *term, const decNumber *sum, const decContext *set) {
+
+  if ( decNumberIsZero(term) ) {
+      return 1;
+  }
+
+  if ( decNumberIsZero(sum) ) {
+      return 0;
+  }
+
+  return mdq_adjusted_exponent(term) < mdq_adjusted_exponent(sum) - set->digits - 1;
+}
+
+
+/* load Pi into dn, rounded to set->digits digits.
+
+   The status of set is not modified.
+*/
+static void mdq_load_pi(decNumber *dn, const decContext *set) {
+  decContext  ctx = *set;
+
+  decNumberPlus(dn, DN(static_pi), &ctx);   // only rounds static_pi, which is much faster than parsing MDQ_PI
+}
+
+
+/* load n*Pi/d into dn, rounded to set->digits digits.
+
+   The status of set is not modified.
+*/
+static void mdq_load_pi_fraction(decNumber *dn, int32_t n, int32_t d, const decContext *set) {
+  decContext     ctx = *set;
+  Trig_number    t;
+
+  ctx.digits = MDQ_TRIG_DIGITS;
+
+  mdq_load_pi(dn, &ctx);
+
+  decNumberFromInt32(DN(t), n);
+  decNumberMultiply(dn, dn, DN(t), &ctx);
+
+  decNumberFromInt32(DN(t), d);
+  decNumberDivide(dn, dn, DN(t), &ctx);
+}
+
+
+/* round the working number dn to decQuad.
+
+   status contains the status of the arguments. The flags raised by the rounding are added to it.
+
+   Apart from the special cases processed before calling this function (e.g. sin(0) or cos(0)), the results of these functions are irrational.
+   So, Inexact is always set, and the coefficient of the result is padded with zeros to 34 digits, as for any inexact result.
+   E.g. cos(1E-20) is 1.000000000000000000000000000000000, and not 1.
+*/
+static Quad mdq_trig_result(const decNumber *dn, uint16_t status) {
+  decContext     set;
+  decContext     ws;
+  Trig_number    padded;
+  Trig_number    exp;
+  int32_t        e;
+  Quad           res;
+
+  if ( decNumberIsFinite(dn) && ! decNumberIsZero(dn) && dn->digits < DECQUAD_Pmax ) {
+      e = mdq_adjusted_exponent(dn) - (DECQUAD_Pmax - 1);
+      if ( e < DECQUAD_Emin - (DECQUAD_Pmax - 1) ) {
+          e = DECQUAD_Emin - (DECQUAD_Pmax - 1);          // subnormal, can't be padded to 34 digits
+      }
+      if ( e < dn->exponent ) {
+          mdq_work_context(&ws, MDQ_TRIG_DIGITS);
+          decNumberFromInt32(DN(exp), e);
+          decNumberRescale(DN(padded), dn, DN(exp), &ws);  // exact, only adds trailing zeros
+          dn = DN(padded);
+      }
+  }
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+
+  decQuadFromNumber(&res.val, dn, &set);
+
+  set.status |= DEC_Inexact;
+
+  if ( decQuadIsSubnormal(&res.val) ) {
+      set.status |= DEC_Underflow;              // result is tiny and inexact
+  }
+
+  res.status = status | (decContextGetStatus(&set) & (DEC_Errors | DEC_Inexact));  // Quad doesn't use Rounded, Subnormal and Clamped flags
+
+  return res;
+}
+
+
+/* returns a, which is NaN or sNaN, as result.
+
+   sNaN becomes NaN, and Invalid_operation flag is set.
+*/
+static Quad mdq_trig_nan(Quad a) {
+  decContext  set;
+  Quad        res;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+  set.status = a.status;
+
+  decQuadPlus(&res.val, &a.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* returns NaN, with Invalid_operation flag set.
+*/
+static Quad mdq_trig_invalid(uint16_t status) {
+  Quad  res;
+
+  res.val    = mdq_nan();
+  res.status = status | DEC_Invalid_operation;
+
+  return res;
+}
+
+
+/* returns an exact result.
+*/
+static Quad mdq_trig_exact(decQuad val, uint16_t status) {
+  Quad  res;
+
+  res.val    = val;
+  res.status = status;
+
+  return res;
+}
+
+
+/************************************************************************/
+/*                     argument reduction and series                    */
+/************************************************************************/
+
+
+/* argument reduction for sin, cos and tan.
+
+   Computes r = x - k*Pi/2, where k is the integer nearest to x/(Pi/2), so that |r| <= Pi/4.
+   r is rounded to MDQ_TRIG_DIGITS digits.
+
+   Returns k modulo 4, in [0..3], which is the quadrant of x.
+
+   Pi is used with as many digits as the integral part of x/(Pi/2) has, plus DECQUAD_Pmax + MDQ_TRIG_GUARD digits.
+   So, all the digits of r are right, even for the largest decQuad.
+*/
+static int32_t mdq_reduce_half_pi(decNumber *r, const decNumber *x) {
+  decContext     set;
+  Reduce_number  half_pi;
+  Reduce_number  k;
+  Reduce_number  t;
+  Trig_number    n;
+  int32_t        ae;
+  int32_t        digits;
+  int32_t        quadrant;
+
+  ae = mdq_adjusted_exponent(x);
+
+  if ( ae < 0 ) {             // |x| < 1, no reduction is needed for the series to converge
+      decNumberCopy(r, x);
+      return 0;
+  }
+
+  digits = ae + 1 + DECQUAD_Pmax + MDQ_TRIG_GUARD;
+  assert( digits <= MDQ_REDUCE_DIGITS );
+
+  mdq_work_context(&set, digits);
+
+  mdq_load_pi(DN(half_pi), &set);
+  decNumberFromInt32(DN(n), 2);
+  decNumberDivide(DN(half_pi), DN(half_pi), DN(n), &set);      // Pi/2
+
+  decNumberDivide(DN(k), x, DN(half_pi), &set);
+  decNumberToIntegralValue(DN(k), DN(k), &set);                  // k = x/(Pi/2), rounded half even to integer
+
+  set.digits = 2*digits;
+  decNumberMultiply(DN(t), DN(k), DN(half_pi), &set);            // exact
+  decNumberSubtract(DN(t), x, DN(t), &set);                      // r = x - k*Pi/2
+
+  set.digits = MDQ_TRIG_DIGITS;
+  decNumberPlus(r, DN(t), &set);
+
+  set.digits = digits;
+  decNumberFromInt32(DN(n), 4);
+  decNumberRemainder(DN(t), DN(k), DN(n), &set);                 // k modulo 4, in [-3..3]
+  quadrant = decNumberToInt32(DN(t), &set);
+
+  return (quadrant + 4) % 4;
+}
+
+
+/* Taylor series.
+
+       sin(x)  = x - x^3/3! + x^5/5! - ...        cosine == 0, hyperbolic == 0
+       cos(x)  = 1 - x^2/2! + x^4/4! - ...        cosine == 1, hyperbolic == 0
+       sinh(x) = x + x^3/3! + x^5/5! + ...        cosine == 0, hyperbolic == 1
+
+   |x| must be small (e.g. <= 1), for the series to converge quickly.
+   The sum stops when the term doesn't change the sum at the precision of set.
+*/
+static void mdq_series(decNumber *res, const decNumber *x, int cosine, int hyperbolic, decContext *set) {
+  Trig_number    x2;
+  Trig_number    term;
+  Trig_number    sum;
+  Trig_number    d;
+  int32_t        n;
+
+  decNumberMultiply(DN(x2), x, x, set);
+
+  if ( cosine ) {
+      decNumberFromInt32(DN(term), 1);
+      n = 0;
+  } else {
+      decNumberCopy(DN(term), x);
+      n = 1;
+  }
+
+  decNumberCopy(DN(sum), DN(term));
+
+  for (;;) {
+      decNumberMultiply(DN(term), DN(term), DN(x2), set);
+      decNumberFromInt32(DN(d), (n+1)*(n+2));
+      decNumberDivide(DN(term), DN(term), DN(d), set);
+      if ( ! hyperbolic ) {
+          decNumberMinus(DN(term), DN(term), set);
+      }
+      n += 2;
+
+      if ( mdq_negligible(DN(term), DN(sum), set) ) {
+          break;
+      }
+
+      decNumberAdd(DN(sum), DN(sum), DN(term), set);
+  }
+
+  decNumberCopy(res, DN(sum));
+}
+
+
+/* atan(x), for a finite x.
+
+   The argument is first reduced with atan(x) = 2*atan(x/(1+sqrt(1+x^2))), until |x| < 0.1.
+   Then the series atan(x) = x - x^3/3 + x^5/5 - ... is used.
+*/
+static void mdq_atan_work(decNumber *res, const decNumber *x, decContext *set) {
+  Trig_number    y;
+  Trig_number    y2;
+  Trig_number    power;
+  Trig_number    term;
+  Trig_number    sum;
+  Trig_number    one;
+  Trig_number    d;
+  int32_t        halvings = 0;
+  int32_t        n;
+
+  decNumberPlus(DN(y), x, set);
+  decNumberFromInt32(DN(one), 1);
+
+  while ( ! decNumberIsZero(DN(y)) && mdq_adjusted_exponent(DN(y)) >= -1 ) {   // while |y| >= 0.1
+      decNumberMultiply(DN(d), DN(y), DN(y), set);
+      decNumberAdd(DN(d), DN(d), DN(one), set);
+      decNumberSquareRoot(DN(d), DN(d), set);
+      decNumberAdd(DN(d), DN(d), DN(one), set);
+      decNumberDivide(DN(y), DN(y), DN(d), set);
+      halvings++;
+  }
+
+  decNumberMultiply(DN(y2), DN(y), DN(y), set);
+  decNumberCopy(DN(power), DN(y));
+  decNumberCopy(DN(sum), DN(y));
+
+  for ( n = 3; ; n += 2 ) {
+      decNumberMultiply(DN(power), DN(power), DN(y2), set);
+      decNumberMinus(DN(power), DN(power), set);
+      decNumberFromInt32(DN(d), n);
+      decNumberDivide(DN(term), DN(power), DN(d), set);
+
+      if ( mdq_negligible(DN(term), DN(sum), set) ) {
+          break;
+      }
+
+      decNumberAdd(DN(sum), DN(sum), DN(term), set);
+  }
+
+  decNumberFromInt32(DN(d), 1 << halvings);
+  decNumberMultiply(res, DN(sum), DN(d), set);
+}
+
+
+/* if |x| > limit, x is replaced by limit, with the sign of x.
+
+   Used by hyperbolic functions, when the result is known to overflow decQuad, or to be ±1, for all arguments larger than limit.
+*/
+static void mdq_clamp(decNumber *x, int32_t limit, decContext *set) {
+  Trig_number    lim;
+  Trig_number    cmp;
+  uint8_t        sign;
+
+  sign = x->bits & DECNEG;
+
+  decNumberFromInt32(DN(lim), limit);
+  decNumberCompareTotalMag(DN(cmp), x, DN(lim), set);
+
+  if ( ! decNumberIsNegative(DN(cmp)) && ! decNumberIsZero(DN(cmp)) ) {
+      decNumberCopy(x, DN(lim));
+      x->bits |= sign;
+  }
+}
+
+
+/************************************************************************/
+/*                      trigonometric functions                         */
+/************************************************************************/
+
+
+#define MDQ_SIN    0
+#define MDQ_COS    1
+#define MDQ_TAN    2
+
+
+/* sin, cos and tan.
+*/
+static Quad mdq_circular(Quad a, int fn) {
+  decContext     set;
+  Trig_number    x;
+  Trig_number    r;
+  Trig_number    s;
+  Trig_number    c;
+  decQuad        one;
+  int32_t        quadrant;
+
+  if ( decQuadIsNaN(&a.val) ) {
+      return mdq_trig_nan(a);
+  }
+
+  if ( decQuadIsInfinite(&a.val) ) {
+      return mdq_trig_invalid(a.status);
+  }
+
+  if ( decQuadIsZero(&a.val) ) {                   // sin(±0) = ±0, tan(±0) = ±0, cos(±0) = 1. These results are exact.
+      if ( fn == MDQ_COS ) {
+          decQuadFromInt32(&one, 1);
+          return mdq_trig_exact(one, a.status);
+      }
+      return mdq_trig_exact(a.val, a.status);
+  }
+
+  decQuadToNumber(&a.val, DN(x));
+
+  quadrant = mdq_reduce_half_pi(DN(r), DN(x));     // x = r + quadrant*Pi/2  (modulo 2*Pi)
+
+  mdq_work_context(&set, MDQ_TRIG_DIGITS);
+
+  switch ( fn ) {
+  case MDQ_COS:
+      quadrant = (quadrant + 1) % 4;               // cos(x) = sin(x + Pi/2)
+      // fall through
+  case MDQ_SIN:
+      mdq_series(DN(s), DN(r), quadrant % 2, 0, &set);
+      if ( quadrant >= 2 ) {
+          decNumberMinus(DN(s), DN(s), &set);
+      }
+      break;
+
+  case MDQ_TAN:
+      mdq_series(DN(s), DN(r), 0, 0, &set);
+      mdq_series(DN(c), DN(r), 1, 0, &set);
+      if ( quadrant % 2 == 0 ) {
+          decNumberDivide(DN(s), DN(s), DN(c), &set);  // tan(r)
+      } else {
+          decNumberDivide(DN(s), DN(c), DN(s), &set);  // tan(r + Pi/2) = -cos(r)/sin(r)
+          decNumberMinus(DN(s), DN(s), &set);
+      }
+      break;
+  }
+
+  return mdq_trig_result(DN(s), a.status);
+}
+
+
+/* sine. a is in radians.
+*/
+Quad mdq_sin(Quad a) {
+
+  return mdq_circular(a, MDQ_SIN);
+}
+
+
+/* cosine. a is in radians.
+*/
+Quad mdq_cos(Quad a) {
+
+  return mdq_circular(a, MDQ_COS);
+}
+
+
+/* tangent. a is in radians.
+*/
+Quad mdq_tan(Quad a) {
+
+  return mdq_circular(a, MDQ_TAN);
+}
+
+
+/* arc sine, and arc cosine.
+
+       asin(x) = atan(x/sqrt((1-x)*(1+x)))
+       acos(x) = 2*atan(sqrt((1-x)/(1+x)))
+
+   (1-x) and (1+x) are exact, so that there is no loss of precision when x is near ±1.
+*/
+static Quad mdq_arc(Quad a, int cosine) {
+  decContext     set;
+  Trig_number    x;
+  Trig_number    one;
+  Trig_number    cmp;
+  Trig_number    u;
+  Trig_number    v;
+  decQuad        zero;
+
+  if ( decQuadIsNaN(&a.val) ) {
+      return mdq_trig_nan(a);
+  }
+
+  if ( decQuadIsInfinite(&a.val) ) {
+      return mdq_trig_invalid(a.status);
+  }
+
+  if ( decQuadIsZero(&a.val) && ! cosine ) {         // asin(±0) = ±0, exact
+      return mdq_trig_exact(a.val, a.status);
+  }
+
+  mdq_work_context(&set, MDQ_TRIG_DIGITS);
+
+  decQuadToNumber(&a.val, DN(x));
+  decNumberFromInt32(DN(one), 1);
+
+  decNumberCompareTotalMag(DN(cmp), DN(x), DN(one), &set);
+
+  if ( ! decNumberIsNegative(DN(cmp)) && ! decNumberIsZero(DN(cmp)) ) {   // |x| > 1
+      return mdq_trig_invalid(a.status);
+  }
+
+  decNumberCompare(DN(cmp), DN(x), DN(one), &set);
+
+  if ( decNumberIsZero(DN(cmp)) ) {                  // x == 1, maybe with trailing zeros
+      if ( cosine ) {                                // acos(1) = 0, exact
+          decQuadZero(&zero);
+          return mdq_trig_exact(zero, a.status);
+      }
+      mdq_load_pi_fraction(DN(u), 1, 2, &set);       // asin(1) = Pi/2
+      return mdq_trig_result(DN(u), a.status);
+  }
+
+  decNumberMinus(DN(v), DN(one), &set);
+  decNumberCompare(DN(cmp), DN(x), DN(v), &set);
+
+  if ( decNumberIsZero(DN(cmp)) ) {                  // x == -1
+      if ( cosine ) {
+          mdq_load_pi_fraction(DN(u), 1, 1, &set);   // acos(-1) = Pi
+      } else {
+          mdq_load_pi_fraction(DN(u), -1, 2, &set);  // asin(-1) = -Pi/2
+      }
+      return mdq_trig_result(DN(u), a.status);
+  }
+
+  decNumberSubtract(DN(u), DN(one), DN(x), &set);    // 1-x, exact
+  decNumberAdd(DN(v), DN(one), DN(x), &set);         // 1+x, exact
+
+  if ( cosine ) {
+      decNumberDivide(DN(u), DN(u), DN(v), &set);
+      decNumberSquareRoot(DN(u), DN(u), &set);
+      mdq_atan_work(DN(u), DN(u), &set);
+      decNumberAdd(DN(u), DN(u), DN(u), &set);
+  } else {
+      decNumberMultiply(DN(u), DN(u), DN(v), &set);
+      decNumberSquareRoot(DN(u), DN(u), &set);
+      decNumberDivide(DN(u), DN(x), DN(u), &set);
+      mdq_atan_work(DN(u), DN(u), &set);
+  }
+
+  return mdq_trig_result(DN(u), a.status);
+}
+
+
+/* arc sine. Result is in radians, in [-Pi/2, Pi/2].
+*/
+Quad mdq_asin(Quad a) {
+
+  return mdq_arc(a, 0);
+}
+
+
+/* arc cosine. Result is in radians, in [0, Pi].
+*/
+Quad mdq_acos(Quad a) {
+
+  return mdq_arc(a, 1);
+}
+
+
+/* arc tangent. Result is in radians, in [-Pi/2, Pi/2].
+*/
+Quad mdq_atan(Quad a) {
+  decContext     set;
+  Trig_number    x;
+
+  if ( decQuadIsNaN(&a.val) ) {
+      return mdq_trig_nan(a);
+  }
+
+  if ( decQuadIsZero(&a.val) ) {                    // atan(±0) = ±0, exact
+      return mdq_trig_exact(a.val, a.status);
+  }
+
+  mdq_work_context(&set, MDQ_TRIG_DIGITS);
+
+  if ( decQuadIsInfinite(&a.val) ) {                // atan(±Inf) = ±Pi/2
+      mdq_load_pi_fraction(DN(x), decQuadIsSigned(&a.val) ? -1 : 1, 2, &set);
+      return mdq_trig_result(DN(x), a.status);
+  }
+
+  decQuadToNumber(&a.val, DN(x));
+
+  mdq_atan_work(DN(x), DN(x), &set);
+
+  return mdq_trig_result(DN(x), a.status);
+}
+
+
+/* arc tangent of y/x, using the signs of y and x to determine the quadrant. Result is in radians, in [-Pi, Pi].
+
+   Special values follow the IEEE 754 rules. E.g. atan2(±0, +0) = ±0, atan2(±0, -0) = ±Pi, atan2(±Inf, +Inf) = ±Pi/4.
+*/
+Quad mdq_atan2(Quad y, Quad x) {
+  decContext     set;
+  Trig_number    dy;
+  Trig_number    dx;
+  Trig_number    pi;
+  decQuad        zero;
+  uint16_t       status;
+  int32_t        ysign;
+  int            xneg;
+  Quad           res;
+
+  status = y.status | x.status;
+
+  if ( decQuadIsNaN(&y.val) || decQuadIsNaN(&x.val) ) {   // propagates NaN, the same way as Add
+      decContextDefault(&set, DEC_INIT_DECQUAD);
+      set.status = status;
+
+      decQuadAdd(&res.val, &y.val, &x.val, &set);
+      res.status = decContextGetStatus(&set);
+
+      return res;
+  }
+
+  mdq_work_context(&set, MDQ_TRIG_DIGITS);
+
+  ysign = decQuadIsSigned(&y.val) ? -1 : 1;
+  xneg  = decQuadIsSigned(&x.val);                        // true also for -0
+
+  if ( decQuadIsZero(&y.val) ) {
+      if ( xneg ) {                                       // atan2(±0, -x) = ±Pi
+          mdq_load_pi_fraction(DN(pi), ysign, 1, &set);
+          return mdq_trig_result(DN(pi), status);
+      }
+      return mdq_trig_exact(y.val, status);               // atan2(±0, +x) = ±0
+  }
+
+  if ( decQuadIsInfinite(&y.val) ) {
+      if ( decQuadIsInfinite(&x.val) ) {                  // atan2(±Inf, ±Inf) = ±Pi/4 or ±3*Pi/4
+          mdq_load_pi_fraction(DN(pi), ysign*(xneg ? 3 : 1), 4, &set);
+      } else {                                            // atan2(±Inf, x) = ±Pi/2
+          mdq_load_pi_fraction(DN(pi), ysign, 2, &set);
+      }
+      return mdq_trig_result(DN(pi), status);
+  }
+
+  if ( decQuadIsInfinite(&x.val) ) {
+      if ( xneg ) {                                       // atan2(±y, -Inf) = ±Pi
+          mdq_load_pi_fraction(DN(pi), ysign, 1, &set);
+          return mdq_trig_result(DN(pi), status);
+      }
+      decQuadZero(&zero);                                 // atan2(±y, +Inf) = ±0
+      decQuadCopySign(&zero, &zero, &y.val);
+      return mdq_trig_exact(zero, status);
+  }
+
+  if ( decQuadIsZero(&x.val) ) {                          // atan2(±y, ±0) = ±Pi/2
+      mdq_load_pi_fraction(DN(pi), ysign, 2, &set);
+      return mdq_trig_result(DN(pi), status);
+  }
+
+  decQuadToNumber(&y.val, DN(dy));
+  decQuadToNumber(&x.val, DN(dx));
+
+  decNumberDivide(DN(dy), DN(dy), DN(dx), &set);
+  mdq_atan_work(DN(dy), DN(dy), &set);
+
+  if ( xneg ) {                                           // x < 0, result is atan(y/x) ± Pi
+      mdq_load_pi_fraction(DN(pi), ysign, 1, &set);
+      decNumberAdd(DN(dy), DN(dy), DN(pi), &set);
+  }
+
+  return mdq_trig_result(DN(dy), status);
+}
+
+
+/************************************************************************/
+/*                         hyperbolic functions                         */
+/************************************************************************/
+
+
+/* beyond this limit, sinh and cosh overflow decQuad, as e^14200 > 1E+6166.
+*/
+#define MDQ_HYPERBOLIC_OVERFLOW_LIMIT  15000
+
+/* beyond this limit, tanh is 1 at working precision, as 1 - tanh(x) < 2*e^(-2*x).
+*/
+#define MDQ_TANH_ONE_LIMIT               100
+
+
+/* hyperbolic sine.
+
+   For |x| < 1, the Taylor series is used. Else, sinh(x) = (e^x - e^-x)/2.
+*/
+Quad mdq_sinh(Quad a) {
+  decContext     set;
+  Trig_number    x;
+  Trig_number    e;
+  Trig_number    t;
+  uint8_t        sign;
+
+  if ( decQuadIsNaN(&a.val) ) {
+      return mdq_trig_nan(a);
+  }
+
+  if ( decQuadIsInfinite(&a.val) || decQuadIsZero(&a.val) ) {     // sinh(±Inf) = ±Inf, sinh(±0) = ±0, exact
+      return mdq_trig_exact(a.val, a.status);
+  }
+
+  mdq_work_context(&set, MDQ_TRIG_DIGITS);
+
+  decQuadToNumber(&a.val, DN(x));
+
+  if ( mdq_adjusted_exponent(DN(x)) < 0 ) {                        // |x| < 1
+      mdq_series(DN(x), DN(x), 0, 1, &set);
+      return mdq_trig_result(DN(x), a.status);
+  }
+
+  mdq_clamp(DN(x), MDQ_HYPERBOLIC_OVERFLOW_LIMIT, &set);
+
+  sign = x.bits & DECNEG;
+  x.bits &= ~DECNEG;                                              // |x|
+
+  decNumberExp(DN(e), DN(x), &set);                              // e^|x|
+  decNumberFromInt32(DN(t), 1);
+  decNumberDivide(DN(t), DN(t), DN(e), &set);                    // e^-|x|
+  decNumberSubtract(DN(e), DN(e), DN(t), &set);
+  decNumberFromInt32(DN(t), 2);
+  decNumberDivide(DN(e), DN(e), DN(t), &set);
+
+  e.bits |= sign;
+
+  return mdq_trig_result(DN(e), a.status);
+}
+
+
+/* hyperbolic cosine.
+
+   cosh(x) = (e^x + e^-x)/2
+*/
+Quad mdq_cosh(Quad a) {
+  decContext     set;
+  Trig_number    x;
+  Trig_number    e;
+  Trig_number    t;
+  decQuad        r;
+
+  if ( decQuadIsNaN(&a.val) ) {
+      return mdq_trig_nan(a);
+  }
+
+  if ( decQuadIsInfinite(&a.val) ) {                               // cosh(±Inf) = +Inf, exact
+      decQuadCopyAbs(&r, &a.val);
+      return mdq_trig_exact(r, a.status);
+  }
+
+  if ( decQuadIsZero(&a.val) ) {                                   // cosh(±0) = 1, exact
+      decQuadFromInt32(&r, 1);
+      return mdq_trig_exact(r, a.status);
+  }
+
+  mdq_work_context(&set, MDQ_TRIG_DIGITS);
+
+  decQuadToNumber(&a.val, DN(x));
+
+  mdq_clamp(DN(x), MDQ_HYPERBOLIC_OVERFLOW_LIMIT, &set);
+  x.bits &= ~DECNEG;                                              // |x|
+
+  decNumberExp(DN(e), DN(x), &set);                              // e^|x|
+  decNumberFromInt32(DN(t), 1);
+  decNumberDivide(DN(t), DN(t), DN(e), &set);                    // e^-|x|
+  decNumberAdd(DN(e), DN(e), DN(t), &set);
+  decNumberFromInt32(DN(t), 2);
+  decNumberDivide(DN(e), DN(e), DN(t), &set);
+
+  return mdq_trig_result(DN(e), a.status);
+}
+
+
+/* hyperbolic tangent.
+
+   For |x| < 1, tanh(x) = s/sqrt(1+s^2), where s = sinh(x) is computed with the Taylor series.
+   Else, tanh(x) = (1 - e^(-2x))/(1 + e^(-2x)).
+*/
+Quad mdq_tanh(Quad a) {
+  decContext     set;
+  Trig_number    x;
+  Trig_number    e;
+  Trig_number    t;
+  Trig_number    one;
+  decQuad        r;
+  uint8_t        sign;
+
+  if ( decQuadIsNaN(&a.val) ) {
+      return mdq_trig_nan(a);
+  }
+
+  if ( decQuadIsInfinite(&a.val) ) {                               // tanh(±Inf) = ±1, exact
+      decQuadFromInt32(&r, 1);
+      decQuadCopySign(&r, &r, &a.val);
+      return mdq_trig_exact(r, a.status);
+  }
+
+  if ( decQuadIsZero(&a.val) ) {                                   // tanh(±0) = ±0, exact
+      return mdq_trig_exact(a.val, a.status);
+  }
+
+  mdq_work_context(&set, MDQ_TRIG_DIGITS);
+
+  decQuadToNumber(&a.val, DN(x));
+  decNumberFromInt32(DN(one), 1);
+
+  if ( mdq_adjusted_exponent(DN(x)) < 0 ) {                        // |x| < 1
+      mdq_series(DN(e), DN(x), 0, 1, &set);                        // sinh(x)
+      decNumberMultiply(DN(t), DN(e), DN(e), &set);
+      decNumberAdd(DN(t), DN(t), DN(one), &set);
+      decNumberSquareRoot(DN(t), DN(t), &set);                    // cosh(x)
+      decNumberDivide(DN(e), DN(e), DN(t), &set);
+      return mdq_trig_result(DN(e), a.status);
+  }
+
+  mdq_clamp(DN(x), MDQ_TANH_ONE_LIMIT, &set);
+
+  sign = x.bits & DECNEG;
+  x.bits &= ~DECNEG;                                              // |x|
+
+  decNumberFromInt32(DN(t), -2);
+  decNumberMultiply(DN(x), DN(x), DN(t), &set);
+  decNumberExp(DN(e), DN(x), &set);                              // e^(-2|x|)
+  decNumberSubtract(DN(t), DN(one), DN(e), &set);
+  decNumberAdd(DN(e), DN(one), DN(e), &set);
+  decNumberDivide(DN(e), DN(t), DN(e), &set);
+
+  e.bits |= sign;
+
+  return mdq_trig_result(DN(e), a.status);
+}
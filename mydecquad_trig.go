@@ -0,0 +1,147 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+/************************************************************************/
+/*                                                                      */
+/*                      trigonometric functions                         */
+/*                                                                      */
+/************************************************************************/
+
+// The functions in this file are computed with the C decNumber package, with a working precision of 60 digits.
+// The result is then rounded to 34 digits, with RoundHalfEven mode.
+//
+// Except for some special arguments, like Sin(0) or Cos(0), the results are irrational numbers, so the Inexact flag is set in their status.
+//
+// As for the other operations, the status of the result contains the status of the argument.
+// A NaN argument gives a NaN result. A sNaN argument gives a NaN result, and sets the InvalidOperation flag.
+
+// Sin returns the sine of a, a being in radians.
+//
+// The argument is reduced modulo Pi/2 with as many digits of Pi as needed, so that the result is accurate even for very large a.
+//
+//      Sin(±0) is ±0.
+//      Sin(±Inf) is NaN, and sets the InvalidOperation flag.
+//
+func (a Quad) Sin() Quad {
+
+	return Quad(C.mdq_sin(C.struct_Quad(a)))
+}
+
+// Cos returns the cosine of a, a being in radians.
+//
+// The argument is reduced modulo Pi/2 with as many digits of Pi as needed, so that the result is accurate even for very large a.
+//
+//      Cos(±0) is 1.
+//      Cos(±Inf) is NaN, and sets the InvalidOperation flag.
+//
+func (a Quad) Cos() Quad {
+
+	return Quad(C.mdq_cos(C.struct_Quad(a)))
+}
+
+// Tan returns the tangent of a, a being in radians.
+//
+// The argument is reduced modulo Pi/2 with as many digits of Pi as needed, so that the result is accurate even for very large a.
+//
+//      Tan(±0) is ±0.
+//      Tan(±Inf) is NaN, and sets the InvalidOperation flag.
+//
+func (a Quad) Tan() Quad {
+
+	return Quad(C.mdq_tan(C.struct_Quad(a)))
+}
+
+// Asin returns the arc sine of a, in radians. The result is in the range [-Pi/2, Pi/2].
+//
+//      Asin(±0) is ±0.
+//      Asin(a) is NaN if |a| > 1, and sets the InvalidOperation flag.
+//
+func (a Quad) Asin() Quad {
+
+	return Quad(C.mdq_asin(C.struct_Quad(a)))
+}
+
+// Acos returns the arc cosine of a, in radians. The result is in the range [0, Pi].
+//
+//      Acos(1) is 0.
+//      Acos(a) is NaN if |a| > 1, and sets the InvalidOperation flag.
+//
+func (a Quad) Acos() Quad {
+
+	return Quad(C.mdq_acos(C.struct_Quad(a)))
+}
+
+// Atan returns the arc tangent of a, in radians. The result is in the range [-Pi/2, Pi/2].
+//
+//      Atan(±0) is ±0.
+//      Atan(±Inf) is ±Pi/2.
+//
+func (a Quad) Atan() Quad {
+
+	return Quad(C.mdq_atan(C.struct_Quad(a)))
+}
+
+// Atan2 returns the arc tangent of y/x, in radians, using the signs of y and x to determine the quadrant of the result.
+// The result is in the range [-Pi, Pi].
+//
+// Special values follow the same rules as math.Atan2, e.g.
+//
+//      Atan2(±0, x)       is ±0     if x >= +0
+//      Atan2(±0, x)       is ±Pi    if x <= -0
+//      Atan2(y, ±0)       is ±Pi/2  if y != 0, with the sign of y
+//      Atan2(±Inf, +Inf)  is ±Pi/4
+//      Atan2(±Inf, -Inf)  is ±3Pi/4
+//      Atan2(y, +Inf)     is ±0     if y is finite
+//      Atan2(y, -Inf)     is ±Pi    if y is finite
+//
+// If y or x is NaN, the result is NaN, the same way as for y.Add(x).
+//
+// The status of the result contains the status of y and x.
+//
+func Atan2(y Quad, x Quad) Quad {
+
+	return Quad(C.mdq_atan2(C.struct_Quad(y), C.struct_Quad(x)))
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                         hyperbolic functions                         */
+/*                                                                      */
+/************************************************************************/
+
+// Sinh returns the hyperbolic sine of a.
+//
+//      Sinh(±0) is ±0.
+//      Sinh(±Inf) is ±Inf.
+//      If the result is too large, it is ±Inf, and the Overflow flag is set.
+//
+func (a Quad) Sinh() Quad {
+
+	return Quad(C.mdq_sinh(C.struct_Quad(a)))
+}
+
+// Cosh returns the hyperbolic cosine of a.
+//
+//      Cosh(±0) is 1.
+//      Cosh(±Inf) is +Inf.
+//      If the result is too large, it is +Inf, and the Overflow flag is set.
+//
+func (a Quad) Cosh() Quad {
+
+	return Quad(C.mdq_cosh(C.struct_Quad(a)))
+}
+
+// Tanh returns the hyperbolic tangent of a.
+//
+//      Tanh(±0) is ±0.
+//      Tanh(±Inf) is ±1.
+//
+func (a Quad) Tanh() Quad {
+
+	return Quad(C.mdq_tanh(C.struct_Quad(a)))
+}
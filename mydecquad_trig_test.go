@@ -0,0 +1,261 @@
+package decnum
+
+import (
+	"testing"
+)
+
+func Test_trigonometric(t *testing.T) {
+
+	type Operation_t string
+
+	const (
+		T_SIN   Operation_t = "Sin"
+		T_COS   Operation_t = "Cos"
+		T_TAN   Operation_t = "Tan"
+		T_ASIN  Operation_t = "Asin"
+		T_ACOS  Operation_t = "Acos"
+		T_ATAN  Operation_t = "Atan"
+		T_ATAN2 Operation_t = "Atan2"
+		T_SINH  Operation_t = "Sinh"
+		T_COSH  Operation_t = "Cosh"
+		T_TANH  Operation_t = "Tanh"
+	)
+
+	var samples = []struct {
+		operation             Operation_t // operation to test
+		a                     string      // first argument of operation to test
+		b                     string      // 2nd argument of operation to test, only used by Atan2
+		expected_result       string      // expected result of operation
+		expected_error_status Status      // error status flags expected after an operation
+	}{
+		{T_SIN, "sNaN", "", "NaN", InvalidOperation},
+		{T_SIN, "NaN", "", "NaN", 0},
+		{T_SIN, "Inf", "", "NaN", InvalidOperation},
+		{T_SIN, "-Inf", "", "NaN", InvalidOperation},
+		{T_SIN, "0", "", "0", 0},
+		{T_SIN, "-0.00", "", "0.00", 0},
+		{T_SIN, "1", "", "0.8414709848078965066525023216302990", 0},
+		{T_SIN, "-1", "", "-0.8414709848078965066525023216302990", 0},
+		{T_SIN, "0.5", "", "0.4794255386042030002732879352155714", 0},
+		{T_SIN, "3", "", "0.1411200080598672221007448028081103", 0},
+		{T_SIN, "3.141592653589793238462643383279503", "", "-1.158028306006248941790250554076922E-34", 0},
+		{T_SIN, "1E-20", "", "1.000000000000000000000000000000000E-20", 0},
+		{T_SIN, "1E+22", "", "-0.8522008497671888017727058937530294", 0},
+		{T_SIN, "-7.25", "", "-0.8230808790115054584216711834120565", 0},
+		{T_SIN, "9.999999999999999999999999999999999E+6144", "", "0.5582907749092521238056875912416942", 0},
+
+		{T_COS, "sNaN", "", "NaN", InvalidOperation},
+		{T_COS, "NaN", "", "NaN", 0},
+		{T_COS, "Inf", "", "NaN", InvalidOperation},
+		{T_COS, "0", "", "1", 0},
+		{T_COS, "1", "", "0.5403023058681397174009366074429766", 0},
+		{T_COS, "-1", "", "0.5403023058681397174009366074429766", 0},
+		{T_COS, "3", "", "-0.9899924966004454572715727947312613", 0},
+		{T_COS, "1E-20", "", "1.000000000000000000000000000000000", 0},
+		{T_COS, "1E+22", "", "0.5232147853951389454975944733847095", 0},
+		{T_COS, "1.570796326794896619231321691639751", "", "4.420985846996875529104874722961539E-34", 0},
+
+		{T_TAN, "sNaN", "", "NaN", InvalidOperation},
+		{T_TAN, "NaN", "", "NaN", 0},
+		{T_TAN, "-Inf", "", "NaN", InvalidOperation},
+		{T_TAN, "0", "", "0", 0},
+		{T_TAN, "1", "", "1.557407724654902230506974807458360", 0},
+		{T_TAN, "-1", "", "-1.557407724654902230506974807458360", 0},
+		{T_TAN, "1.570796326794896619231321691639751", "", "2261938930836633226244288822199802", 0},
+		{T_TAN, "0.7853981633974483096156608458198757", "", "1.000000000000000000000000000000000", 0},
+		{T_TAN, "1E+22", "", "-1.628778225606898878549375936939549", 0},
+
+		{T_ASIN, "sNaN", "", "NaN", InvalidOperation},
+		{T_ASIN, "NaN", "", "NaN", 0},
+		{T_ASIN, "Inf", "", "NaN", InvalidOperation},
+		{T_ASIN, "0", "", "0", 0},
+		{T_ASIN, "0.5", "", "0.5235987755982988730771072305465838", 0},
+		{T_ASIN, "-0.5", "", "-0.5235987755982988730771072305465838", 0},
+		{T_ASIN, "1", "", "1.570796326794896619231321691639751", 0},
+		{T_ASIN, "-1", "", "-1.570796326794896619231321691639751", 0},
+		{T_ASIN, "1.0", "", "1.570796326794896619231321691639751", 0},
+		{T_ASIN, "0.9999999999999999999999999999999999", "", "1.570796326794896605089186067908801", 0},
+		{T_ASIN, "2", "", "NaN", InvalidOperation},
+		{T_ASIN, "-1.5", "", "NaN", InvalidOperation},
+
+		{T_ACOS, "sNaN", "", "NaN", InvalidOperation},
+		{T_ACOS, "NaN", "", "NaN", 0},
+		{T_ACOS, "-Inf", "", "NaN", InvalidOperation},
+		{T_ACOS, "1", "", "0", 0},
+		{T_ACOS, "1.000", "", "0", 0},
+		{T_ACOS, "-1", "", "3.141592653589793238462643383279503", 0},
+		{T_ACOS, "0", "", "1.570796326794896619231321691639751", 0},
+		{T_ACOS, "0.5", "", "1.047197551196597746154214461093168", 0},
+		{T_ACOS, "0.9999999999999999999999999999999999", "", "1.414213562373095048801688724209698E-17", 0},
+		{T_ACOS, "2", "", "NaN", InvalidOperation},
+
+		{T_ATAN, "sNaN", "", "NaN", InvalidOperation},
+		{T_ATAN, "NaN", "", "NaN", 0},
+		{T_ATAN, "Inf", "", "1.570796326794896619231321691639751", 0},
+		{T_ATAN, "-Inf", "", "-1.570796326794896619231321691639751", 0},
+		{T_ATAN, "0", "", "0", 0},
+		{T_ATAN, "1", "", "0.7853981633974483096156608458198757", 0},
+		{T_ATAN, "-1", "", "-0.7853981633974483096156608458198757", 0},
+		{T_ATAN, "0.5", "", "0.4636476090008061162142562314612144", 0},
+		{T_ATAN, "1E+100", "", "1.570796326794896619231321691639751", 0},
+		{T_ATAN, "-1E-100", "", "-1.000000000000000000000000000000000E-100", 0},
+		{T_ATAN, "9.999999999999999999999999999999999E+6144", "", "1.570796326794896619231321691639751", 0},
+
+		{T_ATAN2, "sNaN", "1", "NaN", InvalidOperation},
+		{T_ATAN2, "1", "NaN", "NaN", 0},
+		{T_ATAN2, "0", "1", "0", 0},
+		{T_ATAN2, "0", "-0", "3.141592653589793238462643383279503", 0},
+		{T_ATAN2, "-0", "-1", "-3.141592653589793238462643383279503", 0},
+		{T_ATAN2, "1", "0", "1.570796326794896619231321691639751", 0},
+		{T_ATAN2, "-1", "-0", "-1.570796326794896619231321691639751", 0},
+		{T_ATAN2, "Inf", "Inf", "0.7853981633974483096156608458198757", 0},
+		{T_ATAN2, "Inf", "-Inf", "2.356194490192344928846982537459627", 0},
+		{T_ATAN2, "-Inf", "5", "-1.570796326794896619231321691639751", 0},
+		{T_ATAN2, "1", "Inf", "0", 0},
+		{T_ATAN2, "1", "-Inf", "3.141592653589793238462643383279503", 0},
+		{T_ATAN2, "1", "1", "0.7853981633974483096156608458198757", 0},
+		{T_ATAN2, "1", "-1", "2.356194490192344928846982537459627", 0},
+		{T_ATAN2, "-1", "-1", "-2.356194490192344928846982537459627", 0},
+		{T_ATAN2, "3", "-4", "2.498091544796508851659834154562180", 0},
+		{T_ATAN2, "1E-30", "-1", "3.141592653589793238462643383278503", 0},
+
+		{T_SINH, "sNaN", "", "NaN", InvalidOperation},
+		{T_SINH, "NaN", "", "NaN", 0},
+		{T_SINH, "Inf", "", "Infinity", 0},
+		{T_SINH, "-Inf", "", "-Infinity", 0},
+		{T_SINH, "0", "", "0", 0},
+		{T_SINH, "1", "", "1.175201193643801456882381850595601", 0},
+		{T_SINH, "-1", "", "-1.175201193643801456882381850595601", 0},
+		{T_SINH, "0.5", "", "0.5210953054937473616224256264114916", 0},
+		{T_SINH, "1E-20", "", "1.000000000000000000000000000000000E-20", 0},
+		{T_SINH, "20", "", "242582597.7048951379539766040514914", 0},
+		{T_SINH, "-100", "", "-1.344058570908067724206312775790007E+43", 0},
+		{T_SINH, "14200", "", "Infinity", Overflow},
+		{T_SINH, "-20000", "", "-Infinity", Overflow},
+
+		{T_COSH, "sNaN", "", "NaN", InvalidOperation},
+		{T_COSH, "NaN", "", "NaN", 0},
+		{T_COSH, "Inf", "", "Infinity", 0},
+		{T_COSH, "-Inf", "", "Infinity", 0},
+		{T_COSH, "0", "", "1", 0},
+		{T_COSH, "1", "", "1.543080634815243778477905620757062", 0},
+		{T_COSH, "-1", "", "1.543080634815243778477905620757062", 0},
+		{T_COSH, "0.5", "", "1.127625965206380785226225161402672", 0},
+		{T_COSH, "1E-20", "", "1.000000000000000000000000000000000", 0},
+		{T_COSH, "20", "", "242582597.7048951400151302264900492", 0},
+		{T_COSH, "14200", "", "Infinity", Overflow},
+
+		{T_TANH, "sNaN", "", "NaN", InvalidOperation},
+		{T_TANH, "NaN", "", "NaN", 0},
+		{T_TANH, "Inf", "", "1", 0},
+		{T_TANH, "-Inf", "", "-1", 0},
+		{T_TANH, "0", "", "0", 0},
+		{T_TANH, "1", "", "0.7615941559557648881194582826047936", 0},
+		{T_TANH, "-1", "", "-0.7615941559557648881194582826047936", 0},
+		{T_TANH, "0.5", "", "0.4621171572600097585023184836436725", 0},
+		{T_TANH, "1E-20", "", "1.000000000000000000000000000000000E-20", 0},
+		{T_TANH, "20", "", "0.9999999999999999915032914894168220", 0},
+		{T_TANH, "-100", "", "-1.000000000000000000000000000000000", 0},
+		{T_TANH, "1E+6144", "", "1.000000000000000000000000000000000", 0},
+	}
+
+	for i, sp := range samples {
+		var (
+			a      Quad
+			b      Quad
+			result Quad
+		)
+
+		a = must_quad(sp.a)
+
+		switch sp.operation {
+		case T_SIN:
+			result = a.Sin()
+		case T_COS:
+			result = a.Cos()
+		case T_TAN:
+			result = a.Tan()
+		case T_ASIN:
+			result = a.Asin()
+		case T_ACOS:
+			result = a.Acos()
+		case T_ATAN:
+			result = a.Atan()
+		case T_ATAN2:
+			b = must_quad(sp.b)
+			result = Atan2(a, b)
+		case T_SINH:
+			result = a.Sinh()
+		case T_COSH:
+			result = a.Cosh()
+		case T_TANH:
+			result = a.Tanh()
+		default:
+			panic("operation unknown")
+		}
+
+		// check status and output
+
+		if result.ErrorStatus() != sp.expected_error_status {
+			t.Fatalf("sample %d, %s <%s, %s>:  \"%s\" (status) != \"%s\" (expected status)", i, sp.operation, sp.a, sp.b, result.ErrorStatus(), sp.expected_error_status)
+		}
+
+		if result.String() != sp.expected_result {
+			t.Fatalf("sample %d, %s <%s, %s>:  \"%s\" (output) != \"%s\" (expected result)", i, sp.operation, sp.a, sp.b, result.String(), sp.expected_result)
+		}
+	}
+}
+
+func Test_trigonometric_status(t *testing.T) {
+
+	// irrational results are inexact
+
+	if r := One().Sin(); r.Status() != Inexact {
+		t.Fatalf("incorrect status: %s", r.Status())
+	}
+
+	if r := Atan2(One(), One()); r.Status() != Inexact {
+		t.Fatalf("incorrect status: %s", r.Status())
+	}
+
+	// special values give exact results
+
+	if r := Zero().Sin(); r.Status() != 0 {
+		t.Fatalf("incorrect status: %s", r.Status())
+	}
+
+	if r := Zero().Cosh(); r.Status() != 0 || r.String() != "1" {
+		t.Fatalf("incorrect result: %s %s", r, r.Status())
+	}
+
+	// status of arguments propagates to the result
+
+	a := One().SetStatusFlags(DivisionByZero)
+	b := One().SetStatusFlags(Underflow)
+
+	r := a.Cos()
+	if r.Status() != DivisionByZero|Inexact {
+		t.Fatalf("incorrect status: %s", r.Status())
+	}
+
+	r = Atan2(a, b)
+	if r.Status() != DivisionByZero|Underflow|Inexact {
+		t.Fatalf("incorrect status: %s", r.Status())
+	}
+
+	r = a.Sin().Cos().Tan().Atan().Sinh().Cosh().Tanh().Acos().Asin()
+	if r.Status() != DivisionByZero|Inexact {
+		t.Fatalf("incorrect status: %s", r.Status())
+	}
+}
+
+var benchTrigSamples = []Quad{must_quad("0.5"), must_quad("-1.234567890123"), must_quad("3"), must_quad("1E+100")}
+
+func Benchmark_Sin(b *testing.B) {
+
+	for i := 0; i < b.N; i++ {
+		for _, a := range benchTrigSamples {
+			a.Sin()
+		}
+	}
+}
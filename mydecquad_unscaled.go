@@ -0,0 +1,304 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+	"strconv"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                      unscaled integer and scale                      */
+/*                                                                      */
+/************************************************************************/
+
+// An amount is often stored as an integer number of minor units, the unscaled value, with a scale which is the number of digits after the decimal point.
+//
+//      unscaled 12345, scale 2       is    123.45
+//      unscaled 12345, scale 0       is    12345
+//      unscaled 12345, scale -2      is    1234500, that is 12345E+2
+//
+// The scale is the negated exponent of the Quad.
+
+// FromUnscaled returns the Quad unscaled * 10^-scale.
+//
+//      FromUnscaled(12345, 2)      gives   123.45
+//      FromUnscaled(-5, 3)         gives   -0.005
+//
+// The result is exact, and its exponent is -scale, so that a.Scale() returns scale.
+// If -scale is out of the exponent range of Quad, Overflow, Underflow or Inexact flag is set, as for the other operations.
+// This is also true for a very large scale, as FromUnscaled(1, math.MinInt32), which gives Infinity with Overflow and Inexact.
+// If unscaled is 0, the exponent is clamped to the range of Quad, and no flag is set.
+//
+func FromUnscaled(unscaled int64, scale int32) Quad {
+
+	return Quad(C.mdq_from_unscaled(C.int64_t(unscaled), C.int32_t(scale)))
+}
+
+// Unscaled returns a rounded to scale digits after the decimal point, as an unscaled integer.
+// The rounding passed as argument is used.
+//
+//      123.456    scale 2     gives   12346    (with RoundHalfEven)
+//      123.4      scale 2     gives   12340
+//      123456     scale -2    gives   1235     (with RoundHalfEven)
+//
+// If a is Infinite or NaN, or if the unscaled value doesn't fit in int64, InvalidOperation error is returned.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) Unscaled(scale int32, rounding RoundingMode) (int64, error) {
+	var result C.Ret_int64_t
+
+	result = C.mdq_to_unscaled(C.struct_Quad(a), C.int32_t(scale), C.int(rounding))
+
+	if Status(result.status)&ErrorMask != 0 {
+		return 0, newError(Status(result.status))
+	}
+
+	return int64(result.val), nil
+}
+
+// Scale returns the scale of a, which is the negated exponent.
+//
+//      123.45     gives   2
+//      123.450    gives   3
+//      1.2E+3     gives   -2
+//
+// If a is NaN, sNaN or Infinity, the special values ExpNaN, ExpSignalingNaN or ExpInf are returned, as for GetExponent.
+//
+func (a Quad) Scale() int32 {
+
+	exp := a.GetExponent()
+
+	if !a.IsFinite() {
+		return exp
+	}
+
+	return -exp
+}
+
+/************************************************************************/
+/*                                                                      */
+/*              unscaled integer as two's complement bytes              */
+/*                                                                      */
+/************************************************************************/
+
+// Java BigDecimal, the Kafka Connect Decimal logical type and the CQL decimal type of Cassandra and Scylla store a number as an unscaled integer and a scale.
+// The unscaled integer is written in big-endian two's complement with the minimal number of bytes, as returned by BigInteger.toByteArray:
+//
+//      123.45      is    unscaled  0x30 0x39,    scale 2
+//      -1          is    unscaled  0xFF,         scale 0
+//      128         is    unscaled  0x00 0x80,    scale 0
+//
+// The Kafka form contains only the unscaled bytes, the scale being specified by the schema.
+// The CQL form is a big-endian int32 scale, followed by the unscaled bytes.
+
+// g_1e34_hi and g_1e34_lo are 10^34 as a 128-bit integer, which is the limit for a coefficient of 34 digits.
+// g_1e33_hi and g_1e33_lo are 10^33, the limit for a NaN payload.
+//
+var (
+	g_1e34_hi, g_1e34_lo = bits.Mul64(1e19, 1e15)
+	g_1e33_hi, g_1e33_lo = bits.Mul64(1e19, 1e14)
+)
+
+// coefficientBinary returns the coefficient hi*10^19 + lo returned by mdq_to_coefficient, as a 128-bit integer.
+//
+func coefficientBinary(c C.Ret_coefficient) (hi uint64, lo uint64) {
+	var carry uint64
+
+	hi, lo = bits.Mul64(uint64(c.hi), 1e19)
+	lo, carry = bits.Add64(lo, uint64(c.lo), 0)
+
+	return hi + carry, lo
+}
+
+// fromBinaryCoefficient returns (hi*2^64 + lo) * 10^exp, with the specified sign.
+// The coefficient must be less than 10^34.
+//
+func fromBinaryCoefficient(hi uint64, lo uint64, exp int32, negative bool) Quad {
+	var sign C.uint32_t
+
+	if negative {
+		sign = 1
+	}
+
+	q, r := bits.Div64(hi, lo, 1e19) // hi < 10^34/2^64, so q < 10^15
+
+	return Quad(C.mdq_from_coefficient(C.uint64_t(q), C.uint64_t(r), C.int32_t(exp), sign))
+}
+
+// FromUnscaledBytes returns the Quad unscaled * 10^-scale, unscaled being a big-endian two's complement integer.
+// This is the Kafka Connect Decimal form, the scale being specified by the schema.
+//
+//      unscaled 0x30 0x39,  scale 2       gives   123.45
+//      unscaled 0xCF 0xC7,  scale 2       gives   -123.45
+//
+// If unscaled has more than 34 digits, the result is rounded to 34 digits with RoundHalfEven mode, and the Inexact flag is set, as for FromString.
+//
+// If unscaled is empty, or if -scale is out of the exponent range of Quad, NaN and an InvalidOperation error are returned.
+// If the rounded result overflows, Infinity and an Overflow error are returned.
+//
+func FromUnscaledBytes(unscaled []byte, scale int32) (Quad, error) {
+
+	exp := -int64(scale)
+
+	if len(unscaled) == 0 || exp < quadMinExponent || exp > quadMaxExponent {
+		return NaN().SetStatusFlags(InvalidOperation), QuadError(InvalidOperation)
+	}
+
+	if len(unscaled) <= 16 {
+		var buf [16]byte
+
+		negative := unscaled[0]&0x80 != 0
+
+		if negative { // sign extension to 128 bits
+			for i := range buf {
+				buf[i] = 0xFF
+			}
+		}
+
+		copy(buf[16-len(unscaled):], unscaled)
+
+		hi := binary.BigEndian.Uint64(buf[0:8])
+		lo := binary.BigEndian.Uint64(buf[8:16])
+
+		if negative { // magnitude
+			var borrow uint64
+			lo, borrow = bits.Sub64(0, lo, 0)
+			hi, _ = bits.Sub64(0, hi, borrow)
+		}
+
+		if hi < g_1e34_hi || (hi == g_1e34_hi && lo < g_1e34_lo) {
+			return fromBinaryCoefficient(hi, lo, int32(exp), negative), nil // always exact, as exp is in the range of Quad
+		}
+	}
+
+	// more than 34 digits, the number is rounded by FromString, with a single rounding
+
+	n := new(big.Int).SetBytes(unscaled)
+
+	if unscaled[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(8*len(unscaled))))
+	}
+
+	r, _ := FromString(n.String() + "E" + strconv.FormatInt(exp, 10)) // always a valid number
+
+	if r.Status()&ErrorMask != 0 {
+		return r, newError(r.Status())
+	}
+
+	return r, nil
+}
+
+// UnscaledBytes returns a rounded to scale digits after the decimal point, as a big-endian two's complement unscaled integer with the minimal number of bytes.
+// The rounding passed as argument is used.
+// This is the Kafka Connect Decimal form, the scale being specified by the schema.
+//
+//      123.456    scale 2     gives   0x30 0x3A    (12346 with RoundHalfEven)
+//      -1         scale 0     gives   0xFF
+//      0          scale 2     gives   0x00
+//
+// -0 gives 0, as the unscaled integer has no sign for zero.
+//
+// If a is Infinite or NaN, an InvalidOperation error is returned.
+// If the unscaled value has more than 34 digits, an Overflow error is returned.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) UnscaledBytes(scale int32, rounding RoundingMode) ([]byte, error) {
+
+	if !a.IsFinite() {
+		return nil, QuadError(InvalidOperation)
+	}
+
+	r := a.ClearStatus().Quantize(FromUnscaled(1, scale), rounding)
+
+	if r.Status()&InvalidOperation != 0 || r.GetExponent() != -scale { // more than 34 digits, or scale out of range
+		return nil, QuadError(Overflow)
+	}
+
+	return coefficientBytes(C.mdq_to_coefficient(r.val)), nil
+}
+
+// coefficientBytes returns the signed coefficient c as a big-endian two's complement integer, with the minimal number of bytes.
+//
+func coefficientBytes(c C.Ret_coefficient) []byte {
+	var buf [16]byte
+
+	hi, lo := coefficientBinary(c)
+
+	if c.sign != 0 {
+		var borrow uint64
+		lo, borrow = bits.Sub64(0, lo, 0)
+		hi, _ = bits.Sub64(0, hi, borrow)
+	}
+
+	binary.BigEndian.PutUint64(buf[0:8], hi)
+	binary.BigEndian.PutUint64(buf[8:16], lo)
+
+	// removes the leading bytes which are only sign extension
+
+	i := 0
+	for i < 15 && ((buf[i] == 0x00 && buf[i+1]&0x80 == 0) || (buf[i] == 0xFF && buf[i+1]&0x80 != 0)) {
+		i++
+	}
+
+	return buf[i:]
+}
+
+// FromCQLDecimal returns a Quad from a CQL decimal, which is a big-endian int32 scale followed by the big-endian two's complement unscaled integer.
+// It is also the form of the serialization of Java BigDecimal.
+//
+//      0x00 0x00 0x00 0x02 0x30 0x39        gives   123.45
+//
+// The trailing zeros are kept, as the exponent of the result is -scale.
+// If the unscaled integer has more than 34 digits, it is rounded with RoundHalfEven mode, and the Inexact flag is set.
+//
+// If b has less than 5 bytes, or if the negated scale is out of the exponent range of Quad, NaN and an InvalidOperation error are returned.
+// If the rounded result overflows, Infinity and an Overflow error are returned.
+//
+func FromCQLDecimal(b []byte) (Quad, error) {
+
+	if len(b) < 5 {
+		return NaN().SetStatusFlags(InvalidOperation), QuadError(InvalidOperation)
+	}
+
+	return FromUnscaledBytes(b[4:], int32(binary.BigEndian.Uint32(b[0:4])))
+}
+
+// ToCQLDecimal returns a as a CQL decimal, which is a big-endian int32 scale followed by the big-endian two's complement unscaled integer.
+//
+// The scale is a.Scale(), so that the conversion is exact and trailing zeros are kept. It can be negative.
+//
+//      123.45      gives   0x00 0x00 0x00 0x02 0x30 0x39
+//      1.2E+3      gives   0xFF 0xFF 0xFF 0xFE 0x0C         (12, scale -2)
+//
+// -0 gives 0, with the scale of a.
+//
+// If a is Infinite or NaN, an InvalidOperation error is returned.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToCQLDecimal() ([]byte, error) {
+
+	if !a.IsFinite() {
+		return nil, QuadError(InvalidOperation)
+	}
+
+	unscaled := coefficientBytes(C.mdq_to_coefficient(a.val))
+
+	b := make([]byte, 4, 4+len(unscaled))
+	binary.BigEndian.PutUint32(b, uint32(a.Scale()))
+
+	return append(b, unscaled...), nil
+}
@@ -0,0 +1,247 @@
+package decnum
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func Test_unscaled(t *testing.T) {
+
+	samples := []struct {
+		unscaled        int64
+		scale           int32
+		expected_result string
+		expected_status Status
+	}{
+		{12345, 2, "123.45", 0},
+		{12345, 0, "12345", 0},
+		{12345, -2, "1.2345E+6", 0},
+		{-5, 3, "-0.005", 0},
+		{0, 2, "0.00", 0},
+		{-9223372036854775808, 18, "-9.223372036854775808", 0},
+		{9223372036854775807, 6176, "9.223372036854775807E-6158", 0},
+		{9223372036854775807, 6180, "9.22337203685478E-6162", Inexact | Underflow},
+		{1, -6200, "Infinity", Overflow | Inexact},
+		{1, 20000, "0E-6176", Inexact | Underflow},
+		{-1, -20000, "-Infinity", Overflow | Inexact},
+		{1, -2147483648, "Infinity", Overflow | Inexact},
+		{0, 20000, "0E-6176", 0},
+		{0, -20000, "0E+6111", 0},
+	}
+
+	for i, sp := range samples {
+		r := FromUnscaled(sp.unscaled, sp.scale)
+
+		if r.String() != sp.expected_result || r.Status() != sp.expected_status {
+			t.Fatalf("sample %d, FromUnscaled(%d, %d): %s %s != %s %s (expected)", i, sp.unscaled, sp.scale, r, r.Status(), sp.expected_result, sp.expected_status)
+		}
+
+		if r.Status() == 0 && -sp.scale >= quadMinExponent && -sp.scale <= quadMaxExponent && r.Scale() != sp.scale { // the exponent of 0 is clamped
+			t.Fatalf("sample %d, Scale of %s: %d != %d (expected)", i, r, r.Scale(), sp.scale)
+		}
+	}
+
+	unscaled := []struct {
+		a                 string
+		scale             int32
+		rounding          RoundingMode
+		expected_unscaled int64
+		expected_error    bool
+	}{
+		{"123.456", 2, RoundHalfEven, 12346, false},
+		{"123.456", 2, RoundDown, 12345, false},
+		{"123.4", 2, RoundHalfEven, 12340, false},
+		{"-123.455", 2, RoundHalfEven, -12346, false},
+		{"-123.455", 2, RoundCeiling, -12345, false},
+		{"123456", -2, RoundHalfEven, 1235, false},
+		{"1.2E+3", 0, RoundHalfEven, 1200, false},
+		{"92233720368547758.07", 2, RoundHalfEven, 9223372036854775807, false},
+		{"92233720368547758.08", 2, RoundHalfEven, 0, true},
+		{"1", 40, RoundHalfEven, 0, true}, // coefficient would have 41 digits
+		{"Inf", 2, RoundHalfEven, 0, true},
+		{"NaN", 2, RoundHalfEven, 0, true},
+	}
+
+	for i, sp := range unscaled {
+		n, err := must_quad(sp.a).Unscaled(sp.scale, sp.rounding)
+
+		if n != sp.expected_unscaled || (err != nil) != sp.expected_error {
+			t.Fatalf("sample %d, Unscaled %s %d %s: %d %v != %d (expected)", i, sp.a, sp.scale, sp.rounding, n, err, sp.expected_unscaled)
+		}
+	}
+
+	if s := must_quad("123.450").Scale(); s != 3 {
+		t.Fatalf("Scale: %d", s)
+	}
+
+	if s := must_quad("NaN").Scale(); s != ExpNaN {
+		t.Fatalf("Scale of NaN: %d", s)
+	}
+
+	if s := must_quad("-Inf").Scale(); s != ExpInf {
+		t.Fatalf("Scale of Infinity: %d", s)
+	}
+}
+
+// javaBytes returns n as BigInteger.toByteArray does, in big-endian two's complement with the minimal number of bytes.
+//
+func javaBytes(n *big.Int) []byte {
+
+	k := n.BitLen()/8 + 1 // a sign bit is needed
+
+	if n.Sign() < 0 {
+		m := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), uint(8*k)), n)
+		b := m.FillBytes(make([]byte, k))
+		for len(b) > 1 && b[0] == 0xFF && b[1]&0x80 != 0 {
+			b = b[1:]
+		}
+		return b
+	}
+
+	return n.FillBytes(make([]byte, k))
+}
+
+func Test_unscaled_bytes(t *testing.T) {
+
+	samples := []struct {
+		unscaled        string
+		scale           int32
+		expected_result string
+		expected_status Status
+	}{
+		{"3039", 2, "123.45", 0},
+		{"cfc7", 2, "-123.45", 0},
+		{"ff", 0, "-1", 0},
+		{"0080", 0, "128", 0},
+		{"80", 0, "-128", 0},
+		{"00", 3, "0.000", 0},
+		{"0c", -2, "1.2E+3", 0},
+		{"0000000000000000000000000000000001", 0, "1", 0},
+		{"80000000000000000000000000000000", 0, "-1.701411834604692317316873037158841E+38", Inexact}, // -2^127
+		{"01ed09bead87c0378d8e63ffffffff", 4, "999999999999999999999999999999.9999", 0},              // 10^34 - 1
+		{"01ed09bead87c0378d8e6400000000", 4, "1000000000000000000000000000000.000", 0},              // 10^34
+		{"01ed09bead87c0378d8e6400000005", 0, "1.000000000000000000000000000000000E+34", Inexact},    // 10^34 + 5
+		{"01ed09bead87c0378d8e6400000005ff", 0, "2.560000000000000000000000000000002E+36", Inexact},
+		{"01", -6111, "1E+6111", 0},
+		{"01", 6176, "1E-6176", 0},
+	}
+
+	for i, sp := range samples {
+		b, _ := hex.DecodeString(sp.unscaled)
+		r, err := FromUnscaledBytes(b, sp.scale)
+
+		if err != nil || r.String() != sp.expected_result || r.Status() != sp.expected_status {
+			t.Fatalf("sample %d, FromUnscaledBytes(%s, %d): %s %s %v != %s %s (expected)", i, sp.unscaled, sp.scale, r, r.Status(), err, sp.expected_result, sp.expected_status)
+		}
+
+		// the encoding back is minimal, so the same as the input, except for the extra bytes of sample 7. It is possible only if the scale is kept.
+
+		if r.Status() == 0 && r.Scale() == sp.scale {
+			if back, err := r.UnscaledBytes(sp.scale, RoundHalfEven); err != nil || !bytes.Equal(back, javaBytes(new(big.Int).SetBytes(b))) && !bytes.Equal(back, b) {
+				t.Fatalf("sample %d, UnscaledBytes of %s: %x %v != %s (expected)", i, r, back, err, sp.unscaled)
+			}
+
+			cql, _ := r.ToCQLDecimal()
+
+			if back, err := FromCQLDecimal(cql); err != nil || back.Bytes() != r.Bytes() {
+				t.Fatalf("sample %d, CQL round trip of %s: %x %s %v", i, r, cql, back, err)
+			}
+		}
+	}
+
+	invalid := []struct {
+		unscaled        string
+		scale           int32
+		expected_result string
+		expected_error  error
+	}{
+		{"", 0, "NaN", QuadError(InvalidOperation)},
+		{"7fffffff", -6200, "NaN", QuadError(InvalidOperation)},
+		{"01", -6112, "NaN", QuadError(InvalidOperation)},
+		{"01", 6177, "NaN", QuadError(InvalidOperation)},
+		{"01", -2147483648, "NaN", QuadError(InvalidOperation)},
+		{"01", 2147483647, "NaN", QuadError(InvalidOperation)},
+		{"7fffffffffffffffffffffffffffffff", -6111, "Infinity", QuadError(Overflow)}, // 39 digits
+	}
+
+	for i, sp := range invalid {
+		b, _ := hex.DecodeString(sp.unscaled)
+		r, err := FromUnscaledBytes(b, sp.scale)
+
+		if r.String() != sp.expected_result || err != sp.expected_error {
+			t.Fatalf("invalid sample %d, FromUnscaledBytes(%s, %d): %s %v != %s %v (expected)", i, sp.unscaled, sp.scale, r, err, sp.expected_result, sp.expected_error)
+		}
+	}
+
+	if r, err := FromCQLDecimal([]byte{0, 0, 0, 2}); err == nil || !r.IsNaN() {
+		t.Fatalf("FromCQLDecimal without unscaled should fail: %s", r)
+	}
+
+	if r, err := FromCQLDecimal([]byte{0x80, 0, 0, 0, 1}); err == nil || !r.IsNaN() { // scale math.MinInt32
+		t.Fatalf("FromCQLDecimal with scale out of range should fail: %s", r)
+	}
+
+	unscaled := []struct {
+		a              string
+		scale          int32
+		rounding       RoundingMode
+		expected_bytes string
+		expected_error Status
+	}{
+		{"123.456", 2, RoundHalfEven, "303a", 0},
+		{"123.456", 2, RoundDown, "3039", 0},
+		{"-0", 2, RoundHalfEven, "00", 0},
+		{"-0.5", 0, RoundHalfEven, "00", 0},
+		{"-0.5", 0, RoundUp, "ff", 0},
+		{"1E+33", 0, RoundHalfEven, "314dc6448d9338c15b0a00000000", 0},
+		{"1E+34", 0, RoundHalfEven, "", Overflow},
+		{"1", 34, RoundHalfEven, "", Overflow},
+		{"Inf", 0, RoundHalfEven, "", InvalidOperation},
+		{"NaN", 0, RoundHalfEven, "", InvalidOperation},
+	}
+
+	for i, sp := range unscaled {
+		b, err := must_quad(sp.a).UnscaledBytes(sp.scale, sp.rounding)
+
+		if hex.EncodeToString(b) != sp.expected_bytes || (sp.expected_error == 0) != (err == nil) || (err != nil && err.(QuadError) != QuadError(sp.expected_error)) {
+			t.Fatalf("sample %d, UnscaledBytes %s %d %s: %x %v != %s %s (expected)", i, sp.a, sp.scale, sp.rounding, b, err, sp.expected_bytes, sp.expected_error)
+		}
+	}
+
+	if b, _ := must_quad("123.45").ToCQLDecimal(); hex.EncodeToString(b) != "000000023039" {
+		t.Fatalf("ToCQLDecimal 123.45: %x", b)
+	}
+
+	if b, _ := must_quad("1.2E+3").ToCQLDecimal(); hex.EncodeToString(b) != "fffffffe0c" {
+		t.Fatalf("ToCQLDecimal 1.2E+3: %x", b)
+	}
+
+	if _, err := must_quad("-Inf").ToCQLDecimal(); err == nil {
+		t.Fatalf("ToCQLDecimal of Infinity should fail")
+	}
+
+	// comparison with math/big, for coefficients of all sizes
+
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 5000; i++ {
+		n := new(big.Int).Rand(rnd, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(rnd.Intn(34)+1)), nil))
+		if rnd.Intn(2) == 0 {
+			n.Neg(n)
+		}
+		scale := int32(rnd.Intn(60) - 30)
+
+		r, err := FromUnscaledBytes(javaBytes(n), scale)
+
+		if expected := must_quad(n.String() + "E" + FromInt32(-scale).String()); err != nil || r.Bytes() != expected.Bytes() {
+			t.Fatalf("FromUnscaledBytes %s %d: %s != %s (expected)", n, scale, r, expected)
+		}
+
+		if b, err := r.UnscaledBytes(scale, RoundHalfEven); err != nil || !bytes.Equal(b, javaBytes(n)) {
+			t.Fatalf("UnscaledBytes %s %d: %x != %x (expected)", n, scale, b, javaBytes(n))
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package decnum
+
+import (
+	"strconv"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                   zoned decimal (COBOL DISPLAY)                      */
+/*                                                                      */
+/************************************************************************/
+
+// A zoned decimal field, as COBOL PIC S9(n)V9(m) DISPLAY, stores one digit per byte, as a character.
+// The field has n+m digits. The scale m is not stored in the field, it is given by the PIC clause of the record layout.
+//
+// By default, the sign is embedded in the last digit (SIGN IS TRAILING), which is "overpunched":
+//
+//      - in EBCDIC, the digits are 0xF0 to 0xF9. In the last digit, the zone nibble 0xF is replaced by 0xC (positive) or 0xD (negative).
+//      - in ASCII, the digits are '0' to '9'. The last digit is replaced by '{', 'A' to 'I' (positive 0 to 9), or '}', 'J' to 'R' (negative 0 to 9).
+//
+//      PIC S9(3)V99, EBCDIC            123.45       is    f1 f2 f3 f4 c5
+//      PIC S9(3)V99, EBCDIC            -123.45      is    f1 f2 f3 f4 d5
+//      PIC S9(3)V99, ASCII             -123.45      is    "1234N"
+//      PIC S9(3)V99, ASCII             -123.40      is    "1234}"
+//
+// The sign can also be embedded in the first digit (SIGN IS LEADING), or be a separate '+' or '-' character (SIGN IS SEPARATE), before or after the digits:
+//
+//      PIC S9(3)V99 SIGN IS LEADING SEPARATE, ASCII       -123.45      is    "-12345"
+//      PIC S9(3)V99 SIGN IS TRAILING SEPARATE, EBCDIC     123.45       is    f1 f2 f3 f4 f5 4e
+//
+// An unsigned field, PIC 9(n)V9(m), contains only digits.
+//
+// When reading, the alternate zones 0xA, 0xE and 0xF (positive) and 0xB (negative) are also accepted in EBCDIC,
+// and the plain digits '0' to '9' (positive) and 'p' to 'y' (negative 0 to 9, as written by Micro Focus COBOL) are also accepted in ASCII.
+
+// ZonedFormat is the character set of a zoned decimal field, ORed with the flags describing its sign.
+//
+//      e.g. ZonedASCII|ZonedSignLeading|ZonedSignSeparate
+//
+type ZonedFormat uint32
+
+const (
+	ZonedEBCDIC ZonedFormat = 0 // EBCDIC character set, the default
+	ZonedASCII  ZonedFormat = 1 // ASCII character set
+
+	ZonedSignLeading  ZonedFormat = 0x10 // SIGN IS LEADING. The sign is in or before the first digit, instead of the last.
+	ZonedSignSeparate ZonedFormat = 0x20 // SIGN IS SEPARATE. The sign is a '+' or '-' character, instead of being embedded in a digit.
+	ZonedUnsigned     ZonedFormat = 0x40 // unsigned field, PIC 9(n). It contains only digits, and the two flags above are ignored.
+
+	zonedCharsetMask ZonedFormat = 0x0F
+	zonedFlagsMask   ZonedFormat = ZonedSignLeading | ZonedSignSeparate | ZonedUnsigned
+)
+
+const (
+	ebcdicZero  = 0xF0 // EBCDIC '0'
+	ebcdicPlus  = 0x4E // EBCDIC '+'
+	ebcdicMinus = 0x60 // EBCDIC '-'
+
+	asciiOverpunchPositive = "{ABCDEFGHI" // ASCII overpunched digits 0 to 9, positive
+	asciiOverpunchNegative = "}JKLMNOPQR" // ASCII overpunched digits 0 to 9, negative
+)
+
+// valid returns true if the charset and the flags of f are known.
+//
+func (f ZonedFormat) valid() bool {
+
+	return f&zonedCharsetMask <= ZonedASCII && f&^(zonedCharsetMask|zonedFlagsMask) == 0
+}
+
+// zonedDigit returns the value of the unsigned digit c.
+//
+func (f ZonedFormat) zonedDigit(c byte) (digit byte, ok bool) {
+
+	if f&zonedCharsetMask == ZonedASCII {
+		if c >= '0' && c <= '9' {
+			return c - '0', true
+		}
+		return 0, false
+	}
+
+	if c >= ebcdicZero && c <= ebcdicZero+9 {
+		return c - ebcdicZero, true
+	}
+	return 0, false
+}
+
+// zonedSignedDigit returns the value and the sign of the digit c, in which the sign is embedded.
+//
+func (f ZonedFormat) zonedSignedDigit(c byte) (digit byte, negative bool, ok bool) {
+
+	if f&zonedCharsetMask == ZonedASCII {
+		switch {
+		case c >= '0' && c <= '9':
+			return c - '0', false, true
+		case c == '{':
+			return 0, false, true
+		case c >= 'A' && c <= 'I':
+			return c - 'A' + 1, false, true
+		case c == '}':
+			return 0, true, true
+		case c >= 'J' && c <= 'R':
+			return c - 'J' + 1, true, true
+		case c >= 'p' && c <= 'y':
+			return c - 'p', true, true
+		}
+		return 0, false, false
+	}
+
+	if c&0x0F > 9 {
+		return 0, false, false
+	}
+
+	switch c >> 4 {
+	case 0xA, 0xC, 0xE, 0xF:
+		return c & 0x0F, false, true
+	case 0xB, 0xD:
+		return c & 0x0F, true, true
+	}
+	return 0, false, false
+}
+
+// zonedSign returns the sign of the separate sign character c.
+//
+func (f ZonedFormat) zonedSign(c byte) (negative bool, ok bool) {
+	plus, minus := byte(ebcdicPlus), byte(ebcdicMinus)
+
+	if f&zonedCharsetMask == ZonedASCII {
+		plus, minus = '+', '-'
+	}
+
+	switch c {
+	case plus:
+		return false, true
+	case minus:
+		return true, true
+	}
+	return false, false
+}
+
+// FromZoned returns a Quad from the zoned decimal field b, with scale digits after the decimal point.
+// format is the character set, ZonedEBCDIC or ZonedASCII, ORed with the flags describing the sign.
+//
+// The conversion is exact. The exponent of the result is -scale, so that trailing zeros are kept.
+//
+//      f1 f2 f3 f4 c5     scale 2     ZonedEBCDIC                                     gives   123.45
+//      "1234N"            scale 2     ZonedASCII                                      gives   -123.45
+//      "-12345"           scale 2     ZonedASCII|ZonedSignLeading|ZonedSignSeparate   gives   -123.45
+//      f0 f0 f7           scale 0     ZonedEBCDIC|ZonedUnsigned                       gives   7
+//
+// If b contains no digit, or if a character is not valid for format, or if the value has more than 34 significant digits, NaN and an InvalidOperation error are returned.
+// An InvalidOperation error is also returned if -scale is out of the exponent range of Quad, or if format is not valid.
+//
+func FromZoned(b []byte, scale int32, format ZonedFormat) (Quad, error) {
+	var (
+		negative bool
+		ok       bool
+		digit    byte
+		sign_pos int = -1 // index of the digit containing the sign
+	)
+
+	if !format.valid() {
+		return NaN().SetStatusFlags(InvalidOperation), QuadError(InvalidOperation)
+	}
+
+	digits := b
+
+	if format&ZonedUnsigned == 0 {
+		switch {
+		case format&ZonedSignSeparate != 0 && len(b) >= 2:
+			if format&ZonedSignLeading != 0 {
+				negative, ok = format.zonedSign(b[0])
+				digits = b[1:]
+			} else {
+				negative, ok = format.zonedSign(b[len(b)-1])
+				digits = b[:len(b)-1]
+			}
+
+			if !ok {
+				return NaN().SetStatusFlags(InvalidOperation), QuadError(InvalidOperation)
+			}
+
+		case format&ZonedSignSeparate != 0:
+			digits = nil // the sign alone is not valid
+
+		case format&ZonedSignLeading != 0:
+			sign_pos = 0
+
+		default:
+			sign_pos = len(b) - 1
+		}
+	}
+
+	if len(digits) == 0 {
+		return NaN().SetStatusFlags(InvalidOperation), QuadError(InvalidOperation)
+	}
+
+	s := make([]byte, 0, len(digits)+16)
+
+	s = append(s, '+') // replaced by '-' if negative
+
+	for i, c := range digits {
+		if i == sign_pos {
+			digit, negative, ok = format.zonedSignedDigit(c)
+		} else {
+			digit, ok = format.zonedDigit(c)
+		}
+
+		if !ok {
+			return NaN().SetStatusFlags(InvalidOperation), QuadError(InvalidOperation)
+		}
+
+		s = append(s, '0'+digit)
+	}
+
+	if negative {
+		s[0] = '-'
+	}
+
+	s = append(s, 'E')
+	s = strconv.AppendInt(s, -int64(scale), 10)
+
+	r, err := FromString(string(s))
+
+	if err != nil || r.Status() != 0 || r.GetExponent() != -scale { // more than 34 significant digits, or exponent out of range
+		return NaN().SetStatusFlags(InvalidOperation), QuadError(InvalidOperation)
+	}
+
+	return r, nil
+}
+
+// ToZoned returns a as a zoned decimal field with digits digits, scale of them being after the decimal point, as COBOL PIC S9(digits-scale)V9(scale) DISPLAY.
+// format is the character set, ZonedEBCDIC or ZonedASCII, ORed with the flags describing the sign.
+// The result has digits bytes, or digits+1 bytes if the sign is separate.
+//
+// As for ToPacked, the conversion is exact, and a is not rounded. The embedded sign of a positive number is 0xC in EBCDIC, and '{' or 'A' to 'I' in ASCII.
+// A separate sign is always written, '+' or '-'. The sign of -0 is kept, except in an unsigned field.
+//
+//      123.45      digits 5, scale 2     ZonedEBCDIC                                     gives   f1 f2 f3 f4 c5
+//      -123.45     digits 5, scale 2     ZonedASCII                                      gives   "1234N"
+//      -123.45     digits 6, scale 2     ZonedASCII|ZonedSignLeading|ZonedSignSeparate   gives   "-012345"
+//      7           digits 3, scale 0     ZonedEBCDIC|ZonedUnsigned                       gives   f0 f0 f7
+//
+// If digits is not in the range 1 to 34, or if a is Infinite or NaN, or if format is not valid, an InvalidOperation error is returned.
+// An InvalidOperation error is also returned if a would be rounded, as in ToPacked, or if a is negative, except -0, and format contains ZonedUnsigned.
+// If the integral part of a doesn't fit in digits-scale digits, an Overflow error is returned.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToZoned(digits int, scale int, format ZonedFormat) ([]byte, error) {
+	var (
+		zero     byte = ebcdicZero
+		sign_pos int
+	)
+
+	if !format.valid() {
+		return nil, QuadError(InvalidOperation)
+	}
+
+	p, err := a.ToPacked(digits, scale)
+	if err != nil {
+		return nil, err
+	}
+
+	negative := p[len(p)-1]&0x0F == 0x0D
+
+	if format&zonedCharsetMask == ZonedASCII {
+		zero = '0'
+	}
+
+	b := make([]byte, digits, digits+1)
+
+	first := 2*len(p) - 1 - digits // index of the nibble of the first digit
+
+	for i := range b {
+		k := first + i
+
+		if k%2 == 0 {
+			b[i] = p[k/2] >> 4
+		} else {
+			b[i] = p[k/2] & 0x0F
+		}
+	}
+
+	if format&ZonedUnsigned != 0 {
+		for i := range b {
+			if negative && b[i] != 0 { // -0 is accepted
+				return nil, QuadError(InvalidOperation)
+			}
+			b[i] += zero
+		}
+
+		return b, nil
+	}
+
+	if format&ZonedSignSeparate != 0 {
+		sign := byte(ebcdicPlus)
+
+		switch {
+		case format&zonedCharsetMask == ZonedASCII && negative:
+			sign = '-'
+		case format&zonedCharsetMask == ZonedASCII:
+			sign = '+'
+		case negative:
+			sign = ebcdicMinus
+		}
+
+		for i := range b {
+			b[i] += zero
+		}
+
+		if format&ZonedSignLeading != 0 {
+			return append([]byte{sign}, b...), nil
+		}
+
+		return append(b, sign), nil
+	}
+
+	if format&ZonedSignLeading == 0 {
+		sign_pos = digits - 1
+	}
+
+	for i := range b {
+		switch {
+		case i != sign_pos:
+			b[i] += zero
+		case format&zonedCharsetMask == ZonedASCII && negative:
+			b[i] = asciiOverpunchNegative[b[i]]
+		case format&zonedCharsetMask == ZonedASCII:
+			b[i] = asciiOverpunchPositive[b[i]]
+		case negative:
+			b[i] |= 0xD0
+		default:
+			b[i] |= 0xC0
+		}
+	}
+
+	return b, nil
+}
@@ -0,0 +1,189 @@
+package decnum
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func Test_zoned(t *testing.T) {
+	const (
+		ASCII    = ZonedASCII
+		EBCDIC   = ZonedEBCDIC
+		LEADING  = ZonedSignLeading
+		SEPARATE = ZonedSignSeparate
+		UNSIGNED = ZonedUnsigned
+	)
+
+	samples := []struct {
+		zoned           string // hex for EBCDIC, text for ASCII
+		scale           int32
+		format          ZonedFormat
+		expected_result string
+	}{
+		{"f1f2f3f4c5", 2, EBCDIC, "123.45"},
+		{"f1f2f3f4d5", 2, EBCDIC, "-123.45"},
+		{"f1f2f3f4f5", 2, EBCDIC, "123.45"},
+		{"f1f2f3f4a5", 2, EBCDIC, "123.45"},
+		{"f1f2f3f4e5", 2, EBCDIC, "123.45"},
+		{"f1f2f3f4b5", 2, EBCDIC, "-123.45"},
+		{"d1f2f3f4f5", 2, EBCDIC | LEADING, "-123.45"},
+		{"f1f2f3f4f54e", 2, EBCDIC | SEPARATE, "123.45"},
+		{"60f1f2f3f4f5", 2, EBCDIC | LEADING | SEPARATE, "-123.45"},
+		{"f0f0f7", 0, EBCDIC | UNSIGNED, "7"},
+		{"f0f0f7", -3, EBCDIC | UNSIGNED, "7E+3"},
+		{"c0", 0, EBCDIC, "0"},
+		{"1234N", 2, ASCII, "-123.45"},
+		{"1234E", 2, ASCII, "123.45"},
+		{"1234}", 2, ASCII, "-123.40"},
+		{"1234{", 2, ASCII, "123.40"},
+		{"12345", 2, ASCII, "123.45"},
+		{"1234u", 2, ASCII, "-123.45"},
+		{"J2345", 2, ASCII | LEADING, "-123.45"},
+		{"-12345", 2, ASCII | LEADING | SEPARATE, "-123.45"},
+		{"12345+", 2, ASCII | SEPARATE, "123.45"},
+		{"00123", 0, ASCII | UNSIGNED, "123"},
+		{"0000000000999999999999999999999999999999999R", 0, ASCII, "-9999999999999999999999999999999999"},
+		{"1{", 6175, ASCII, "1.0E-6174"},
+	}
+
+	for i, sp := range samples {
+		b := []byte(sp.zoned)
+
+		if sp.format&ZonedASCII == 0 {
+			b, _ = hex.DecodeString(sp.zoned)
+		}
+
+		r, err := FromZoned(b, sp.scale, sp.format)
+
+		if err != nil || r.String() != sp.expected_result {
+			t.Fatalf("sample %d, FromZoned(%s, %d, %#x): %s %v != %s (expected)", i, sp.zoned, sp.scale, sp.format, r, err, sp.expected_result)
+		}
+	}
+
+	invalid := []struct {
+		zoned  string
+		scale  int32
+		format ZonedFormat
+	}{
+		{"", 0, EBCDIC},
+		{"", 0, ASCII | UNSIGNED},
+		{"+", 0, ASCII | SEPARATE},
+		{"f1c2", 0, EBCDIC | UNSIGNED},                    // sign in an unsigned field
+		{"c1f2", 0, EBCDIC},                               // sign in the first digit
+		{"f1fa", 0, EBCDIC},                               // bad digit
+		{"f191", 0, EBCDIC},                               // bad zone
+		{"f1f24e", 0, EBCDIC | LEADING | SEPARATE},        // sign at the end
+		{"4ef1f2", 0, EBCDIC | SEPARATE},                  // sign at the beginning
+		{"f1f22b", 0, EBCDIC | SEPARATE},                  // ASCII sign
+		{"12-", 0, ASCII | LEADING | SEPARATE},            // sign at the end
+		{"1 2", 0, ASCII},                                 // space
+		{"1S", 0, ASCII},                                  // bad overpunch
+		{"N2", 0, ASCII},                                  // overpunch in the first digit
+		{"12N", 0, ASCII | UNSIGNED},                      // sign in an unsigned field
+		{"12345678901234567890123456789012345", 0, ASCII}, // 35 significant digits
+		{"1", 6177, ASCII},                                // exponent out of range
+		{"1", -6112, ASCII},                               // exponent out of range
+		{"1", -2147483648, ASCII},                         // exponent out of range
+		{"1", 0, ZonedFormat(2)},                          // bad charset
+		{"1", 0, ASCII | 0x100},                           // bad flag
+	}
+
+	for i, sp := range invalid {
+		b := []byte(sp.zoned)
+
+		if sp.format&ZonedASCII == 0 {
+			b, _ = hex.DecodeString(sp.zoned)
+		}
+
+		r, err := FromZoned(b, sp.scale, sp.format)
+
+		if err == nil || !r.IsNaN() || r.Status() != InvalidOperation {
+			t.Fatalf("invalid sample %d, FromZoned(%s, %d, %#x) should fail: %s %s", i, sp.zoned, sp.scale, sp.format, r, r.Status())
+		}
+	}
+
+	to_zoned := []struct {
+		a               string
+		digits          int
+		scale           int
+		format          ZonedFormat
+		expected_result string // hex for EBCDIC, text for ASCII
+		expected_error  error
+	}{
+		{"123.45", 5, 2, EBCDIC, "f1f2f3f4c5", nil},
+		{"-123.45", 5, 2, EBCDIC, "f1f2f3f4d5", nil},
+		{"-123.45", 5, 2, EBCDIC | LEADING, "d1f2f3f4f5", nil},
+		{"123.45", 5, 2, EBCDIC | SEPARATE, "f1f2f3f4f54e", nil},
+		{"-123.45", 5, 2, EBCDIC | LEADING | SEPARATE, "60f1f2f3f4f5", nil},
+		{"7", 3, 0, EBCDIC | UNSIGNED, "f0f0f7", nil},
+		{"-123.45", 5, 2, ASCII, "1234N", nil},
+		{"123.45", 5, 2, ASCII, "1234E", nil},
+		{"-123.4", 5, 2, ASCII, "1234}", nil},
+		{"123.4", 5, 2, ASCII, "1234{", nil},
+		{"-123.45", 6, 2, ASCII | LEADING | SEPARATE, "-012345", nil},
+		{"123.45", 5, 2, ASCII | SEPARATE, "12345+", nil},
+		{"-1", 3, 0, ASCII | LEADING, "}01", nil},
+		{"1.00", 3, 2, ASCII | UNSIGNED, "100", nil},
+		{"-0.00", 3, 2, ASCII | UNSIGNED, "000", nil},
+		{"-0.00", 3, 2, ASCII, "00}", nil},
+		{"1.005", 3, 2, ASCII | UNSIGNED, "", QuadError(InvalidOperation)}, // would be rounded
+		{"-0.001", 3, 2, ASCII, "", QuadError(InvalidOperation)},
+		{"-1", 3, 0, ASCII | UNSIGNED, "", QuadError(InvalidOperation)},
+		{"1234", 3, 0, ASCII, "", QuadError(Overflow)},
+		{"1", 35, 0, ASCII, "", QuadError(InvalidOperation)},
+		{"NaN", 5, 0, ASCII, "", QuadError(InvalidOperation)},
+		{"1", 5, 0, ZonedFormat(7), "", QuadError(InvalidOperation)},
+	}
+
+	for i, sp := range to_zoned {
+		b, err := must_quad(sp.a).ToZoned(sp.digits, sp.scale, sp.format)
+
+		result := string(b)
+		if sp.format&ZonedASCII == 0 {
+			result = hex.EncodeToString(b)
+		}
+
+		if result != sp.expected_result || err != sp.expected_error {
+			t.Fatalf("sample %d, ToZoned(%s, %d, %d, %#x): %s %v != %s %v (expected)", i, sp.a, sp.digits, sp.scale, sp.format, result, err, sp.expected_result, sp.expected_error)
+		}
+	}
+
+	// round trip of random values, in all formats
+
+	formats := []ZonedFormat{EBCDIC, EBCDIC | LEADING, EBCDIC | SEPARATE, EBCDIC | LEADING | SEPARATE, EBCDIC | UNSIGNED, ASCII, ASCII | LEADING, ASCII | SEPARATE, ASCII | LEADING | SEPARATE, ASCII | UNSIGNED}
+
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		format := formats[rng.Intn(len(formats))]
+		digits := 1 + rng.Intn(DecquadPmax)
+		scale := rng.Intn(digits + 1)
+
+		s := ""
+		for j := 0; j < digits; j++ {
+			s += strconv.Itoa(rng.Intn(10))
+		}
+		if rng.Intn(2) == 0 && format&ZonedUnsigned == 0 {
+			s = "-" + s
+		}
+
+		a := must_quad(s + "E-" + strconv.Itoa(scale))
+
+		b, err := a.ToZoned(digits, scale, format)
+		if err != nil {
+			t.Fatalf("ToZoned(%s, %d, %d, %#x): %v", a, digits, scale, format, err)
+		}
+
+		r, err := FromZoned(b, int32(scale), format)
+		if err != nil || r.Bytes() != a.Bytes() {
+			t.Fatalf("round trip of %s, digits %d, format %#x: %x gives %s %v", a, digits, format, b, r, err)
+		}
+
+		if b2, _ := r.ToZoned(digits, scale, format); !bytes.Equal(b, b2) {
+			t.Fatalf("round trip of %x: %x", b, b2)
+		}
+	}
+}
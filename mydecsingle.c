@@ -0,0 +1,118 @@
+#include "mydecquad.h"
+
+
+/************************************************************************/
+/*                                                                      */
+/*     decSingle is only a storage format. The library has no           */
+/*     arithmetic for it, so values are widened to operate on them.     */
+/*                                                                      */
+/************************************************************************/
+
+
+/* conversion from string.
+
+   The coefficient is rounded to 7 digits if necessary.
+*/
+Single mds_from_string(char *s) {
+  decContext  set;
+  Single      res;
+
+  decContextDefault(&set, DEC_INIT_DECSINGLE);
+
+  decSingleFromString(&res.val, s, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* widening to decDouble. It is always exact.
+*/
+Double mds_to_double(Single a) {
+  Double      res;
+
+  decSingleToWider(&a.val, &res.val);
+  res.status = a.status;
+
+  return res;
+}
+
+
+/* widening to decQuad. It is always exact.
+*/
+Quad mds_to_quad(Single a) {
+  decDouble   d;
+  Quad        res;
+
+  decSingleToWider(&a.val, &d);
+  decDoubleToWider(&d, &res.val);
+  res.status = a.status;
+
+  return res;
+}
+
+
+/* narrowing from decDouble.
+
+   The coefficient is rounded to 7 digits with DEC_ROUND_HALF_EVEN. Inexact, Overflow or Underflow flags are set if necessary.
+*/
+Single mds_from_double(Double a) {
+  decContext  set;
+  Single      res;
+
+  decContextDefault(&set, DEC_INIT_DECSINGLE);
+  set.status = a.status;
+
+  decSingleFromWider(&res.val, &a.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* narrowing from decQuad.
+
+   decSingleFromWider() only accepts a decDouble, and narrowing first to decDouble would round twice.
+   So, the value is converted through decNumber, which rounds once to 7 digits.
+*/
+Single mds_from_quad(Quad a) {
+  decContext  set;
+  Single      res;
+  decNumber   a_num;   // DECNUMDIGITS is DECIMAL128_Pmax, defined in decimal128.h
+
+  decContextDefault(&set, DEC_INIT_DECSINGLE);
+  set.status = a.status;
+
+  decQuadToNumber(&a.val, &a_num);
+
+  decSingleFromNumber(&res.val, &a_num, &set);
+  res.status = decContextGetStatus(&set) & ~(DEC_Rounded | DEC_Subnormal | DEC_Clamped);   // decNumber sets these flags, decSingleFromWider() doesn't
+
+  return res;
+}
+
+
+
+/************************************************************************/
+/*                             encoding                                 */
+/************************************************************************/
+
+
+/* check if the encoding of a is canonical.
+
+   decSingle has no decSingleIsCanonical function. Widening copies the declets as they are, so a is widened,
+   made canonical, and narrowed back, which is exact. The result is the same as a only if a is canonical.
+*/
+uint32_t mds_is_canonical(decSingle a) {
+  decContext  set;
+  decDouble   d;
+  decSingle   c;
+
+  decContextDefault(&set, DEC_INIT_DECSINGLE);
+
+  decSingleToWider(&a, &d);
+  decDoubleCanonical(&d, &d);
+  decSingleFromWider(&c, &d, &set);
+
+  return memcmp(&a, &c, sizeof(decSingle)) == 0;
+}
@@ -0,0 +1,278 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+import (
+	"encoding/binary"
+	"strings"
+	"unsafe"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                          Single value and status                     */
+/*                                                                      */
+/************************************************************************/
+
+// Single contains a 32bits decimal floating-point value, with 7 digits of precision, and a status like Quad.
+//
+// Single is a storage and interchange format: the value needs only 4 bytes. There is no arithmetic on Single.
+// Convert it to Double or Quad with ToDouble or ToQuad, which are always exact, and convert the result back with Quad.ToSingle or Double.ToSingle.
+//
+// The exponent range is [-101, 90], so numbers are in the range 1E-101 (subnormal) to 9.999999E+96.
+//
+type Single C.Single // array of 4 bytes, + 2 bytes for status field
+
+const (
+	DecsinglePmax   = C.DECSINGLE_Pmax   // number of digits in coefficient == 7
+	DecsingleBytes  = C.DECSINGLE_Bytes  // size in bytes of decSingle == 4
+	DecsingleString = C.DECSINGLE_String // buffer capacity for C.decSingleToString()
+)
+
+// Status returns the status field of the Single.
+// It contains error flags, like 'Overflow', and informational flags, like 'Inexact'.
+//
+func (a Single) Status() Status {
+
+	return Status(a.status)
+}
+
+// Error returns an error if an error flag bit has been set in Single's status field.
+//
+// It returns QuadError(a.Status()&ErrorMask), or nil if no error.
+//
+func (a Single) Error() error {
+	var errorFlags Status
+
+	errorFlags = Status(a.status) & ErrorMask
+
+	if errorFlags == 0 {
+		return nil
+	}
+
+	return QuadError(errorFlags)
+}
+
+// ClearStatus returns a copy of a, whith status field cleared.
+//
+func (a Single) ClearStatus() Single {
+
+	a.status = 0
+	return a
+}
+
+// SingleFromString returns a Single from a string.
+//
+// The accepted strings are the same as for FromString.
+// If the number has more than 7 digits, it is rounded with RoundHalfEven, and the Inexact flag is set.
+// If it is too large, the result is Infinity, and the Overflow flag is set.
+//
+// This function returns result.Error() as a convenience.
+//
+func SingleFromString(s string) (result Single, err error) {
+	var cs *C.char
+
+	s = strings.TrimSpace(s)
+
+	cs = C.CString(s)
+	defer C.free(unsafe.Pointer(cs))
+
+	result = Single(C.mds_from_string(cs))
+
+	return result, result.Error()
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                    conversion to and from Double and Quad            */
+/*                                                                      */
+/************************************************************************/
+
+// ToDouble returns the value of a as a Double.
+//
+// The conversion is always exact. The status of a is kept.
+//
+func (a Single) ToDouble() Double {
+
+	return Double(C.mds_to_double(C.struct_Single(a)))
+}
+
+// ToQuad returns the value of a as a Quad.
+//
+// The conversion is always exact. The status of a is kept.
+//
+func (a Single) ToQuad() Quad {
+
+	return Quad(C.mds_to_quad(C.struct_Single(a)))
+}
+
+// ToSingle returns the value of a as a Single.
+//
+// The coefficient is rounded to 7 digits with RoundHalfEven, and the Inexact flag is set if the value has changed.
+// If a is too large for a Single, the result is Infinity and the Overflow flag is set.
+// If a is too small, the result is a subnormal number or 0, and the Underflow flag is set.
+//
+func (a Double) ToSingle() Single {
+
+	return Single(C.mds_from_double(C.struct_Double(a)))
+}
+
+// ToSingle returns the value of a as a Single.
+//
+// The coefficient is rounded once to 7 digits with RoundHalfEven, and the Inexact flag is set if the value has changed.
+// If a is too large for a Single, the result is Infinity and the Overflow flag is set.
+// If a is too small, the result is a subnormal number or 0, and the Underflow flag is set.
+//
+//      3.14159265               gives   3.141593                    Inexact
+//      1E+97                    gives   Infinity                    Overflow|Inexact
+//
+func (a Quad) ToSingle() Single {
+
+	return Single(C.mds_from_quad(C.struct_Quad(a)))
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                      conversion to string                            */
+/*                                                                      */
+/************************************************************************/
+
+// SingleToString returns the string representation of a Single number.
+// It uses exponential notation as often as Quad.QuadToString.
+//
+func (a Single) SingleToString() string {
+
+	return a.ToQuad().QuadToString()
+}
+
+// AppendSingle appends string representation of Single into byte slice.
+// The number is written as AppendQuad would write it.
+//
+func AppendSingle(dst []byte, a Single) []byte {
+
+	return AppendQuad(dst, a.ToQuad())
+}
+
+// String is the preferred way to display a Single number.
+// It calls AppendSingle internally.
+//
+func (a Single) String() string {
+	var buffer []byte
+
+	buffer = pool.Get().([]byte)[:0]
+	defer pool.Put(buffer)
+
+	ss := AppendSingle(buffer[:0], a)
+
+	return string(ss)
+}
+
+// Bytes returns the internal byte representation of the value field of the Single.
+//
+func (a Single) Bytes() (res [DecsingleBytes]byte) {
+
+	for i, b := range a.val {
+		res[i] = byte(b)
+	}
+
+	return res
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                  decimal32 interchange encodings                     */
+/*                                                                      */
+/************************************************************************/
+
+// The value of a Single is an IEEE 754 decimal32 number, in DPD encoding. ToDPD and ToBID return it in the DPD or BID encoding.
+// The bytes are in big-endian order, the first byte containing the sign bit:
+//
+//      1            is    22 50 00 01 in DPD
+//      1            is    32 80 00 01 in BID
+//
+// A Quad or a Double is converted with ToSingle, which sets the Inexact or Overflow flag in the status of the Single if the value doesn't fit.
+
+// IsCanonical returns true if the encoding of a is canonical.
+//
+func (a Single) IsCanonical() bool {
+
+	if C.mds_is_canonical(a.val) != 0 {
+		return true
+	}
+
+	return false
+}
+
+// ToDPD returns the DPD encoding of a, in big-endian order.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Single) ToDPD() (b [DecsingleBytes]byte) {
+
+	bytes := a.Bytes()
+
+	for i := range b {
+		if g_little_endian {
+			b[i] = bytes[DecsingleBytes-1-i]
+		} else {
+			b[i] = bytes[i]
+		}
+	}
+
+	return b
+}
+
+// SingleFromDPD returns a Single from its DPD encoding, in big-endian order.
+//
+// If the encoding of b is not canonical, NaN and an InvalidOperation error are returned.
+// On success, the status of the result is 0.
+//
+func SingleFromDPD(b [DecsingleBytes]byte) (Single, error) {
+	var r Single
+
+	for i := range r.val {
+		if g_little_endian {
+			r.val[i] = b[DecsingleBytes-1-i]
+		} else {
+			r.val[i] = b[i]
+		}
+	}
+
+	if !r.IsCanonical() {
+		return NaN().SetStatusFlags(InvalidOperation).ToSingle(), QuadError(InvalidOperation)
+	}
+
+	return r, nil
+}
+
+// ToBID returns the BID encoding of a, in big-endian order.
+//
+//      9999999      gives   6c b8 96 7f
+//
+// The conversion is always exact.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Single) ToBID() (b [DecsingleBytes]byte) {
+
+	binary.BigEndian.PutUint32(b[:], uint32(g_bid32.encode(a.ToQuad())))
+
+	return b
+}
+
+// SingleFromBID returns a Single from its BID encoding, in big-endian order.
+//
+// All encodings are accepted, and the result is exact.
+// As required by IEEE 754, a non-canonical coefficient, larger than 10^7 - 1, is replaced by 0, keeping the sign and the exponent.
+// A NaN payload larger than 10^6 - 1 is replaced by 0.
+//
+func SingleFromBID(b [DecsingleBytes]byte) Single {
+
+	return g_bid32.decode(uint64(binary.BigEndian.Uint32(b[:]))).ToSingle()
+}
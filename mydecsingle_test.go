@@ -0,0 +1,159 @@
+package decnum
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func Test_single_conversions(t *testing.T) {
+
+	samples := []struct {
+		s               string
+		expected_result string
+		expected_status Status
+	}{
+		{"12.34", "12.34", 0},
+		{"1234567", "1234567", 0},
+		{"3.14159265", "3.141593", Inexact},
+		{"12345675", "1.234568E+7", Inexact},
+		{"12345665", "1.234566E+7", Inexact},
+		{"-0.000", "0.000", 0},
+		{"9.999999E+96", "9.999999E+96", 0},
+		{"9.9999995E+96", "Infinity", Overflow | Inexact},
+		{"1E+97", "Infinity", Overflow | Inexact},
+		{"1E-101", "1E-101", 0},
+		{"1.5E-101", "2E-101", Underflow | Inexact},
+		{"1E-102", "0E-101", Underflow | Inexact},
+		{"1E-399", "0E-101", Underflow | Inexact},
+		{"1.5E-383", "0E-101", Underflow | Inexact},
+		{"NaN", "NaN", 0},
+		{"-Inf", "-Infinity", 0},
+		{"hello", "NaN", ConversionSyntax},
+	}
+
+	for i, sp := range samples {
+		q, _ := FromString(sp.s)
+
+		r := q.ToSingle()
+		if r.String() != sp.expected_result || r.Status() != sp.expected_status {
+			t.Fatalf("sample %d, Quad.ToSingle %s: %s %s != %s %s (expected)", i, sp.s, r, r.Status(), sp.expected_result, sp.expected_status)
+		}
+
+		r, _ = SingleFromString(sp.s)
+		if r.String() != sp.expected_result || r.Status() != sp.expected_status {
+			t.Fatalf("sample %d, SingleFromString %s: %s %s != %s %s (expected)", i, sp.s, r, r.Status(), sp.expected_result, sp.expected_status)
+		}
+
+		if r.Error() == nil {
+			if w := r.ToDouble(); w.String() != r.String() || w.ToSingle().Bytes() != r.Bytes() || w.Status() != r.Status() {
+				t.Fatalf("sample %d, round trip through Double %s: %s", i, sp.s, w)
+			}
+
+			if w := r.ToQuad(); w.String() != r.String() || w.ToSingle().Bytes() != r.Bytes() || w.Status() != r.Status() {
+				t.Fatalf("sample %d, round trip through Quad %s: %s", i, sp.s, w)
+			}
+		}
+	}
+
+	// Quad to Single rounds only once: 1.23456749999999999 would give 1.234568 if rounded to 16 digits first.
+
+	if r := must_quad("1.23456749999999999").ToSingle(); r.String() != "1.234567" || r.Status() != Inexact {
+		t.Fatalf("Quad.ToSingle 1.23456749999999999: %s %s", r, r.Status())
+	}
+
+	if r := must_double("1.234567499999999").ToSingle(); r.String() != "1.234567" || r.Status() != Inexact {
+		t.Fatalf("Double.ToSingle 1.234567499999999: %s %s", r, r.Status())
+	}
+
+	if r := must_quad("1.5").SetStatusFlags(DivisionByZero).ToSingle(); r.Error() == nil || r.ClearStatus().Status() != 0 {
+		t.Fatalf("status propagation: %s %s", r, r.Status())
+	}
+
+	if s := must_quad("1E-7").ToSingle().SingleToString(); s != "1E-7" {
+		t.Fatalf("SingleToString: %s", s)
+	}
+
+	if s := string(AppendSingle([]byte("x = "), must_quad("1E-7").ToSingle())); s != "x = 0.0000001" {
+		t.Fatalf("AppendSingle: %s", s)
+	}
+}
+
+func Test_single_encodings(t *testing.T) {
+
+	samples := []struct {
+		a            string
+		expected_dpd string
+		expected_bid string
+	}{
+		{"1", "22500001", "32800001"},
+		{"-12.345", "a22049c5", "b1003039"},
+		{"-0", "a2500000", "b2800000"},
+		{"9999999", "6e53fcff", "6cb8967f"},
+		{"9.999999E+96", "77f3fcff", "77f8967f"},
+		{"1E-101", "00000001", "00000001"},
+		{"Inf", "78000000", "78000000"},
+		{"sNaN12", "7e000012", "7e00000c"},
+	}
+
+	for i, sp := range samples {
+		a, _ := SingleFromString(sp.a)
+
+		dpd := a.ToDPD()
+		bid := a.ToBID()
+
+		if hex.EncodeToString(dpd[:]) != sp.expected_dpd || hex.EncodeToString(bid[:]) != sp.expected_bid {
+			t.Fatalf("sample %d, %s: %x %x != %s %s (expected)", i, sp.a, dpd, bid, sp.expected_dpd, sp.expected_bid)
+		}
+
+		if r, err := SingleFromDPD(dpd); err != nil || r.Bytes() != a.Bytes() {
+			t.Fatalf("sample %d, SingleFromDPD %x: %s %v", i, dpd, r, err)
+		}
+
+		if r := SingleFromBID(bid); r.Bytes() != a.Bytes() || r.Status() != 0 {
+			t.Fatalf("sample %d, SingleFromBID %x: %s %s", i, bid, r, r.Status())
+		}
+	}
+
+	// non-canonical encodings
+
+	for _, s := range []string{"225003ff", "78000001", "7c0003ff"} {
+		var b [4]byte
+
+		hex.Decode(b[:], []byte(s))
+
+		if r, err := SingleFromDPD(b); err == nil || !r.ToQuad().IsNaN() || r.Status() != InvalidOperation {
+			t.Fatalf("SingleFromDPD of non-canonical %s should fail: %s %s", s, r, r.Status())
+		}
+	}
+
+	if r := SingleFromBID([4]byte{0x6c, 0xb8, 0x96, 0x80}); r.String() != "0" { // coefficient 10^7
+		t.Fatalf("SingleFromBID of non-canonical coefficient: %s", r)
+	}
+
+	// checked narrowing from Quad
+
+	r := must_quad("3.14159265").ToSingle()
+
+	if bid := r.ToBID(); hex.EncodeToString(bid[:]) != "2fafefd9" || r.Status() != Inexact {
+		t.Fatalf("narrowing: %x %s", bid, r.Status())
+	}
+
+	// round trip of random values
+
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		s := strconv.Itoa(rnd.Intn(1e7)>>uint(rnd.Intn(24))) + "E" + strconv.Itoa(rnd.Intn(192)-101)
+		a, _ := SingleFromString(s)
+
+		if r := SingleFromBID(a.ToBID()); r.Bytes() != a.Bytes() {
+			t.Fatalf("BID round trip of %s: %s", s, r)
+		}
+
+		if r, err := SingleFromDPD(a.ToDPD()); err != nil || r.Bytes() != a.Bytes() {
+			t.Fatalf("DPD round trip of %s: %s", s, r)
+		}
+	}
+}
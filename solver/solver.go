@@ -0,0 +1,534 @@
+/*
+Package solver finds a root of a function of one Quad variable, that is, a value x such that f(x) == 0.
+
+The following methods are available:
+
+	Bisection              needs an interval [a, b] where f changes sign. Slow, but always converges.
+	Newton                 needs a starting point, and optionally the derivative of f. Fast, but may diverge.
+	Brent                  needs an interval [a, b] where f changes sign. Fast, and always converges.
+
+The required precision is given in Options, either as a number of significant digits, or as an absolute Quad tolerance.
+The number of iterations is limited by Options.MaxIterations.
+
+Each method returns a Result, which contains the root, the number of iterations and the achieved precision.
+If the method fails, the Result contains the last approximation of the root, and the error is a SolverError:
+
+	NotBracketed           f(a) and f(b) have the same sign
+	NoConvergence          the maximum number of iterations has been reached
+	ZeroDerivative         the derivative of f is 0, for the Newton method
+	InvalidValue           f returned NaN or a Quad with an error status, or an argument is not finite
+	InvalidTolerance       the tolerance in Options is negative or not finite
+*/
+package solver
+
+import (
+	"strconv"
+
+	"github.com/rin01/decnum"
+)
+
+// Func is a function of one Quad variable.
+//
+type Func func(x decnum.Quad) decnum.Quad
+
+const (
+	DefaultDigits        = 32  // default number of significant digits of the root, if Options is empty
+	DefaultMaxIterations = 500 // default maximum number of iterations, if Options.MaxIterations is 0
+)
+
+// Options contains the required precision of the root, and the maximum number of iterations.
+//
+// If Digits is not 0, the root is accurate to Digits significant digits.
+// Else, if Absolute is not 0, the absolute error on the root is at most Absolute.
+// Else, the root is accurate to DefaultDigits significant digits.
+//
+// The zero value of Options is ready to use.
+//
+type Options struct {
+	Digits        int         // required number of significant digits of the root, between 1 and 34
+	Absolute      decnum.Quad // required absolute precision of the root, used if Digits is 0
+	MaxIterations int         // maximum number of iterations. If 0, DefaultMaxIterations is used.
+}
+
+// Result is returned by the solver methods.
+//
+type Result struct {
+	Root       decnum.Quad // the root, or the last approximation of the root if the method failed
+	Iterations int         // number of iterations performed
+	Precision  decnum.Quad // estimation of the absolute error on Root. It is 0 if f(Root) is exactly 0.
+	Digits     int         // estimation of the number of significant digits of Root
+}
+
+// SolverError is the error type returned by this package.
+//
+type SolverError int
+
+const (
+	NotBracketed     SolverError = iota + 1 // f(a) and f(b) have the same sign
+	NoConvergence                           // the maximum number of iterations has been reached
+	ZeroDerivative                          // the derivative of f is 0
+	InvalidValue                            // f returned NaN or a Quad with an error status, or an argument is not finite
+	InvalidTolerance                        // the tolerance in Options is invalid
+)
+
+// Error returns a string describing the error.
+//
+func (e SolverError) Error() string {
+
+	switch e {
+	case NotBracketed:
+		return "solver: root is not bracketed, f(a) and f(b) have the same sign"
+	case NoConvergence:
+		return "solver: no convergence, maximum number of iterations reached"
+	case ZeroDerivative:
+		return "solver: derivative is zero"
+	case InvalidValue:
+		return "solver: invalid value, function returned NaN or an error status"
+	case InvalidTolerance:
+		return "solver: invalid tolerance"
+	default:
+		return "solver: unknown error " + strconv.Itoa(int(e))
+	}
+}
+
+var (
+	g_half = decnum.MustParse("0.5")
+	g_two  = decnum.MustParse("2")
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                           tolerance                                  */
+/*                                                                      */
+/************************************************************************/
+
+// tolerance computes the absolute tolerance for a root near x.
+//
+type tolerance struct {
+	relative bool        // if true, tol(x) is |x|*eps. Else, it is eps.
+	eps      decnum.Quad // 1E-digits, or absolute tolerance
+	maxIter  int
+}
+
+// newTolerance checks the options, and returns the tolerance to use.
+//
+func newTolerance(opts Options) (tolerance, error) {
+	var tol tolerance
+
+	tol.maxIter = opts.MaxIterations
+	if tol.maxIter <= 0 {
+		tol.maxIter = DefaultMaxIterations
+	}
+
+	switch {
+	case opts.Digits != 0:
+		if opts.Digits < 1 || opts.Digits > decnum.DecquadPmax {
+			return tol, InvalidTolerance
+		}
+		tol.relative = true
+		tol.eps = decnum.MustParse("1E-" + strconv.Itoa(opts.Digits))
+
+	case !opts.Absolute.IsZero():
+		if !opts.Absolute.IsFinite() || opts.Absolute.IsNegative() || opts.Absolute.Error() != nil {
+			return tol, InvalidTolerance
+		}
+		tol.eps = opts.Absolute
+
+	default:
+		tol.relative = true
+		tol.eps = decnum.MustParse("1E-" + strconv.Itoa(DefaultDigits))
+	}
+
+	return tol, nil
+}
+
+// of returns the absolute tolerance for a root near x.
+//
+func (tol tolerance) of(x decnum.Quad) decnum.Quad {
+
+	if tol.relative {
+		return x.Abs().Mul(tol.eps)
+	}
+
+	return tol.eps
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                           helper functions                           */
+/*                                                                      */
+/************************************************************************/
+
+// valid returns true if x is finite and has no error status.
+//
+func valid(x decnum.Quad) bool {
+
+	return x.IsFinite() && x.Error() == nil
+}
+
+// eval computes f(x), and returns InvalidValue if the result is not valid.
+//
+func eval(f Func, x decnum.Quad) (decnum.Quad, error) {
+
+	fx := f(x)
+
+	if !valid(fx) {
+		return fx, InvalidValue
+	}
+
+	return fx, nil
+}
+
+// sameSign returns true if a and b are both positive or both negative.
+//
+func sameSign(a decnum.Quad, b decnum.Quad) bool {
+
+	return (a.IsPositive() && b.IsPositive()) || (a.IsNegative() && b.IsNegative())
+}
+
+// adjustedExponent returns the exponent of the most significant digit of a. a must be finite and not 0.
+//
+//      123.45     gives   2
+//      0.0012     gives   -3
+//
+func adjustedExponent(a decnum.Quad) int {
+
+	bcd, exp, _, _ := a.ToBCD()
+
+	for i, d := range bcd {
+		if d != 0 {
+			return int(exp) + len(bcd) - 1 - i
+		}
+	}
+
+	return int(exp)
+}
+
+// newResult fills a Result, computing the number of significant digits from root and precision.
+//
+func newResult(root decnum.Quad, iterations int, precision decnum.Quad) Result {
+	var digits int
+
+	precision = precision.Abs()
+
+	switch {
+	case precision.IsZero():
+		digits = decnum.DecquadPmax
+	case root.IsZero():
+		digits = 0
+	default:
+		digits = adjustedExponent(root) - adjustedExponent(precision)
+		if digits < 0 {
+			digits = 0
+		}
+		if digits > decnum.DecquadPmax {
+			digits = decnum.DecquadPmax
+		}
+	}
+
+	return Result{Root: root, Iterations: iterations, Precision: precision, Digits: digits}
+}
+
+// checkInterval evaluates f at a and b, and checks that the root is bracketed.
+//
+func checkInterval(f Func, a decnum.Quad, b decnum.Quad) (fa decnum.Quad, fb decnum.Quad, err error) {
+
+	if !valid(a) || !valid(b) {
+		return fa, fb, InvalidValue
+	}
+
+	if fa, err = eval(f, a); err != nil {
+		return fa, fb, err
+	}
+
+	if fb, err = eval(f, b); err != nil {
+		return fa, fb, err
+	}
+
+	if sameSign(fa, fb) {
+		return fa, fb, NotBracketed
+	}
+
+	return fa, fb, nil
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                              methods                                 */
+/*                                                                      */
+/************************************************************************/
+
+// Bisection finds a root of f in the interval [a, b], by bisection.
+//
+// f(a) and f(b) must have opposite signs, or be 0.
+// The interval is halved at each iteration, so about 3.3 iterations are needed for each digit of precision.
+//
+// If the interval contains 0, it is first split at 0 instead of its middle, as a relative tolerance cannot be reached by halving an interval around 0.
+//
+// If the interval cannot be halved anymore, because its bounds are consecutive Quad numbers, the method stops and returns the best bound.
+//
+func Bisection(f Func, a decnum.Quad, b decnum.Quad, opts Options) (Result, error) {
+	var (
+		tol tolerance
+		fa  decnum.Quad
+		fb  decnum.Quad
+		err error
+	)
+
+	if tol, err = newTolerance(opts); err != nil {
+		return Result{Root: a}, err
+	}
+
+	if fa, fb, err = checkInterval(f, a, b); err != nil {
+		return Result{Root: a}, err
+	}
+
+	if fa.IsZero() {
+		return newResult(a, 0, decnum.Zero()), nil
+	}
+	if fb.IsZero() {
+		return newResult(b, 0, decnum.Zero()), nil
+	}
+
+	for i := 1; i <= tol.maxIter; i++ {
+		mid := a.Add(b).Mul(g_half)
+
+		if !a.IsZero() && !b.IsZero() && !sameSign(a, b) { // the interval contains 0
+			mid = decnum.Zero()
+		}
+
+		if mid.Equal(a) || mid.Equal(b) { // a and b are consecutive Quad numbers
+			if fa.Abs().Less(fb.Abs()) {
+				return newResult(a, i, b.Sub(a)), nil
+			}
+			return newResult(b, i, b.Sub(a)), nil
+		}
+
+		fmid, err := eval(f, mid)
+		if err != nil {
+			return newResult(mid, i, b.Sub(a)), err
+		}
+
+		if fmid.IsZero() {
+			return newResult(mid, i, decnum.Zero()), nil
+		}
+
+		if sameSign(fa, fmid) {
+			a, fa = mid, fmid
+		} else {
+			b, fb = mid, fmid
+		}
+
+		tol1 := tol.of(mid)
+		if tol1.IsZero() { // mid is 0 and tolerance is relative
+			tol1 = decnum.SmallestSubnormal()
+		}
+
+		width := b.Sub(a).Abs()
+		if width.LessEqual(tol1) {
+			return newResult(a.Add(b).Mul(g_half), i, width), nil
+		}
+	}
+
+	return newResult(a.Add(b).Mul(g_half), tol.maxIter, b.Sub(a)), NoConvergence
+}
+
+// Newton finds a root of f, by the Newton-Raphson method, starting from x0.
+//
+// df is the derivative of f. If df is nil, the derivative is estimated by a central difference.
+//
+// The method stops when the Newton step is smaller than the tolerance. The step is returned as the achieved precision.
+// The method is fast when x0 is near a simple root, but may diverge else.
+//
+func Newton(f Func, df Func, x0 decnum.Quad, opts Options) (Result, error) {
+	var (
+		tol tolerance
+		err error
+	)
+
+	if tol, err = newTolerance(opts); err != nil {
+		return Result{Root: x0}, err
+	}
+
+	if !valid(x0) {
+		return Result{Root: x0}, InvalidValue
+	}
+
+	if df == nil {
+		df = centralDifference(f)
+	}
+
+	x := x0
+	step := decnum.Zero()
+
+	for i := 1; i <= tol.maxIter; i++ {
+		fx, err := eval(f, x)
+		if err != nil {
+			return newResult(x, i, step), err
+		}
+
+		if fx.IsZero() {
+			return newResult(x, i, decnum.Zero()), nil
+		}
+
+		dfx, err := eval(df, x)
+		if err != nil {
+			return newResult(x, i, step), err
+		}
+
+		if dfx.IsZero() {
+			return newResult(x, i, step), ZeroDerivative
+		}
+
+		step = fx.Div(dfx)
+		xnext := x.Sub(step)
+
+		if !valid(xnext) {
+			return newResult(x, i, step), InvalidValue
+		}
+
+		if step.Abs().LessEqual(tol.of(xnext)) || xnext.Equal(x) {
+			return newResult(xnext, i, step), nil
+		}
+
+		x = xnext
+	}
+
+	return newResult(x, tol.maxIter, step), NoConvergence
+}
+
+// centralDifference returns a function estimating the derivative of f, by (f(x+h) - f(x-h)) / 2h.
+//
+func centralDifference(f Func) Func {
+	var (
+		h0 = decnum.MustParse("1E-17")
+	)
+
+	return func(x decnum.Quad) decnum.Quad {
+		h := x.Abs().Mul(h0)
+		if h.IsZero() {
+			h = h0
+		}
+
+		return f(x.Add(h)).Sub(f(x.Sub(h))).Div(h.Mul(g_two))
+	}
+}
+
+// Brent finds a root of f in the interval [a, b], by Brent's method.
+//
+// f(a) and f(b) must have opposite signs, or be 0.
+// The method combines bisection, secant and inverse quadratic interpolation. It always converges, and is usually much faster than bisection.
+//
+// The achieved precision is the width of the final interval containing the root.
+//
+func Brent(f Func, a decnum.Quad, b decnum.Quad, opts Options) (Result, error) {
+	var (
+		tol tolerance
+		fa  decnum.Quad
+		fb  decnum.Quad
+		err error
+	)
+
+	if tol, err = newTolerance(opts); err != nil {
+		return Result{Root: a}, err
+	}
+
+	if fa, fb, err = checkInterval(f, a, b); err != nil {
+		return Result{Root: a}, err
+	}
+
+	if fa.IsZero() {
+		return newResult(a, 0, decnum.Zero()), nil
+	}
+	if fb.IsZero() {
+		return newResult(b, 0, decnum.Zero()), nil
+	}
+
+	// the root is always between b and c. b is the best approximation, a is the previous value of b.
+
+	var (
+		one   = decnum.One()
+		three = decnum.MustParse("3")
+		c     = b
+		fc    = fb
+		d     = b.Sub(a) // last step
+		e     = d        // step before last step
+	)
+
+	for i := 1; i <= tol.maxIter; i++ {
+		if sameSign(fb, fc) {
+			c, fc = a, fa
+			d = b.Sub(a)
+			e = d
+		}
+
+		if fc.Abs().Less(fb.Abs()) {
+			a, b, c = b, c, b
+			fa, fb, fc = fb, fc, fb
+		}
+
+		tol1 := decnum.Epsilon().Mul(b.Abs()).Mul(g_two).Add(tol.of(b).Mul(g_half))
+		if tol1.IsZero() { // b is 0 and tolerance is relative
+			tol1 = decnum.SmallestSubnormal()
+		}
+		xm := c.Sub(b).Mul(g_half)
+
+		if xm.Abs().LessEqual(tol1) || fb.IsZero() {
+			if fb.IsZero() {
+				return newResult(b, i, decnum.Zero()), nil
+			}
+			return newResult(b, i, c.Sub(b)), nil
+		}
+
+		if e.Abs().GreaterEqual(tol1) && fa.Abs().Greater(fb.Abs()) { // try interpolation
+			var p, q decnum.Quad
+
+			s := fb.Div(fa)
+
+			if a.Equal(c) { // secant method
+				p = g_two.Mul(xm).Mul(s)
+				q = one.Sub(s)
+			} else { // inverse quadratic interpolation
+				q = fa.Div(fc)
+				r := fb.Div(fc)
+				p = s.Mul(g_two.Mul(xm).Mul(q).Mul(q.Sub(r)).Sub(b.Sub(a).Mul(r.Sub(one))))
+				q = q.Sub(one).Mul(r.Sub(one)).Mul(s.Sub(one))
+			}
+
+			if p.IsPositive() {
+				q = q.Neg()
+			}
+			p = p.Abs()
+
+			min1 := three.Mul(xm).Mul(q).Sub(tol1.Mul(q).Abs())
+			min2 := e.Mul(q).Abs()
+
+			if g_two.Mul(p).Less(decnum.Min(min1, min2)) { // accept interpolation
+				e = d
+				d = p.Div(q)
+			} else { // interpolation failed, use bisection
+				d = xm
+				e = d
+			}
+		} else { // bounds decreasing too slowly, use bisection
+			d = xm
+			e = d
+		}
+
+		a, fa = b, fb
+
+		if d.Abs().Greater(tol1) {
+			b = b.Add(d)
+		} else if xm.IsNegative() {
+			b = b.Sub(tol1)
+		} else {
+			b = b.Add(tol1)
+		}
+
+		if fb, err = eval(f, b); err != nil {
+			return newResult(b, i, c.Sub(b)), err
+		}
+	}
+
+	return newResult(b, tol.maxIter, c.Sub(b)), NoConvergence
+}
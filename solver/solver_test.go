@@ -0,0 +1,169 @@
+package solver
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/rin01/decnum"
+)
+
+var (
+	sqrt2 = "1.414213562373095048801688724209698"
+	pi    = "3.141592653589793238462643383279503"
+)
+
+// x*x - 2, root is Sqrt(2).
+//
+func square_minus_2(x decnum.Quad) decnum.Quad {
+
+	return x.Mul(x).Sub(decnum.MustParse("2"))
+}
+
+// near checks that a and b have the same first n significant digits.
+//
+func near(a decnum.Quad, b string, n int) bool {
+
+	bq := decnum.MustParse(b)
+	diff := a.Sub(bq).Abs()
+
+	return diff.LessEqual(bq.Abs().Mul(decnum.MustParse("1E-" + strconv.Itoa(n))))
+}
+
+func Test_methods(t *testing.T) {
+
+	samples := []struct {
+		name     string
+		f        Func
+		a        string
+		b        string
+		expected string
+	}{
+		{"sqrt2", square_minus_2, "0", "2", sqrt2},
+		{"sin", decnum.Quad.Sin, "3", "4", pi},
+		{"cos", decnum.Quad.Cos, "1", "2", "1.570796326794896619231321691639751"},
+		{"cubic", func(x decnum.Quad) decnum.Quad { return x.Mul(x).Mul(x).Sub(decnum.MustParse("27")) }, "-10", "10", "3"},
+	}
+
+	for _, sp := range samples {
+		a, b := decnum.MustParse(sp.a), decnum.MustParse(sp.b)
+
+		for _, m := range []struct {
+			name  string
+			solve func() (Result, error)
+		}{
+			{"Bisection", func() (Result, error) { return Bisection(sp.f, a, b, Options{}) }},
+			{"Brent", func() (Result, error) { return Brent(sp.f, a, b, Options{}) }},
+			{"Newton", func() (Result, error) { return Newton(sp.f, nil, b, Options{}) }},
+		} {
+			res, err := m.solve()
+			if err != nil {
+				t.Fatalf("%s %s: %s", m.name, sp.name, err)
+			}
+
+			if !near(res.Root, sp.expected, DefaultDigits-1) {
+				t.Fatalf("%s %s: root %s != %s (expected)", m.name, sp.name, res.Root, sp.expected)
+			}
+
+			if res.Iterations <= 0 && !res.Precision.IsZero() {
+				t.Fatalf("%s %s: %d iterations", m.name, sp.name, res.Iterations)
+			}
+
+			if res.Digits < DefaultDigits-1 {
+				t.Fatalf("%s %s: %d digits, precision %s", m.name, sp.name, res.Digits, res.Precision)
+			}
+		}
+	}
+}
+
+func Test_options(t *testing.T) {
+
+	two := decnum.MustParse("2")
+	derivative := func(x decnum.Quad) decnum.Quad { return x.Mul(two) }
+
+	// digits
+
+	res, err := Brent(square_minus_2, decnum.One(), two, Options{Digits: 10})
+	if err != nil || !near(res.Root, sqrt2, 10) {
+		t.Fatalf("Brent, 10 digits: %s %v", res.Root, err)
+	}
+
+	// absolute tolerance
+
+	res, err = Bisection(square_minus_2, decnum.One(), two, Options{Absolute: decnum.MustParse("1E-5")})
+	if err != nil || !near(res.Root, sqrt2, 5) || res.Precision.Greater(decnum.MustParse("1E-5")) {
+		t.Fatalf("Bisection, absolute 1E-5: %s %s %v", res.Root, res.Precision, err)
+	}
+
+	// Newton with derivative is faster than bisection
+
+	res2, err := Newton(square_minus_2, derivative, two, Options{})
+	if err != nil || !near(res2.Root, sqrt2, DefaultDigits) || res2.Iterations > 10 {
+		t.Fatalf("Newton: %s %d %v", res2.Root, res2.Iterations, err)
+	}
+
+	// negative root
+
+	res, err = Brent(square_minus_2, decnum.MustParse("-2"), decnum.MustParse("0.5"), Options{})
+	if err != nil || !near(res.Root, "-"+sqrt2, DefaultDigits-1) {
+		t.Fatalf("Brent, negative root: %s %v", res.Root, err)
+	}
+
+	// exact root
+
+	res, err = Bisection(decnum.Quad.Sin, decnum.MustParse("-1"), decnum.MustParse("1"), Options{})
+	if err != nil || !res.Root.IsZero() || !res.Precision.IsZero() || res.Digits != decnum.DecquadPmax {
+		t.Fatalf("Bisection, exact root: %s %s %d %v", res.Root, res.Precision, res.Digits, err)
+	}
+
+	// root at 0, which is not the middle of the interval
+
+	three := decnum.MustParse("3")
+
+	for _, m := range []struct {
+		name  string
+		solve func(f Func, a decnum.Quad, b decnum.Quad, opts Options) (Result, error)
+	}{
+		{"Bisection", Bisection},
+		{"Brent", Brent},
+	} {
+		res, err = m.solve(func(x decnum.Quad) decnum.Quad { return x.Mul(three) }, decnum.MustParse("-1"), decnum.MustParse("2.5"), Options{})
+		if err != nil || !res.Root.IsZero() {
+			t.Fatalf("%s, root at 0: %s %s %d %v", m.name, res.Root, res.Precision, res.Iterations, err)
+		}
+
+		res, err = m.solve(func(x decnum.Quad) decnum.Quad { return x.Sub(decnum.MustParse("1E-20")) }, decnum.MustParse("-1"), decnum.MustParse("2.5"), Options{})
+		if err != nil || !near(res.Root, "1E-20", DefaultDigits-1) {
+			t.Fatalf("%s, root near 0: %s %s %d %v", m.name, res.Root, res.Precision, res.Iterations, err)
+		}
+	}
+}
+
+func Test_errors(t *testing.T) {
+
+	two := decnum.MustParse("2")
+
+	samples := []struct {
+		name     string
+		solve    func() (Result, error)
+		expected SolverError
+	}{
+		{"not bracketed", func() (Result, error) { return Bisection(square_minus_2, two, decnum.MustParse("3"), Options{}) }, NotBracketed},
+		{"not bracketed", func() (Result, error) { return Brent(square_minus_2, two, decnum.MustParse("3"), Options{}) }, NotBracketed},
+		{"no convergence", func() (Result, error) { return Bisection(square_minus_2, decnum.One(), two, Options{MaxIterations: 5}) }, NoConvergence},
+		{"no convergence", func() (Result, error) { return Brent(square_minus_2, decnum.One(), two, Options{MaxIterations: 2}) }, NoConvergence},
+		{"zero derivative", func() (Result, error) { return Newton(square_minus_2, nil, decnum.Zero(), Options{}) }, ZeroDerivative},
+		{"invalid value", func() (Result, error) { return Newton(decnum.Quad.Asin, nil, two, Options{}) }, InvalidValue},
+		{"invalid value", func() (Result, error) { return Brent(square_minus_2, decnum.NaN(), two, Options{}) }, InvalidValue},
+		{"invalid tolerance", func() (Result, error) { return Brent(square_minus_2, decnum.One(), two, Options{Digits: 40}) }, InvalidTolerance},
+		{"invalid tolerance", func() (Result, error) {
+			return Newton(square_minus_2, nil, two, Options{Absolute: decnum.MustParse("-1E-5")})
+		}, InvalidTolerance},
+	}
+
+	for i, sp := range samples {
+		_, err := sp.solve()
+		if err != sp.expected {
+			t.Fatalf("sample %d, %s: error %v != %v (expected)", i, sp.name, err, sp.expected)
+		}
+	}
+}